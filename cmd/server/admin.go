@@ -0,0 +1,151 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/intelligence"
+	"github.com/chainzero/akash-provider-intelligence/internal/telemetry"
+	"github.com/gorilla/mux"
+)
+
+// Middleware: admin endpoints require "Authorization: Bearer <server.admin_token>"
+func (s *MCPServer) adminMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.config.Server.AdminToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// Start a full cache rebuild; responds 202 with the job, or 409 with the running one
+func (s *MCPServer) handleStartCacheRebuild(w http.ResponseWriter, r *http.Request) {
+	job, err := s.intelligenceService.StartCacheRebuild()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/admin/cache/rebuild/%s", job.ID))
+	if errors.Is(err, intelligence.ErrRebuildRunning) {
+		w.WriteHeader(http.StatusConflict)
+	} else if errors.Is(err, intelligence.ErrServiceClosed) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		w.WriteHeader(http.StatusAccepted)
+	}
+	json.NewEncoder(w).Encode(job)
+}
+
+// Progress of a cache rebuild job
+func (s *MCPServer) handleCacheRebuildStatus(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	job, ok := s.intelligenceService.GetRebuildJob(id)
+	if !ok {
+		http.Error(w, fmt.Sprintf("no rebuild job %s", id), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+// Drop all cached provider data, GPU readings and selections
+func (s *MCPServer) handleFlushCache(w http.ResponseWriter, r *http.Request) {
+	flushed := s.intelligenceService.FlushCache()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"flushed": flushed})
+}
+
+// Drop one provider's cached data; 404 when it was not cached
+func (s *MCPServer) handleEvictProvider(w http.ResponseWriter, r *http.Request) {
+	address := mux.Vars(r)["address"]
+
+	if !s.intelligenceService.EvictProvider(address) {
+		http.Error(w, fmt.Sprintf("provider %s is not cached", address), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"evicted": address})
+}
+
+// Refetch the given providers now; for every known provider use /admin/cache/rebuild
+func (s *MCPServer) handleRefreshProviders(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Addresses []string `json:"addresses"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(request.Addresses) == 0 {
+		http.Error(w, "addresses is required", http.StatusBadRequest)
+		return
+	}
+
+	result, err := s.intelligenceService.RefreshProviders(r.Context(), request.Addresses)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	refreshed := make([]string, 0, len(result.Providers))
+	for _, provider := range result.Providers {
+		refreshed = append(refreshed, provider.Address)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"refreshed":        refreshed,
+		"failed_providers": result.FailedProviders,
+	})
+}
+
+// The config the server started with, plus the settings reloaded or changed since
+func (s *MCPServer) handleAdminConfig(w http.ResponseWriter, r *http.Request) {
+	settings := s.intelligenceService.CurrentSettings()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"config": s.config,
+		"runtime": map[string]interface{}{
+			"cache_ttl":         settings.CacheTTL.String(),
+			"selection_weights": s.weights.get(),
+			"log_level":         logLevelName(),
+		},
+	})
+}
+
+// GET reports the log level; PUT {"level": "debug"} changes it until the next config reload
+func (s *MCPServer) handleLogLevel(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPut {
+		var request struct {
+			Level string `json:"level"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if err := telemetry.SetLogLevel(request.Level); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		slog.Info("Log level changed", "level", logLevelName())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"level": logLevelName()})
+}
+
+func logLevelName() string {
+	return strings.ToLower(telemetry.LogLevel().String())
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// A client credential; the label names the client in traces and logs
+type APIKey struct {
+	Label string `yaml:"label"`
+	Key   string `yaml:"key"`
+}
+
+// Find the configured key matching key. Every key is compared in constant
+// time so the response time doesn't reveal how much of a key matched.
+func (s *MCPServer) matchAPIKey(key string) (APIKey, bool) {
+	var match APIKey
+	found := false
+	for _, candidate := range s.config.Server.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(candidate.Key)) == 1 && !found {
+			match, found = candidate, true
+		}
+	}
+	return match, found
+}
+
+// Middleware: with API keys configured, require a valid X-API-Key header.
+// Without any, the server stays open as before. Used where Authorization
+// already carries another credential, like the admin token.
+func (s *MCPServer) apiKeyMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(s.config.Server.APIKeys) == 0 {
+			next(w, r)
+			return
+		}
+
+		// Browsers can't set headers on a WebSocket handshake
+		key := r.Header.Get("X-API-Key")
+		if key == "" && r.Header.Get("Upgrade") != "" {
+			key = r.URL.Query().Get("api_key")
+		}
+		match, ok := s.matchAPIKey(key)
+		if key == "" || !ok {
+			http.Error(w, "Missing or invalid X-API-Key", http.StatusUnauthorized)
+			return
+		}
+
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("client.label", match.Label))
+		next(w, r.WithContext(withClient(r.Context(), "key:"+match.Label)))
+	}
+}
+
+// Middleware: require an API key or, with OIDC configured, a bearer JWT.
+// Without either configured, the server stays open as before. Scopes of a
+// token are checked per tool when the tool runs.
+func (s *MCPServer) authMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(s.config.Server.APIKeys) == 0 && s.jwtVerifier == nil {
+			next(w, r)
+			return
+		}
+		span := trace.SpanFromContext(r.Context())
+		upgrade := r.Header.Get("Upgrade") != ""
+
+		// Browsers can't set headers on a WebSocket handshake
+		key := r.Header.Get("X-API-Key")
+		if key == "" && upgrade {
+			key = r.URL.Query().Get("api_key")
+		}
+		if key != "" {
+			if match, ok := s.matchAPIKey(key); ok {
+				span.SetAttributes(attribute.String("client.label", match.Label))
+				next(w, r.WithContext(withClient(r.Context(), "key:"+match.Label)))
+				return
+			}
+		}
+
+		if s.jwtVerifier != nil {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok && upgrade {
+				token = r.URL.Query().Get("access_token")
+			}
+			if token != "" {
+				claims, err := s.jwtVerifier.verify(r.Context(), token)
+				if err != nil {
+					w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer error="invalid_token", error_description=%q`, err.Error()))
+					http.Error(w, fmt.Sprintf("Invalid bearer token: %v", err), http.StatusUnauthorized)
+					return
+				}
+				span.SetAttributes(attribute.String("client.subject", claims.Subject))
+				ctx := withClient(withScopes(r.Context(), claims.scopes()), "sub:"+claims.Subject)
+				next(w, r.WithContext(ctx))
+				return
+			}
+			w.Header().Set("WWW-Authenticate", "Bearer")
+		}
+
+		http.Error(w, "Missing or invalid credentials", http.StatusUnauthorized)
+	}
+}
+
+// gRPC interceptor: the checks of authMiddleware, with the credentials read
+// from x-api-key or authorization metadata
+func (s *MCPServer) grpcAuthInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if len(s.config.Server.APIKeys) == 0 && s.jwtVerifier == nil {
+		return handler(ctx, req)
+	}
+	span := trace.SpanFromContext(ctx)
+	md, _ := metadata.FromIncomingContext(ctx)
+	first := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	if key := first("x-api-key"); key != "" {
+		if match, ok := s.matchAPIKey(key); ok {
+			span.SetAttributes(attribute.String("client.label", match.Label))
+			return handler(withClient(ctx, "key:"+match.Label), req)
+		}
+	}
+
+	if s.jwtVerifier != nil {
+		if token, ok := strings.CutPrefix(first("authorization"), "Bearer "); ok && token != "" {
+			claims, err := s.jwtVerifier.verify(ctx, token)
+			if err != nil {
+				return nil, status.Errorf(codes.Unauthenticated, "invalid bearer token: %v", err)
+			}
+			span.SetAttributes(attribute.String("client.subject", claims.Subject))
+			return handler(withClient(withScopes(ctx, claims.scopes()), "sub:"+claims.Subject), req)
+		}
+	}
+
+	return nil, status.Error(codes.Unauthenticated, "missing or invalid credentials")
+}
+
+type clientKey struct{}
+
+// Record the client a credential was verified for
+func withClient(ctx context.Context, client string) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// Client a request is attributed to for per-client state, like rate limit
+// buckets: the label of its API key or the subject of its token once
+// verified, otherwise its address. Unverified credentials never count, so a
+// client can't become someone new by sending a made-up key.
+func requestClient(r *http.Request) string {
+	if client, ok := r.Context().Value(clientKey{}).(string); ok {
+		return client
+	}
+	return "ip:" + httpCallScope(r)
+}
+
+// Middleware: CORS for web clients. Any origin is allowed unless
+// server.cors_origins lists the allowed ones.
+func (s *MCPServer) corsMiddleware(next http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(s.config.Server.CORSOrigins))
+	for _, origin := range s.config.Server.CORSOrigins {
+		allowed[origin] = true
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		switch {
+		case len(allowed) == 0 || allowed["*"]:
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		case allowed[origin]:
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+		}
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Idempotency-Key, X-Request-ID, X-API-Key, Authorization")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, X-Trace-Id")
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
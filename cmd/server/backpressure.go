@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Middleware: report upstream saturation on every response so clients can back
+// off, and shed new work with 503 once it reaches the configured threshold
+func (s *MCPServer) backpressureMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		saturation := s.intelligenceService.Saturation()
+		w.Header().Set("X-Upstream-Saturation", fmt.Sprintf("%.2f", saturation))
+
+		threshold := s.config.Server.SaturationThreshold
+		if threshold > 0 && saturation >= threshold {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, fmt.Sprintf("Upstream saturated (%.2f >= %.2f), retry later", saturation, threshold),
+				http.StatusServiceUnavailable)
+			return
+		}
+
+		next(w, r)
+	}
+}
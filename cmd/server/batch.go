@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+)
+
+const (
+	// Most tool calls in one batch, for /call/batch and MCP batches alike
+	maxBatchCalls = 20
+
+	defaultBatchConcurrency = 4
+)
+
+// One invocation in a /call/batch request
+type batchCall struct {
+	Tool      string                 `json:"tool"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+// Outcome of one batched call: the /call response body on success, otherwise
+// the status and error /call would have returned
+type batchCallResult struct {
+	Tool              string                   `json:"tool"`
+	Status            int                      `json:"status"`
+	Content           []map[string]interface{} `json:"content,omitempty"`
+	StructuredContent json.RawMessage          `json:"structuredContent,omitempty"`
+	Error             string                   `json:"error,omitempty"`
+}
+
+// Calls of one batch run at the same time
+func (s *MCPServer) batchConcurrency() int {
+	if s.config.Server.BatchConcurrency > 0 {
+		return s.config.Server.BatchConcurrency
+	}
+	return defaultBatchConcurrency
+}
+
+// Run fn for indexes 0..n-1, at most limit at a time
+func runBounded(n, limit int, fn func(i int)) {
+	slots := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			fn(i)
+		}()
+	}
+	wg.Wait()
+}
+
+// Fetch every provider named by the batch's calls once, up front, so calls
+// that share providers are served from the cache instead of each querying them.
+// Failures are left for the calls themselves to report.
+func (s *MCPServer) prefetchBatch(ctx context.Context, args []map[string]interface{}) {
+	if len(args) < 2 {
+		return
+	}
+
+	seen := make(map[string]bool)
+	var addresses []string
+	for _, arguments := range args {
+		list, _ := stringListArg(arguments, "provider_addresses")
+		for _, address := range list {
+			if address != "" && !seen[address] {
+				seen[address] = true
+				addresses = append(addresses, address)
+			}
+		}
+	}
+	if len(addresses) == 0 {
+		return
+	}
+
+	if _, err := s.intelligenceService.GetProviderIntelligence(ctx, addresses); err != nil {
+		slog.WarnContext(ctx, "Batch prefetch failed", "providers", len(addresses), "error", err)
+	}
+}
+
+// POST /call/batch - run several tool calls in one round trip, returning
+// per-call results in request order
+func (s *MCPServer) handleBatchToolCall(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Calls []batchCall `json:"calls"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(request.Calls) == 0 {
+		http.Error(w, "calls must not be empty", http.StatusBadRequest)
+		return
+	}
+	if len(request.Calls) > maxBatchCalls {
+		http.Error(w, fmt.Sprintf("Too many calls in batch: %d (max %d)", len(request.Calls), maxBatchCalls),
+			http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// The rate limit counts calls, not requests; the middleware took the first
+	if s.rejectOverRateLimit(w, r, len(request.Calls)-1) {
+		return
+	}
+
+	ctx := r.Context()
+
+	args := make([]map[string]interface{}, len(request.Calls))
+	for i, call := range request.Calls {
+		args[i] = call.Arguments
+	}
+	s.prefetchBatch(ctx, args)
+
+	results := make([]batchCallResult, len(request.Calls))
+	runBounded(len(request.Calls), s.batchConcurrency(), func(i int) {
+		results[i] = s.runBatchCall(ctx, request.Calls[i])
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"results": results,
+	})
+}
+
+// Run one batched call under the same per-tool limits as /call
+func (s *MCPServer) runBatchCall(ctx context.Context, call batchCall) batchCallResult {
+	result := batchCallResult{Tool: call.Tool}
+
+	if !s.toolLimits.acquire(call.Tool) {
+		result.Status = http.StatusServiceUnavailable
+		result.Error = fmt.Sprintf("Tool %s is at its concurrency limit (%d), retry later", call.Tool, s.toolLimits.limits[call.Tool])
+		return result
+	}
+	defer s.toolLimits.release(call.Tool)
+
+	response, err := s.callTool(ctx, call.Tool, call.Arguments)
+	if errors.Is(err, errUnknownTool) {
+		result.Status = http.StatusBadRequest
+		result.Error = fmt.Sprintf("Unknown tool: %s", call.Tool)
+		return result
+	}
+	if errors.Is(err, errResultTooLarge) {
+		result.Status = http.StatusUnprocessableEntity
+		result.Error = err.Error()
+		return result
+	}
+	if errors.Is(err, errToolForbidden) {
+		result.Status = http.StatusForbidden
+		result.Error = err.Error()
+		return result
+	}
+	if err != nil {
+		result.Status = http.StatusInternalServerError
+		result.Error = err.Error()
+		return result
+	}
+
+	structured, err := structuredContent(response)
+	if err != nil {
+		result.Status = http.StatusInternalServerError
+		result.Error = fmt.Sprintf("Failed to encode tool result: %v", err)
+		return result
+	}
+
+	result.Status = http.StatusOK
+	result.Content = []map[string]interface{}{
+		{
+			"type": "text",
+			"text": response,
+		},
+	}
+	result.StructuredContent = structured
+	return result
+}
+
+// Handle a JSON-RPC batch, answering its requests in order; notifications get
+// no entry, so a batch of only notifications has no responses. An empty or
+// unparseable batch is answered with a single error instead, as is one admit
+// refuses given its number of tool calls; a nil admit runs every batch.
+func (s *MCPServer) handleRPCBatch(ctx context.Context, data []byte, admit func(toolCalls int) *rpcResponse) ([]*rpcResponse, *rpcResponse) {
+	var messages []json.RawMessage
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil, rpcFailure(nil, rpcParseError, "parse error: %v", err)
+	}
+	if len(messages) == 0 {
+		return nil, rpcFailure(nil, rpcInvalidRequest, "invalid request: empty batch")
+	}
+	if len(messages) > maxBatchCalls {
+		return nil, rpcFailure(nil, rpcInvalidRequest, "invalid request: batch of %d messages exceeds %d", len(messages), maxBatchCalls)
+	}
+
+	requests := make([]*rpcRequest, len(messages))
+	responses := make([]*rpcResponse, len(messages))
+	var args []map[string]interface{}
+	toolCalls := 0
+	for i, message := range messages {
+		requests[i], responses[i] = parseRPCRequest(message)
+		if requests[i] != nil && requests[i].Method == "tools/call" {
+			toolCalls++
+			var params struct {
+				Arguments map[string]interface{} `json:"arguments"`
+			}
+			if json.Unmarshal(requests[i].Params, &params) == nil {
+				args = append(args, params.Arguments)
+			}
+		}
+	}
+	if admit != nil {
+		if failure := admit(toolCalls); failure != nil {
+			return nil, failure
+		}
+	}
+	s.prefetchBatch(ctx, args)
+
+	runBounded(len(requests), s.batchConcurrency(), func(i int) {
+		if requests[i] != nil {
+			responses[i] = s.handleRPC(ctx, requests[i])
+		}
+	})
+
+	answered := make([]*rpcResponse, 0, len(responses))
+	for _, response := range responses {
+		if response != nil {
+			answered = append(answered, response)
+		}
+	}
+	return answered, nil
+}
+
+// Whether a raw JSON-RPC message is a batch
+func isRPCBatch(data []byte) bool {
+	for _, c := range data {
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case '[':
+			return true
+		}
+		return false
+	}
+	return false
+}
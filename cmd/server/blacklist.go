@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// List permanent blacklist entries and active temporary bans
+func (s *MCPServer) handleGetBlacklist(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.intelligenceService.GetBlacklistStatus())
+}
+
+// Temporarily ban a provider: {"address": "akash1...", "ttl": "4h", "reason": "..."}
+func (s *MCPServer) handleBanProvider(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Address string `json:"address"`
+		TTL     string `json:"ttl"`
+		Reason  string `json:"reason"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if request.Address == "" {
+		http.Error(w, "address is required", http.StatusBadRequest)
+		return
+	}
+
+	ttl, err := time.ParseDuration(request.TTL)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid ttl %q: %v", request.TTL, err), http.StatusBadRequest)
+		return
+	}
+
+	entry, err := s.intelligenceService.BanProvider(request.Address, ttl, request.Reason)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
+
+// Lift a temporary ban
+func (s *MCPServer) handleUnbanProvider(w http.ResponseWriter, r *http.Request) {
+	address := mux.Vars(r)["address"]
+
+	if err := s.intelligenceService.UnbanProvider(address); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+const defaultCacheExpiryLimit = 10
+
+// GET /cache/expiry?limit=N - remaining TTL histogram and the N soonest-to-expire entries
+func (s *MCPServer) handleCacheExpiry(w http.ResponseWriter, r *http.Request) {
+	limit := defaultCacheExpiryLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			http.Error(w, "limit must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.intelligenceService.GetCacheExpiry(limit))
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// Cause of a request stopped by the client's notifications/cancelled
+var errCancelledByClient = errors.New("cancelled by client")
+
+// In-flight MCP requests, so notifications/cancelled can stop them. Request IDs
+// are only unique per client, so each is keyed with the client's scope.
+type rpcCalls struct {
+	cancels map[string]context.CancelCauseFunc
+	mutex   sync.Mutex
+}
+
+func newRPCCalls() *rpcCalls {
+	return &rpcCalls{cancels: make(map[string]context.CancelCauseFunc)}
+}
+
+type callScopeKey struct{}
+
+// Scope request IDs to one client: the stdio peer, or a remote address over HTTP
+func withCallScope(ctx context.Context, scope string) context.Context {
+	return context.WithValue(ctx, callScopeKey{}, scope)
+}
+
+func httpCallScope(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func callKey(ctx context.Context, id json.RawMessage) string {
+	scope, _ := ctx.Value(callScopeKey{}).(string)
+	return scope + " " + string(bytes.TrimSpace(id))
+}
+
+// Track a request until the returned finish is called
+func (c *rpcCalls) start(ctx context.Context, id json.RawMessage) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	key := callKey(ctx, id)
+
+	c.mutex.Lock()
+	c.cancels[key] = cancel
+	c.mutex.Unlock()
+
+	return ctx, func() {
+		c.mutex.Lock()
+		delete(c.cancels, key)
+		c.mutex.Unlock()
+		cancel(nil)
+	}
+}
+
+// Stop a request of this client; unknown or finished requests are ignored, as
+// the cancellation may simply have raced the response
+func (c *rpcCalls) cancel(ctx context.Context, id json.RawMessage) {
+	c.mutex.Lock()
+	cancel, ok := c.cancels[callKey(ctx, id)]
+	c.mutex.Unlock()
+
+	if ok {
+		cancel(errCancelledByClient)
+	}
+}
+
+// Handle notifications/cancelled
+func (s *MCPServer) rpcCancel(ctx context.Context, request *rpcRequest) {
+	var params struct {
+		RequestID json.RawMessage `json:"requestId"`
+	}
+	if err := json.Unmarshal(request.Params, &params); err != nil || len(params.RequestID) == 0 {
+		return
+	}
+	s.calls.cancel(ctx, params.RequestID)
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// check subcommand: query a list of providers once, print the results and exit.
+// Exits 1 if any provider could not be fetched, so it can gate scripts and CI.
+func runCheck(args []string) int {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	configPath := flags.String("config", "config.yaml", "Path to configuration file")
+	file := flags.String("file", "", "File with one provider address per line (# starts a comment)")
+	asJSON := flags.Bool("json", false, "Print results as JSON instead of a table")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s check [flags] [address ...]\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 2
+	}
+
+	addresses := flags.Args()
+	if *file != "" {
+		fromFile, err := readAddressFile(*file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read %s: %v\n", *file, err)
+			return 2
+		}
+		addresses = append(addresses, fromFile...)
+	}
+	if len(addresses) == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	client, err := akash.NewClient(&akash.Config{
+		GRPCEndpoints:   akashGRPCEndpoints(config),
+		RPCEndpoint:     config.Akash.RPCEndpoint,
+		GRPCSecurity:    akashGRPCSecurity(config),
+		StatusRetry:     statusRetryPolicy(config),
+		ChainRetry:      chainRetryPolicy(config),
+		MaxConcurrent:   config.Intelligence.MaxConcurrent,
+		StatusTimeout:   config.Intelligence.StatusTimeout,
+		ChainTimeout:    config.Intelligence.ChainTimeout,
+		ProviderTimeout: config.Intelligence.ProviderTimeout,
+		BatchTimeout:    config.Intelligence.BatchTimeout,
+		ResourceLimits: akash.ResourceLimits{
+			MaxNodeCPU:     config.Intelligence.ResourceLimits.MaxNodeCPU,
+			MaxNodeMemory:  config.Intelligence.ResourceLimits.MaxNodeMemory,
+			MaxNodeStorage: config.Intelligence.ResourceLimits.MaxNodeStorage,
+			MaxNodeGPU:     config.Intelligence.ResourceLimits.MaxNodeGPU,
+		},
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to set up Akash client: %v\n", err)
+		return 2
+	}
+	defer client.Close()
+
+	providers, failed, err := client.GetMultipleProviderInfo(context.Background(), addresses)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Check failed: %v\n", err)
+		return 2
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(map[string]interface{}{
+			"providers":        providers,
+			"failed_providers": failed,
+		})
+	} else {
+		printCheckTable(providers, failed)
+	}
+
+	if len(failed) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// Read provider addresses from a file, one per line
+func readAddressFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var addresses []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			addresses = append(addresses, line)
+		}
+	}
+	return addresses, scanner.Err()
+}
+
+// Print one row per provider, failed providers last
+func printCheckTable(providers []*akash.ProviderInfo, failed []*akash.FailedProvider) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ADDRESS\tHEALTH\tLATENCY\tLEASES\tNODES\tERROR")
+
+	for _, provider := range providers {
+		leases, nodes := "-", "-"
+		if provider.ClusterInfo != nil {
+			leases = fmt.Sprintf("%d", provider.ClusterInfo.ActiveLeases)
+			nodes = fmt.Sprintf("%d", provider.ClusterInfo.AvailableNodes)
+		}
+		latency := "-"
+		if provider.StatusQueryTime > 0 {
+			latency = provider.StatusQueryTime.Round(time.Millisecond).String()
+		}
+		fmt.Fprintf(w, "%s\t%.2f\t%s\t%s\t%s\t%s\n",
+			provider.Address, provider.HealthScore, latency, leases, nodes, provider.Error)
+	}
+
+	for _, failure := range failed {
+		fmt.Fprintf(w, "%s\t-\t-\t-\t-\t%s: %s\n", failure.Address, failure.Category, failure.Error)
+	}
+
+	w.Flush()
+}
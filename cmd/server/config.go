@@ -0,0 +1,309 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+	"github.com/chainzero/akash-provider-intelligence/internal/intelligence"
+	"github.com/chainzero/akash-provider-intelligence/internal/telemetry"
+)
+
+// Prefix of environment variables that override config file settings, e.g.
+// APIS_AKASH_GRPC_ENDPOINT for akash.grpc_endpoint
+const envPrefix = "APIS"
+
+// Settings used when the config file leaves them out. The file is decoded
+// over these, so a value set explicitly (even to zero) is still validated.
+func defaultConfig() *Config {
+	config := &Config{}
+	config.Server.Port = 8080
+	config.Server.Host = "0.0.0.0"
+	config.Server.Timeout = 30 * time.Second
+	config.Akash.ChainID = "akashnet-2"
+	config.Intelligence.CacheTTL = 5 * time.Minute
+	config.Intelligence.StatusTimeout = 5 * time.Second
+	config.Intelligence.ChainTimeout = 5 * time.Second
+	config.Intelligence.ProviderTimeout = 10 * time.Second
+	config.Intelligence.BatchTimeout = 15 * time.Second
+	config.Intelligence.MaxConcurrent = 10
+	config.Intelligence.HealthCheckInterval = 2 * time.Minute
+	config.Intelligence.StatusRetryAttempts = 3
+	config.Intelligence.StatusRetryBackoff = 200 * time.Millisecond
+	config.Intelligence.StatusRetryMax = 2 * time.Second
+	config.Intelligence.StatusAttemptTimeout = 2 * time.Second
+	config.Intelligence.ChainRetryAttempts = 3
+	config.Intelligence.ChainRetryBackoff = 200 * time.Millisecond
+	config.Intelligence.ChainRetryMax = 2 * time.Second
+	config.Intelligence.ChainAttemptTimeout = 3 * time.Second
+	config.Intelligence.RetryJitter = 0.5
+	config.Intelligence.CircuitBreaker.FailureThreshold = 5
+	config.Intelligence.CircuitBreaker.Cooldown = 2 * time.Minute
+	config.Logging.Level = "info"
+	config.Logging.Format = "json"
+	return config
+}
+
+// Override config values from APIS_* environment variables. Each scalar
+// setting is named by its yaml path in upper case with "_" for "." and list
+// settings take comma separated values.
+func applyEnvOverrides(config *Config) error {
+	return overrideFromEnv(reflect.ValueOf(config).Elem(), envPrefix)
+}
+
+func overrideFromEnv(v reflect.Value, prefix string) error {
+	for i := 0; i < v.NumField(); i++ {
+		tag := strings.Split(v.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name := prefix + "_" + strings.ToUpper(tag)
+		field := v.Field(i)
+
+		if field.Kind() == reflect.Struct {
+			if err := overrideFromEnv(field, name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setFromEnv(field, value); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func setFromEnv(field reflect.Value, value string) error {
+	// Optional settings, such as selection weights
+	if field.Kind() == reflect.Ptr {
+		target := reflect.New(field.Type().Elem())
+		if err := setFromEnv(target.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(target)
+		return nil
+	}
+
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("only settable in the config file")
+		}
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("only settable in the config file")
+	}
+	return nil
+}
+
+// Reject settings the server cannot run with, naming the offending key
+func validateConfig(config *Config) error {
+	if len(akashGRPCEndpoints(config)) == 0 {
+		return fmt.Errorf("akash.grpc_endpoint or akash.grpc_endpoints is required")
+	}
+	if config.Server.Port <= 0 || config.Server.Port > 65535 {
+		return fmt.Errorf("server.port must be between 1 and 65535, got %d", config.Server.Port)
+	}
+	if config.Server.GRPCPort < 0 || config.Server.GRPCPort > 65535 {
+		return fmt.Errorf("server.grpc_port must be between 0 and 65535, got %d", config.Server.GRPCPort)
+	}
+	if config.Server.GRPCPort == config.Server.Port {
+		return fmt.Errorf("server.grpc_port must differ from server.port (%d)", config.Server.Port)
+	}
+	if endpoint := config.Akash.RPCEndpoint; endpoint != "" {
+		parsed, err := url.Parse(endpoint)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("akash.rpc_endpoint must be an http or https URL, got %q", endpoint)
+		}
+	}
+	grpcTLS, grpcAuth := config.Akash.GRPCTLS, config.Akash.GRPCAuth
+	if !grpcTLS.Enabled && (grpcTLS.CAFile != "" || grpcTLS.ServerName != "") {
+		return fmt.Errorf("akash.grpc_tls.ca_file and server_name require akash.grpc_tls.enabled")
+	}
+	if !grpcTLS.Enabled && (grpcAuth.BearerToken != "" || grpcAuth.Username != "") {
+		return fmt.Errorf("akash.grpc_auth requires akash.grpc_tls.enabled, credentials are never sent in plaintext")
+	}
+	if grpcAuth.BearerToken != "" && grpcAuth.Username != "" {
+		return fmt.Errorf("akash.grpc_auth: set bearer_token or username, not both")
+	}
+	if config.Intelligence.MaxConcurrent <= 0 {
+		return fmt.Errorf("intelligence.max_concurrent must be positive, got %d", config.Intelligence.MaxConcurrent)
+	}
+
+	required := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"server.timeout", config.Server.Timeout},
+		{"intelligence.cache_ttl", config.Intelligence.CacheTTL},
+		{"intelligence.status_timeout", config.Intelligence.StatusTimeout},
+		{"intelligence.chain_timeout", config.Intelligence.ChainTimeout},
+		{"intelligence.provider_timeout", config.Intelligence.ProviderTimeout},
+		{"intelligence.batch_timeout", config.Intelligence.BatchTimeout},
+		{"intelligence.health_check_interval", config.Intelligence.HealthCheckInterval},
+	}
+	for _, d := range required {
+		if d.value <= 0 {
+			return fmt.Errorf("%s must be a positive duration, got %v", d.name, d.value)
+		}
+	}
+
+	// Zero disables or falls back to a default for these, but never negative
+	optional := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"server.drain_timeout", config.Server.DrainTimeout},
+		{"server.idempotency_ttl", config.Server.IdempotencyTTL},
+		{"intelligence.recommendation_cache_ttl", config.Intelligence.RecommendationTTL},
+		{"intelligence.status_retry_backoff", config.Intelligence.StatusRetryBackoff},
+		{"intelligence.status_retry_max_backoff", config.Intelligence.StatusRetryMax},
+		{"intelligence.chain_retry_backoff", config.Intelligence.ChainRetryBackoff},
+		{"intelligence.chain_retry_max_backoff", config.Intelligence.ChainRetryMax},
+		{"intelligence.status_attempt_timeout", config.Intelligence.StatusAttemptTimeout},
+		{"intelligence.chain_attempt_timeout", config.Intelligence.ChainAttemptTimeout},
+		{"intelligence.history_retention", config.Intelligence.HistoryRetention},
+		{"intelligence.network_stats_interval", config.Intelligence.NetworkStatsInterval},
+		{"intelligence.host_index_refresh", config.Intelligence.HostIndexRefresh},
+		{"intelligence.leaderboard_interval", config.Intelligence.LeaderboardInterval},
+		{"intelligence.subscriptions.refresh", config.Intelligence.Subscriptions.Refresh},
+		{"intelligence.gpu.cache_ttl", config.Intelligence.GPU.CacheTTL},
+		{"intelligence.gpu.timeout", config.Intelligence.GPU.Timeout},
+	}
+	for _, d := range optional {
+		if d.value < 0 {
+			return fmt.Errorf("%s must not be negative, got %v", d.name, d.value)
+		}
+	}
+
+	// A provider's queries must fit its own timeout, and that the batch's
+	intel := config.Intelligence
+	if intel.ProviderTimeout < intel.StatusTimeout || intel.ProviderTimeout < intel.ChainTimeout {
+		return fmt.Errorf("intelligence.provider_timeout (%v) must be at least status_timeout (%v) and chain_timeout (%v)",
+			intel.ProviderTimeout, intel.StatusTimeout, intel.ChainTimeout)
+	}
+	if intel.BatchTimeout < intel.ProviderTimeout {
+		return fmt.Errorf("intelligence.batch_timeout (%v) must be at least provider_timeout (%v)", intel.BatchTimeout, intel.ProviderTimeout)
+	}
+
+	if breaker := config.Intelligence.CircuitBreaker; breaker.FailureThreshold > 0 && breaker.Cooldown <= 0 {
+		return fmt.Errorf("intelligence.circuit_breaker.cooldown must be a positive duration, got %v", breaker.Cooldown)
+	}
+	if jitter := config.Intelligence.RetryJitter; jitter < 0 || jitter > 1 {
+		return fmt.Errorf("intelligence.retry_jitter must be between 0 and 1, got %v", jitter)
+	}
+
+	if _, err := telemetry.ParseLogLevel(config.Logging.Level); err != nil {
+		return fmt.Errorf("logging.level: %w", err)
+	}
+	switch config.Logging.Format {
+	case telemetry.LogFormatJSON, telemetry.LogFormatText:
+	default:
+		return fmt.Errorf("logging.format must be %s or %s, got %q", telemetry.LogFormatJSON, telemetry.LogFormatText, config.Logging.Format)
+	}
+
+	weights, _, err := intelligence.ResolveWeights(configuredWeights(config), nil)
+	if err != nil {
+		return fmt.Errorf("invalid selection_weights: %w", err)
+	}
+	if weights.Price+weights.Reliability+weights.Performance+weights.Geographic <= 0 {
+		return fmt.Errorf("selection_weights: price, reliability, performance and geographic are all zero, so every provider would score the same")
+	}
+
+	return nil
+}
+
+// TLS and credentials for the Akash gRPC endpoint
+func akashGRPCSecurity(config *Config) akash.GRPCSecurity {
+	return akash.GRPCSecurity{
+		TLS:         config.Akash.GRPCTLS.Enabled,
+		CAFile:      config.Akash.GRPCTLS.CAFile,
+		ServerName:  config.Akash.GRPCTLS.ServerName,
+		BearerToken: config.Akash.GRPCAuth.BearerToken,
+		Username:    config.Akash.GRPCAuth.Username,
+		Password:    config.Akash.GRPCAuth.Password,
+	}
+}
+
+// Every configured Akash gRPC endpoint, grpc_endpoint first, without blanks or
+// duplicates
+func akashGRPCEndpoints(config *Config) []string {
+	var endpoints []string
+	seen := make(map[string]bool)
+	for _, endpoint := range append([]string{config.Akash.GRPCEndpoint}, config.Akash.GRPCEndpoints...) {
+		endpoint = strings.TrimSpace(endpoint)
+		if endpoint == "" || seen[endpoint] {
+			continue
+		}
+		seen[endpoint] = true
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
+}
+
+// Retries of provider status queries
+func statusRetryPolicy(config *Config) akash.RetryPolicy {
+	return akash.RetryPolicy{
+		Attempts:       config.Intelligence.StatusRetryAttempts,
+		Backoff:        config.Intelligence.StatusRetryBackoff,
+		MaxBackoff:     config.Intelligence.StatusRetryMax,
+		Jitter:         config.Intelligence.RetryJitter,
+		AttemptTimeout: config.Intelligence.StatusAttemptTimeout,
+	}
+}
+
+// Retries of chain queries, over gRPC or the RPC fallback
+func chainRetryPolicy(config *Config) akash.RetryPolicy {
+	return akash.RetryPolicy{
+		Attempts:       config.Intelligence.ChainRetryAttempts,
+		Backoff:        config.Intelligence.ChainRetryBackoff,
+		MaxBackoff:     config.Intelligence.ChainRetryMax,
+		Jitter:         config.Intelligence.RetryJitter,
+		AttemptTimeout: config.Intelligence.ChainAttemptTimeout,
+	}
+}
@@ -0,0 +1,91 @@
+//go:build failureinjection
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/intelligence"
+	"github.com/gorilla/mux"
+)
+
+// Install failure injection when debug.failure_injection is enabled. This file
+// is only compiled with -tags failureinjection, so release builds can't enable it.
+func (s *MCPServer) setupFailureInjection() error {
+	cfg := s.config.Debug.FailureInjection
+	if !cfg.Enabled {
+		return nil
+	}
+
+	injector := intelligence.NewFailureInjector()
+	for _, fault := range cfg.Faults {
+		if err := injector.Set(fault); err != nil {
+			return fmt.Errorf("invalid debug fault: %w", err)
+		}
+	}
+	s.intelligenceService.EnableFailureInjection(injector)
+
+	s.router.HandleFunc("/debug/faults", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(injector.List())
+	}).Methods("GET")
+
+	// Force a failure: {"address": "akash1...", "kind": "timeout", "delay": "10s"}
+	s.router.HandleFunc("/debug/faults", func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Address  string `json:"address"`
+			Kind     string `json:"kind"`
+			Delay    string `json:"delay"`
+			StaleAge string `json:"stale_age"`
+			Message  string `json:"message"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		fault := intelligence.Fault{Address: request.Address, Kind: request.Kind, Message: request.Message}
+		var err error
+		if request.Delay != "" {
+			if fault.Delay, err = parseFlexibleDuration(request.Delay); err != nil {
+				http.Error(w, fmt.Sprintf("invalid delay %q: %v", request.Delay, err), http.StatusBadRequest)
+				return
+			}
+		}
+		if request.StaleAge != "" {
+			if fault.StaleAge, err = parseFlexibleDuration(request.StaleAge); err != nil {
+				http.Error(w, fmt.Sprintf("invalid stale_age %q: %v", request.StaleAge, err), http.StatusBadRequest)
+				return
+			}
+		}
+		if err := injector.Set(fault); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(fault)
+	}).Methods("POST")
+
+	s.router.HandleFunc("/debug/faults/{address}", func(w http.ResponseWriter, r *http.Request) {
+		address := mux.Vars(r)["address"]
+		if !injector.Clear(address) {
+			http.Error(w, fmt.Sprintf("no fault configured for %s", address), http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods("DELETE")
+
+	// Every response says it may be synthetic
+	s.router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Failure-Injection", "enabled")
+			next.ServeHTTP(w, r)
+		})
+	})
+
+	return nil
+}
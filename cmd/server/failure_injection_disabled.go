@@ -0,0 +1,14 @@
+//go:build !failureinjection
+
+package main
+
+import "fmt"
+
+// Release builds carry no failure injection; enabling it in config is an error
+// rather than something silently ignored.
+func (s *MCPServer) setupFailureInjection() error {
+	if s.config.Debug.FailureInjection.Enabled {
+		return fmt.Errorf("debug.failure_injection is enabled, but this binary was built without failure injection (build with -tags failureinjection)")
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const intelligenceServiceName = "akash.intelligence.v1.ProviderIntelligence"
+
+// gRPC service defined in proto/intelligence/v1/intelligence.proto. Its
+// messages are well-known types, so the descriptor is written here instead of
+// generated.
+var intelligenceServiceDesc = grpc.ServiceDesc{
+	ServiceName: intelligenceServiceName,
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "GetProviderIntelligence", Handler: grpcToolHandler("GetProviderIntelligence", "get_provider_intelligence")},
+		{MethodName: "SelectOptimalProvider", Handler: grpcToolHandler("SelectOptimalProvider", "select_optimal_provider")},
+		{MethodName: "GetMarketTrends", Handler: grpcToolHandler("GetMarketTrends", "get_market_trends")},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/intelligence/v1/intelligence.proto",
+}
+
+// Create the gRPC server for the intelligence service, with TLS when certs is set
+func (s *MCPServer) newGRPCServer(certs *certReloader) *grpc.Server {
+	options := []grpc.ServerOption{grpc.ChainUnaryInterceptor(grpcRequestIDInterceptor, grpcTracingInterceptor, s.grpcAuthInterceptor)}
+	if certs != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(certs.tlsConfig())))
+	}
+	server := grpc.NewServer(options...)
+	server.RegisterService(&intelligenceServiceDesc, s)
+	return server
+}
+
+// Unary handler that runs a tool with the request's fields as its arguments
+func grpcToolHandler(method, tool string) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		in := new(structpb.Struct)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return srv.(*MCPServer).callGRPCTool(ctx, tool, req.(*structpb.Struct))
+		}
+		if interceptor == nil {
+			return handler(ctx, in)
+		}
+		info := &grpc.UnaryServerInfo{
+			Server:     srv,
+			FullMethod: "/" + intelligenceServiceName + "/" + method,
+		}
+		return interceptor(ctx, in, info, handler)
+	}
+}
+
+// Run a tool under the same limits as /call, mapping failures to gRPC status codes
+func (s *MCPServer) callGRPCTool(ctx context.Context, tool string, in *structpb.Struct) (*structpb.Struct, error) {
+	if !s.toolLimits.acquire(tool) {
+		return nil, status.Errorf(codes.Unavailable, "tool %s is at its concurrency limit (%d), retry later", tool, s.toolLimits.limits[tool])
+	}
+	defer s.toolLimits.release(tool)
+
+	response, err := s.callTool(ctx, tool, in.AsMap())
+	if errors.Is(err, errResultTooLarge) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if errors.Is(err, errToolForbidden) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, status.FromContextError(ctxErr).Err()
+		}
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+
+	// Round-trip through JSON so the result has the same shape as over HTTP
+	structured, err := structuredContent(response)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode tool result: %v", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(structured, &fields); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode tool result: %v", err)
+	}
+	out, err := structpb.NewStruct(fields)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to encode tool result: %v", err)
+	}
+	return out, nil
+}
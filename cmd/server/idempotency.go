@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultIdempotencyTTL        = 10 * time.Minute
+	defaultIdempotencyMaxEntries = 1000
+)
+
+// Outcome of a tool call made with an Idempotency-Key
+type idempotentResult struct {
+	fingerprint string
+	done        chan struct{} // closed once the call has completed
+	status      int
+	header      http.Header
+	body        []byte
+	expiresAt   time.Time
+}
+
+// Bounded cache of tool call results keyed by Idempotency-Key
+type idempotencyCache struct {
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*idempotentResult
+	order      []string // insertion order, oldest first
+	mutex      sync.Mutex
+}
+
+func newIdempotencyCache(ttl time.Duration, maxEntries int) *idempotencyCache {
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+	if maxEntries <= 0 {
+		maxEntries = defaultIdempotencyMaxEntries
+	}
+	return &idempotencyCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*idempotentResult),
+	}
+}
+
+// Claim a key. Returns the entry for the key and whether the caller owns the
+// execution; non-owners wait on entry.done and replay the stored result.
+func (c *idempotencyCache) begin(key, fingerprint string) (*idempotentResult, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if entry, ok := c.entries[key]; ok {
+		select {
+		case <-entry.done:
+			if time.Now().Before(entry.expiresAt) {
+				return entry, false
+			}
+		default:
+			// Still in flight - coalesce onto it
+			return entry, false
+		}
+		c.remove(key)
+	}
+
+	c.evict()
+
+	entry := &idempotentResult{
+		fingerprint: fingerprint,
+		done:        make(chan struct{}),
+	}
+	c.entries[key] = entry
+	c.order = append(c.order, key)
+	return entry, true
+}
+
+// Store the result of an owned execution and release any waiters.
+// Server errors are not retained so that a retry re-executes the call.
+func (c *idempotencyCache) complete(key string, entry *idempotentResult, recorder *responseRecorder) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry.status = recorder.status
+	entry.header = recorder.header
+	entry.body = recorder.body.Bytes()
+	entry.expiresAt = time.Now().Add(c.ttl)
+	close(entry.done)
+
+	if entry.status >= http.StatusInternalServerError {
+		c.remove(key)
+	}
+}
+
+// Drop expired entries, then the oldest completed ones while over capacity. Caller holds the lock.
+func (c *idempotencyCache) evict() {
+	now := time.Now()
+	kept := c.order[:0]
+	for _, key := range c.order {
+		entry, ok := c.entries[key]
+		if !ok {
+			continue
+		}
+		select {
+		case <-entry.done:
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, key)
+				continue
+			}
+		default:
+		}
+		kept = append(kept, key)
+	}
+	c.order = kept
+
+	for i := 0; len(c.entries) >= c.maxEntries && i < len(c.order); {
+		key := c.order[i]
+		select {
+		case <-c.entries[key].done:
+			c.remove(key)
+		default:
+			i++ // In-flight entries are never evicted
+		}
+	}
+}
+
+// Remove a key from the cache. Caller holds the lock.
+func (c *idempotencyCache) remove(key string) {
+	delete(c.entries, key)
+	for i, k := range c.order {
+		if k == key {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// Captures a handler's response so it can be stored and replayed
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newResponseRecorder() *responseRecorder {
+	return &responseRecorder{header: make(http.Header), status: http.StatusOK}
+}
+
+func (r *responseRecorder) Header() http.Header         { return r.header }
+func (r *responseRecorder) Write(b []byte) (int, error) { return r.body.Write(b) }
+func (r *responseRecorder) WriteHeader(status int)      { r.status = status }
+
+// Write a stored result to the client
+func replayResult(w http.ResponseWriter, entry *idempotentResult, replayed bool) {
+	for name, values := range entry.header {
+		w.Header()[name] = values
+	}
+	if replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+	}
+	w.WriteHeader(entry.status)
+	w.Write(entry.body)
+}
+
+// Middleware: calls carrying an Idempotency-Key are executed at most once per
+// client and key within the TTL. Duplicates, including concurrent ones, receive
+// the same result. Wrapped in authMiddleware, so clients are verified ones.
+func (s *MCPServer) idempotencyMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" {
+			next(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		sum := sha256.Sum256(body)
+		fingerprint := hex.EncodeToString(sum[:])
+
+		// Keys are only unique per client, so they never match another client's call
+		key = requestClient(r) + " " + key
+		entry, owner := s.idempotency.begin(key, fingerprint)
+		if entry.fingerprint != fingerprint {
+			http.Error(w, "Idempotency-Key was already used for a different request", http.StatusUnprocessableEntity)
+			return
+		}
+
+		if !owner {
+			select {
+			case <-entry.done:
+				replayResult(w, entry, true)
+			case <-r.Context().Done():
+			}
+			return
+		}
+
+		// A call that panics completes as a server error, which isn't kept, so
+		// its waiters are answered and the key is free for a retry
+		completed := false
+		defer func() {
+			if !completed {
+				failed := newResponseRecorder()
+				http.Error(failed, "Internal server error", http.StatusInternalServerError)
+				s.idempotency.complete(key, entry, failed)
+			}
+		}()
+
+		recorder := newResponseRecorder()
+		next(recorder, r)
+		s.idempotency.complete(key, entry, recorder)
+		completed = true
+		replayResult(w, entry, false)
+	}
+}
@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	serverName    = "akash-provider-intelligence"
+	serverVersion = "1.0.0"
+)
+
+// MCP protocol revisions this server speaks, newest first
+var supportedProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC 2.0 error codes
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcInternalError  = -32603
+
+	// Server-defined: the tool is at its concurrency limit
+	rpcToolBusy = -32001
+
+	// Server-defined: the client is over its rate limit
+	rpcRateLimited = -32002
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"` // absent on notifications
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (r *rpcRequest) isNotification() bool {
+	return len(r.ID) == 0
+}
+
+func rpcResult(id json.RawMessage, result interface{}) *rpcResponse {
+	return &rpcResponse{JSONRPC: "2.0", ID: id, Result: result}
+}
+
+func rpcFailure(id json.RawMessage, code int, format string, args ...interface{}) *rpcResponse {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &rpcResponse{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: fmt.Sprintf(format, args...)}}
+}
+
+// Decode one JSON-RPC message; the response is set when it is malformed
+func parseRPCRequest(data []byte) (*rpcRequest, *rpcResponse) {
+	var request rpcRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		return nil, rpcFailure(nil, rpcParseError, "parse error: %v", err)
+	}
+	if request.JSONRPC != "2.0" || request.Method == "" {
+		return nil, rpcFailure(request.ID, rpcInvalidRequest, "invalid request: jsonrpc must be \"2.0\" and method is required")
+	}
+	return &request, nil
+}
+
+// Handle one MCP message, independent of transport. Notifications get no
+// response, and neither do requests the client cancelled.
+func (s *MCPServer) handleRPC(ctx context.Context, request *rpcRequest) *rpcResponse {
+	var result interface{}
+	var failure *rpcResponse
+
+	if !request.isNotification() {
+		var finish func()
+		ctx, finish = s.calls.start(ctx, request.ID)
+		defer finish()
+	}
+
+	switch request.Method {
+	case "initialize":
+		result, failure = s.rpcInitialize(request)
+	case "ping":
+		result = map[string]interface{}{}
+	case "tools/list":
+		result = map[string]interface{}{"tools": mcpToolDefinitions()}
+	case "tools/call":
+		result, failure = s.rpcCallTool(ctx, request)
+	case "resources/list":
+		result = s.rpcListResources()
+	case "resources/templates/list":
+		result = s.rpcListResourceTemplates()
+	case "resources/read":
+		result, failure = s.rpcReadResource(ctx, request)
+	case "prompts/list":
+		result = s.rpcListPrompts()
+	case "prompts/get":
+		result, failure = s.rpcGetPrompt(request)
+	case "notifications/cancelled":
+		s.rpcCancel(ctx, request)
+		return nil
+	case "notifications/initialized":
+		return nil
+	default:
+		failure = rpcFailure(request.ID, rpcMethodNotFound, "method not found: %s", request.Method)
+	}
+
+	if request.isNotification() || errors.Is(context.Cause(ctx), errCancelledByClient) {
+		return nil
+	}
+	if failure != nil {
+		return failure
+	}
+	return rpcResult(request.ID, result)
+}
+
+// Agree on a protocol revision: the client's if supported, otherwise our newest
+func (s *MCPServer) rpcInitialize(request *rpcRequest) (interface{}, *rpcResponse) {
+	var params struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(request.Params) > 0 {
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return nil, rpcFailure(request.ID, rpcInvalidParams, "invalid initialize params: %v", err)
+		}
+	}
+
+	version := supportedProtocolVersions[0]
+	if slices.Contains(supportedProtocolVersions, params.ProtocolVersion) {
+		version = params.ProtocolVersion
+	}
+
+	return map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities": map[string]interface{}{
+			"tools":     map[string]interface{}{"listChanged": false},
+			"resources": map[string]interface{}{"subscribe": false, "listChanged": false},
+			"prompts":   map[string]interface{}{"listChanged": false},
+		},
+		"serverInfo": map[string]interface{}{
+			"name":    serverName,
+			"version": serverVersion,
+		},
+	}, nil
+}
+
+// Run a tool. Failures of the tool itself are results with isError set, so the
+// model sees them; only malformed calls are protocol errors.
+func (s *MCPServer) rpcCallTool(ctx context.Context, request *rpcRequest) (interface{}, *rpcResponse) {
+	var params struct {
+		Name      string                 `json:"name"`
+		Arguments map[string]interface{} `json:"arguments"`
+		Meta      struct {
+			ProgressToken json.RawMessage `json:"progressToken"`
+		} `json:"_meta"`
+	}
+	if err := json.Unmarshal(request.Params, &params); err != nil || params.Name == "" {
+		return nil, rpcFailure(request.ID, rpcInvalidParams, "tools/call requires params with a tool name")
+	}
+	if params.Arguments == nil {
+		params.Arguments = map[string]interface{}{}
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("mcp.tool", params.Name))
+
+	if !s.toolLimits.acquire(params.Name) {
+		return nil, rpcFailure(request.ID, rpcToolBusy, "tool %s is at its concurrency limit (%d), retry later",
+			params.Name, s.toolLimits.limits[params.Name])
+	}
+	defer s.toolLimits.release(params.Name)
+
+	ctx = withToolProgress(ensureRequestID(ctx), params.Meta.ProgressToken)
+	response, err := s.callTool(ctx, params.Name, params.Arguments)
+	if errors.Is(err, errUnknownTool) {
+		return nil, rpcFailure(request.ID, rpcInvalidParams, "unknown tool: %s", params.Name)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "tool call failed")
+		// The request ID lets a failure the model reports be found in logs and traces
+		text := fmt.Sprintf("%v (request %s)", err, telemetry.RequestID(ctx))
+		return map[string]interface{}{
+			"content": []map[string]interface{}{{"type": "text", "text": text}},
+			"isError": true,
+		}, nil
+	}
+
+	structured, err := structuredContent(response)
+	if err != nil {
+		return nil, rpcFailure(request.ID, rpcInternalError, "failed to encode tool result: %v", err)
+	}
+
+	// The text block repeats the JSON for clients predating structured content
+	return map[string]interface{}{
+		"content":           []map[string]interface{}{{"type": "text", "text": string(structured)}},
+		"structuredContent": structured,
+		"isError":           false,
+	}, nil
+}
+
+// Encode a tool result as a JSON object. Results that encode to anything else
+// are wrapped as {"result": ...}, since structured content must be an object.
+func structuredContent(response interface{}) (json.RawMessage, error) {
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+	if trimmed := bytes.TrimSpace(encoded); len(trimmed) > 0 && trimmed[0] == '{' {
+		return encoded, nil
+	}
+	return json.Marshal(map[string]json.RawMessage{"result": encoded})
+}
+
+// Tool definitions in the MCP shape, with the JSON schema as inputSchema
+func mcpToolDefinitions() []map[string]interface{} {
+	definitions := toolDefinitions()
+	tools := make([]map[string]interface{}, 0, len(definitions))
+	for _, definition := range definitions {
+		tools = append(tools, map[string]interface{}{
+			"name":        definition["name"],
+			"description": definition["description"],
+			"inputSchema": definition["parameters"],
+		})
+	}
+	return tools
+}
+
+// POST /mcp - one JSON-RPC message or batch per request, answered with JSON
+// or, for single requests from clients accepting text/event-stream, streamed.
+// A client that disconnects cancels its request.
+func (s *MCPServer) handleMCP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+	ctx := withCallScope(r.Context(), httpCallScope(r))
+
+	if isRPCBatch(body) {
+		responses, failure := s.handleRPCBatch(ctx, body, func(toolCalls int) *rpcResponse {
+			// The rate limit counts tool calls, not requests; the middleware took the first
+			return s.rpcOverRateLimit(w, r, toolCalls-1)
+		})
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case failure != nil:
+			json.NewEncoder(w).Encode(failure)
+		case len(responses) == 0:
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			json.NewEncoder(w).Encode(responses)
+		}
+		return
+	}
+
+	request, failure := parseRPCRequest(body)
+	if failure != nil {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(failure)
+		return
+	}
+
+	if !request.isNotification() && acceptsEventStream(r) {
+		s.streamRPC(ctx, w, r, request)
+		return
+	}
+
+	response := s.handleRPC(ctx, request)
+	if response == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GET /mcp - this server sends nothing unprompted, so there is no standalone stream
+func (s *MCPServer) handleMCPStream(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Allow", "POST")
+	http.Error(w, "Server-initiated streams are not supported; POST messages to /mcp", http.StatusMethodNotAllowed)
+}
+
+// Parse and handle a raw message
+func (s *MCPServer) handleRPCMessage(ctx context.Context, data []byte) *rpcResponse {
+	request, failure := parseRPCRequest(data)
+	if failure != nil {
+		return failure
+	}
+	return s.handleRPC(ctx, request)
+}
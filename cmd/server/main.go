@@ -3,40 +3,221 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
 	"github.com/chainzero/akash-provider-intelligence/internal/intelligence"
+	"github.com/chainzero/akash-provider-intelligence/internal/telemetry"
 	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
 	"gopkg.in/yaml.v2"
 )
 
 var startTime = time.Now()
 
+const defaultDrainTimeout = 30 * time.Second
+
 type Config struct {
 	Server struct {
-		Port    int           `yaml:"port"`
-		Host    string        `yaml:"host"`
-		Timeout time.Duration `yaml:"timeout"`
+		Port     int           `yaml:"port"`
+		GRPCPort int           `yaml:"grpc_port"` // 0 disables the gRPC API
+		Host     string        `yaml:"host"`
+		Timeout  time.Duration `yaml:"timeout"`
+
+		// In-flight calls get this long to finish on SIGTERM or SIGINT; 0 uses the default of 30s
+		DrainTimeout time.Duration `yaml:"drain_timeout"`
+
+		// Serve HTTPS (and TLS on gRPC) with this key pair; reloaded on SIGHUP or change
+		TLS struct {
+			CertFile string `yaml:"cert_file"`
+			KeyFile  string `yaml:"key_file"`
+		} `yaml:"tls"`
+
+		IdempotencyTTL        time.Duration `yaml:"idempotency_ttl"`
+		IdempotencyMaxEntries int           `yaml:"idempotency_max_entries"`
+
+		// Reject tool calls with 503 once upstream saturation reaches this; 0 only reports it
+		SaturationThreshold float64 `yaml:"saturation_threshold"`
+
+		// Most concurrent calls per tool name; tools not listed are unlimited
+		ToolConcurrency map[string]int `yaml:"tool_concurrency"`
+
+		// Largest encoded tool result; bigger ones are refused with a hint to page or narrow the call
+		MaxResponseBytes int `yaml:"max_response_bytes"`
+
+		// Token bucket per client (API key, else address); a rate of 0 disables it
+		RateLimit struct {
+			Rate  float64 `yaml:"rate"`  // calls per second
+			Burst int     `yaml:"burst"` // calls allowed at once; defaults to the rate
+		} `yaml:"rate_limit"`
+
+		// Calls of one batch run at the same time; 0 uses the default of 4
+		BatchConcurrency int `yaml:"batch_concurrency"`
+
+		// Keys accepted in X-API-Key; empty leaves the API open
+		APIKeys []APIKey `yaml:"api_keys" json:"-"` // never echoed by /status
+
+		// Bearer JWTs from an OIDC provider, accepted alongside API keys
+		OIDC struct {
+			Issuer   string `yaml:"issuer"`   // expected iss; its discovery document locates the JWKS
+			JWKSURL  string `yaml:"jwks_url"` // skips discovery when set
+			Audience string `yaml:"audience"` // expected aud; empty skips the check
+
+			// Scope a token needs to call each listed tool
+			ToolScopes map[string]string `yaml:"tool_scopes"`
+		} `yaml:"oidc"`
+
+		// Origins allowed by CORS; empty or "*" allows any
+		CORSOrigins []string `yaml:"cors_origins"`
+
+		// Bearer token for the admin endpoints; empty disables them
+		AdminToken string `yaml:"admin_token" json:"-"` // never echoed by /status
 	} `yaml:"server"`
 
 	Akash struct {
 		GRPCEndpoint string `yaml:"grpc_endpoint"`
-		RPCEndpoint  string `yaml:"rpc_endpoint"`
-		ChainID      string `yaml:"chain_id"`
+
+		// More endpoints to balance over and fail over to, after grpc_endpoint
+		GRPCEndpoints []string `yaml:"grpc_endpoints"`
+
+		RPCEndpoint string `yaml:"rpc_endpoint"` // chain queries fall back to it while gRPC is unreachable
+		ChainID     string `yaml:"chain_id"`
+
+		// TLS for hosted gRPC gateways; off talks plaintext
+		GRPCTLS struct {
+			Enabled    bool   `yaml:"enabled"`
+			CAFile     string `yaml:"ca_file"`     // PEM bundle; empty trusts the system roots
+			ServerName string `yaml:"server_name"` // defaults to the endpoint host
+		} `yaml:"grpc_tls"`
+
+		// Authorization sent with every gRPC call; needs grpc_tls
+		GRPCAuth struct {
+			BearerToken string `yaml:"bearer_token"`
+			Username    string `yaml:"username"` // basic auth, when no bearer token is set
+			Password    string `yaml:"password"`
+		} `yaml:"grpc_auth" json:"-"` // never echoed by /status
 	} `yaml:"akash"`
 
 	Intelligence struct {
-		CacheTTL            time.Duration `yaml:"cache_ttl"`
-		StatusTimeout       time.Duration `yaml:"status_timeout"`
-		MaxConcurrent       int           `yaml:"max_concurrent"`
-		HealthCheckInterval time.Duration `yaml:"health_check_interval"`
+		CacheTTL             time.Duration `yaml:"cache_ttl"`
+		CacheFile            string        `yaml:"cache_file"`       // saved on shutdown, restored on start
+		StatusTimeout        time.Duration `yaml:"status_timeout"`   // one provider's /status, retries included
+		ChainTimeout         time.Duration `yaml:"chain_timeout"`    // one provider's blockchain query, retries included
+		ProviderTimeout      time.Duration `yaml:"provider_timeout"` // everything fetched for one provider
+		BatchTimeout         time.Duration `yaml:"batch_timeout"`    // one batch of providers fetched together
+		MaxConcurrent        int           `yaml:"max_concurrent"`
+		HealthCheckInterval  time.Duration `yaml:"health_check_interval"`
+		StatusRetryAttempts  int           `yaml:"status_retry_attempts"`
+		StatusRetryBackoff   time.Duration `yaml:"status_retry_backoff"`
+		StatusRetryMax       time.Duration `yaml:"status_retry_max_backoff"`
+		StatusAttemptTimeout time.Duration `yaml:"status_attempt_timeout"`
+		ChainRetryAttempts   int           `yaml:"chain_retry_attempts"`
+		ChainRetryBackoff    time.Duration `yaml:"chain_retry_backoff"`
+		ChainRetryMax        time.Duration `yaml:"chain_retry_max_backoff"`
+		ChainAttemptTimeout  time.Duration `yaml:"chain_attempt_timeout"`
+		RetryJitter          float64       `yaml:"retry_jitter"` // share of each backoff randomized, 0 to 1
+
+		// Stop querying status endpoints that keep failing; a threshold of 0 disables it
+		CircuitBreaker struct {
+			FailureThreshold int           `yaml:"failure_threshold"`
+			Cooldown         time.Duration `yaml:"cooldown"`
+		} `yaml:"circuit_breaker"`
+		ExtraEndpoints       []string      `yaml:"extra_endpoints"`
+		HistoryRetention     time.Duration `yaml:"history_retention"`
+		HistoryMaxSamples    int           `yaml:"history_max_samples"`
+		NetworkStatsInterval time.Duration `yaml:"network_stats_interval"`
+		HostIndexRefresh     time.Duration `yaml:"host_index_refresh"`
+		LeaderboardInterval  time.Duration `yaml:"leaderboard_interval"`
+		LeaderboardSize      int           `yaml:"leaderboard_size"`
+		ListConcurrency      int           `yaml:"list_concurrency"`
+		MaxResultProviders   int           `yaml:"max_result_providers"`
+		MaxScoredProviders   int           `yaml:"max_scored_providers"`
+		ScoringCapPolicy     string        `yaml:"scoring_cap_policy"` // reject or prior
+		FetchOrder           string        `yaml:"fetch_order"`        // input, prior or stalest
+		RecommendationTTL    time.Duration `yaml:"recommendation_cache_ttl"`
+
+		// Share of the performance score from blockchain query time; 0 (default) excludes it
+		BlockchainQueryWeight float64  `yaml:"blockchain_query_weight"`
+		Blacklist             []string `yaml:"blacklist"`
+		AttributeDenylist     []string `yaml:"attribute_denylist"`
+		ScoreFloor            float64  `yaml:"score_floor"`
+
+		// Disable the geographic weight when too few candidates report a region
+		Geographic struct {
+			MinCoverage  float64 `yaml:"min_coverage"`
+			Redistribute string  `yaml:"redistribute"` // proportional or none
+		} `yaml:"geographic"`
+
+		// Moving average weight of the newest health observation; 0 disables smoothing
+		HealthSmoothingAlpha float64 `yaml:"health_smoothing_alpha"`
+
+		Maintenance struct {
+			Lookahead time.Duration `yaml:"lookahead"`
+			Policy    string        `yaml:"policy"` // penalize or exclude
+			Penalty   float64       `yaml:"penalty"`
+		} `yaml:"maintenance"`
+
+		// Advertised resources contradicted by the status inventory
+		ResourceDivergence struct {
+			Penalty float64 `yaml:"penalty"` // 0 only reports the divergence
+		} `yaml:"resource_divergence"`
+
+		// Pushed provider updates on /ws
+		Subscriptions struct {
+			Refresh     time.Duration `yaml:"refresh"` // 0 leaves watched providers to other callers
+			HealthDelta float64       `yaml:"health_delta"`
+		} `yaml:"subscriptions"`
+
+		// Active lease contribution to the health score
+		LeaseScoring struct {
+			Tiers           []akash.LeaseTier `yaml:"tiers"`
+			ZeroLeasePolicy string            `yaml:"zero_lease_policy"` // penalize or neutral
+			NeutralScore    float64           `yaml:"neutral_score"`
+		} `yaml:"lease_scoring"`
+
+		// Scoring of dimensions that lack status data
+		PartialData struct {
+			Policy string  `yaml:"policy"` // penalize or neutral
+			Score  float64 `yaml:"score"`  // used by penalize
+		} `yaml:"partial_data"`
+
+		// Keep providers with an unknown node count when min_available_nodes is requested
+		IncludeUnknownNodeCount bool `yaml:"include_unknown_node_count"`
+
+		// Providers that advertise no CPU architecture when cpu_arch is requested
+		UnknownArchPolicy string `yaml:"unknown_arch_policy"` // assume_x86 or exclude
+
+		// GPU availability fast path
+		GPU struct {
+			CacheTTL time.Duration `yaml:"cache_ttl"`
+			Timeout  time.Duration `yaml:"timeout"`
+		} `yaml:"gpu"`
+
+		// Status response field mappings, tried before the built-in v0.4/v0.5 schemas
+		StatusSchemas []akash.StatusSchema `yaml:"status_schemas"`
+
+		// Per-node inventory sanity bounds; unset values use the defaults
+		ResourceLimits struct {
+			MaxNodeCPU     int64 `yaml:"max_node_cpu"`     // millicores
+			MaxNodeMemory  int64 `yaml:"max_node_memory"`  // bytes
+			MaxNodeStorage int64 `yaml:"max_node_storage"` // bytes
+			MaxNodeGPU     int64 `yaml:"max_node_gpu"`
+		} `yaml:"resource_limits"`
 	} `yaml:"intelligence"`
 
 	Logging struct {
@@ -44,22 +225,76 @@ type Config struct {
 		Format string `yaml:"format"`
 	} `yaml:"logging"`
 
+	// Default rendering of selection reasoning; requests may override it
+	Reasoning struct {
+		Style string `yaml:"style"` // rich, plain, markdown, html or custom
+		Units string `yaml:"units"` // binary or decimal
+
+		// Go template over the structured reasoning for the custom style,
+		// inline or read from a file
+		Template     string `yaml:"template"`
+		TemplateFile string `yaml:"template_file"`
+	} `yaml:"reasoning"`
+
+	// Testing aids for integrators; never enable in production
+	Debug struct {
+		// Only honoured by binaries built with -tags failureinjection
+		FailureInjection struct {
+			Enabled bool                 `yaml:"enabled"`
+			Faults  []intelligence.Fault `yaml:"faults"`
+		} `yaml:"failure_injection"`
+	} `yaml:"debug"`
+
+	Tracing struct {
+		Enabled      bool    `yaml:"enabled"`
+		OTLPEndpoint string  `yaml:"otlp_endpoint"`
+		Insecure     bool    `yaml:"insecure"`
+		SampleRatio  float64 `yaml:"sample_ratio"`
+		ServiceName  string  `yaml:"service_name"`
+	} `yaml:"tracing"`
+
+	// Unset weights fall back to intelligence.DefaultWeights
 	SelectionWeights struct {
-		Price       float64 `yaml:"price"`
-		Reliability float64 `yaml:"reliability"`
-		Performance float64 `yaml:"performance"`
-		Geographic  float64 `yaml:"geographic"`
+		Price        *float64 `yaml:"price"`
+		Reliability  *float64 `yaml:"reliability"`
+		Performance  *float64 `yaml:"performance"`
+		Geographic   *float64 `yaml:"geographic"`
+		Stability    *float64 `yaml:"stability"`
+		Provisioning *float64 `yaml:"provisioning"`
+		Network      *float64 `yaml:"network"`
 	} `yaml:"selection_weights"`
+
+	Scoring struct {
+		Plugins []struct {
+			Name   string  `yaml:"name"`
+			Weight float64 `yaml:"weight"`
+		} `yaml:"plugins"`
+	} `yaml:"scoring"`
+
+	Tiers []struct {
+		Name               string            `yaml:"name"`
+		RequireGPU         bool              `yaml:"require_gpu"`
+		MinAvailableCPU    int64             `yaml:"min_available_cpu"`
+		MinAvailableMemory int64             `yaml:"min_available_memory"`
+		MaxActiveLeases    *int              `yaml:"max_active_leases"`
+		Attributes         map[string]string `yaml:"attributes"`
+	} `yaml:"tiers"`
 }
 
 type MCPServer struct {
 	config              *Config
 	intelligenceService *intelligence.Service
 	router              *mux.Router
+	idempotency         *idempotencyCache
+	toolLimits          *toolLimiter
+	calls               *rpcCalls
+	rateLimiter         *rateLimiter // nil when rate limiting is disabled
+	jwtVerifier         *jwtVerifier // nil unless OIDC is configured
+	weights             reloadableWeights
 }
 
 func loadConfig(configPath string) (*Config, error) {
-	config := &Config{}
+	config := defaultConfig()
 
 	file, err := os.Open(configPath)
 	if err != nil {
@@ -72,17 +307,125 @@ func loadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to decode config: %w", err)
 	}
 
+	if config.Reasoning.TemplateFile != "" {
+		if config.Reasoning.Template != "" {
+			return nil, fmt.Errorf("reasoning.template and reasoning.template_file are mutually exclusive")
+		}
+		text, err := os.ReadFile(config.Reasoning.TemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read reasoning template: %w", err)
+		}
+		config.Reasoning.Template = string(text)
+	}
+
+	if err := applyEnvOverrides(config); err != nil {
+		return nil, err
+	}
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
 func NewMCPServer(config *Config) (*MCPServer, error) {
+	// Custom scoring plugins referenced in config
+	var customScorers []intelligence.ScorerWeight
+	for _, plugin := range config.Scoring.Plugins {
+		customScorers = append(customScorers, intelligence.ScorerWeight{
+			Name:   plugin.Name,
+			Weight: plugin.Weight,
+		})
+	}
+
+	// Capability tier rules; the service falls back to its defaults when none are configured
+	var tierRules []intelligence.TierRule
+	for _, tier := range config.Tiers {
+		tierRules = append(tierRules, intelligence.TierRule{
+			Name:               tier.Name,
+			RequireGPU:         tier.RequireGPU,
+			MinAvailableCPU:    tier.MinAvailableCPU,
+			MinAvailableMemory: tier.MinAvailableMemory,
+			MaxActiveLeases:    tier.MaxActiveLeases,
+			Attributes:         tier.Attributes,
+		})
+	}
+
+	// The leaderboard ranks with the configured weights
+	leaderboardWeights, _, err := intelligence.ResolveWeights(configuredWeights(config), nil)
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize intelligence service
 	intelService, err := intelligence.NewService(&intelligence.Config{
-		AkashGRPCEndpoint:   config.Akash.GRPCEndpoint,
+		AkashGRPCEndpoints:  akashGRPCEndpoints(config),
+		AkashRPCEndpoint:    config.Akash.RPCEndpoint,
+		AkashGRPCSecurity:   akashGRPCSecurity(config),
 		CacheTTL:            config.Intelligence.CacheTTL,
+		CacheFile:           config.Intelligence.CacheFile,
 		StatusTimeout:       config.Intelligence.StatusTimeout,
+		ChainTimeout:        config.Intelligence.ChainTimeout,
+		ProviderTimeout:     config.Intelligence.ProviderTimeout,
+		BatchTimeout:        config.Intelligence.BatchTimeout,
 		MaxConcurrent:       config.Intelligence.MaxConcurrent,
 		HealthCheckInterval: config.Intelligence.HealthCheckInterval,
+		StatusRetry:         statusRetryPolicy(config),
+		ChainRetry:          chainRetryPolicy(config),
+		CircuitBreaker: akash.CircuitBreakerConfig{
+			FailureThreshold: config.Intelligence.CircuitBreaker.FailureThreshold,
+			Cooldown:         config.Intelligence.CircuitBreaker.Cooldown,
+		},
+		ExtraStatusEndpoints:    config.Intelligence.ExtraEndpoints,
+		CustomScorers:           customScorers,
+		HistoryRetention:        config.Intelligence.HistoryRetention,
+		HistoryMaxSamples:       config.Intelligence.HistoryMaxSamples,
+		NetworkStatsInterval:    config.Intelligence.NetworkStatsInterval,
+		HostIndexRefresh:        config.Intelligence.HostIndexRefresh,
+		LeaderboardInterval:     config.Intelligence.LeaderboardInterval,
+		LeaderboardSize:         config.Intelligence.LeaderboardSize,
+		LeaderboardWeights:      leaderboardWeights,
+		SubscriptionRefresh:     config.Intelligence.Subscriptions.Refresh,
+		SubscriptionHealthDelta: config.Intelligence.Subscriptions.HealthDelta,
+		ListConcurrency:         config.Intelligence.ListConcurrency,
+		MaxResultProviders:      config.Intelligence.MaxResultProviders,
+		MaxScoredProviders:      config.Intelligence.MaxScoredProviders,
+		ScoringCapPolicy:        config.Intelligence.ScoringCapPolicy,
+		FetchOrder:              config.Intelligence.FetchOrder,
+		GPUCacheTTL:             config.Intelligence.GPU.CacheTTL,
+		GPUTimeout:              config.Intelligence.GPU.Timeout,
+		RecommendationCacheTTL:  config.Intelligence.RecommendationTTL,
+		BlockchainQueryWeight:   config.Intelligence.BlockchainQueryWeight,
+		PartialDataPolicy:       config.Intelligence.PartialData.Policy,
+		PartialDataScore:        config.Intelligence.PartialData.Score,
+		Blacklist:               config.Intelligence.Blacklist,
+		AttributeDenylist:       config.Intelligence.AttributeDenylist,
+		TierRules:               tierRules,
+		ScoreFloor:              config.Intelligence.ScoreFloor,
+		GeoMinCoverage:          config.Intelligence.Geographic.MinCoverage,
+		GeoRedistribution:       config.Intelligence.Geographic.Redistribute,
+		IncludeUnknownNodeCount: config.Intelligence.IncludeUnknownNodeCount,
+		UnknownArchPolicy:       config.Intelligence.UnknownArchPolicy,
+		ReasoningStyle:          config.Reasoning.Style,
+		ReasoningUnits:          config.Reasoning.Units,
+		ReasoningTemplate:       config.Reasoning.Template,
+		HealthSmoothingAlpha:    config.Intelligence.HealthSmoothingAlpha,
+		MaintenanceLookahead:    config.Intelligence.Maintenance.Lookahead,
+		MaintenancePolicy:       config.Intelligence.Maintenance.Policy,
+		MaintenancePenalty:      config.Intelligence.Maintenance.Penalty,
+		DivergencePenalty:       config.Intelligence.ResourceDivergence.Penalty,
+		LeaseScoring: akash.LeaseScoring{
+			Tiers:           config.Intelligence.LeaseScoring.Tiers,
+			ZeroLeasePolicy: config.Intelligence.LeaseScoring.ZeroLeasePolicy,
+			NeutralScore:    config.Intelligence.LeaseScoring.NeutralScore,
+		},
+		StatusSchemas: config.Intelligence.StatusSchemas,
+		ResourceLimits: akash.ResourceLimits{
+			MaxNodeCPU:     config.Intelligence.ResourceLimits.MaxNodeCPU,
+			MaxNodeMemory:  config.Intelligence.ResourceLimits.MaxNodeMemory,
+			MaxNodeStorage: config.Intelligence.ResourceLimits.MaxNodeStorage,
+			MaxNodeGPU:     config.Intelligence.ResourceLimits.MaxNodeGPU,
+		},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create intelligence service: %w", err)
@@ -92,108 +435,450 @@ func NewMCPServer(config *Config) (*MCPServer, error) {
 		config:              config,
 		intelligenceService: intelService,
 		router:              mux.NewRouter(),
+		idempotency:         newIdempotencyCache(config.Server.IdempotencyTTL, config.Server.IdempotencyMaxEntries),
+		toolLimits:          newToolLimiter(config.Server.ToolConcurrency),
+		calls:               newRPCCalls(),
+		rateLimiter:         newRateLimiter(config.Server.RateLimit.Rate, config.Server.RateLimit.Burst),
+	}
+	server.weights.set(configuredWeights(config))
+	if oidc := config.Server.OIDC; oidc.Issuer != "" || oidc.JWKSURL != "" {
+		server.jwtVerifier = newJWTVerifier(oidc.Issuer, oidc.JWKSURL, oidc.Audience)
 	}
 
 	server.setupRoutes()
+	if err := server.setupFailureInjection(); err != nil {
+		return nil, err
+	}
 	return server, nil
 }
 
 func (s *MCPServer) setupRoutes() {
-	// MCP Protocol endpoints
+	// MCP JSON-RPC 2.0 endpoint for standard MCP clients
+	s.router.HandleFunc("/mcp", s.authMiddleware(s.rateLimitMiddleware(s.backpressureMiddleware(s.handleMCP)))).Methods("POST")
+	s.router.HandleFunc("/mcp", s.authMiddleware(s.rateLimitMiddleware(s.handleMCPStream))).Methods("GET")
+
+	// Plain HTTP tool endpoints
 	s.router.HandleFunc("/tools", s.handleTools).Methods("GET")
-	s.router.HandleFunc("/call", s.handleToolCall).Methods("POST")
+	s.router.HandleFunc("/call", s.authMiddleware(s.rateLimitMiddleware(s.idempotencyMiddleware(s.backpressureMiddleware(s.handleToolCall))))).Methods("POST")
+	s.router.HandleFunc("/call/batch", s.authMiddleware(s.rateLimitMiddleware(s.backpressureMiddleware(s.handleBatchToolCall)))).Methods("POST")
+
+	// REST endpoints for non-MCP consumers
+	s.router.HandleFunc("/api/v1/providers", s.authMiddleware(s.rateLimitMiddleware(s.backpressureMiddleware(s.handleRESTProviders)))).Methods("GET")
+	s.router.HandleFunc("/api/v1/selection", s.authMiddleware(s.rateLimitMiddleware(s.backpressureMiddleware(s.handleRESTSelection)))).Methods("POST")
+	s.router.HandleFunc("/api/v1/market", s.authMiddleware(s.rateLimitMiddleware(s.backpressureMiddleware(s.handleRESTMarket)))).Methods("GET")
+	s.router.HandleFunc("/openapi.json", s.handleOpenAPI).Methods("GET")
+
+	// Provider blacklist and temporary bans
+	s.router.HandleFunc("/blacklist", s.handleGetBlacklist).Methods("GET")
+	s.router.HandleFunc("/blacklist", s.authMiddleware(s.handleBanProvider)).Methods("POST")
+	s.router.HandleFunc("/blacklist/{address}", s.authMiddleware(s.handleUnbanProvider)).Methods("DELETE")
+
+	// Pushed provider updates for dashboards
+	s.router.HandleFunc("/ws", s.authMiddleware(s.handleWebSocket)).Methods("GET")
+
+	// Cache churn for monitoring dashboards
+	s.router.HandleFunc("/cache/expiry", s.authMiddleware(s.handleCacheExpiry)).Methods("GET")
+
+	// Operator controls, only with an admin token configured
+	if s.config.Server.AdminToken != "" {
+		s.router.HandleFunc("/admin/cache/rebuild", s.apiKeyMiddleware(s.adminMiddleware(s.handleStartCacheRebuild))).Methods("POST")
+		s.router.HandleFunc("/admin/cache/rebuild/{id}", s.apiKeyMiddleware(s.adminMiddleware(s.handleCacheRebuildStatus))).Methods("GET")
+		s.router.HandleFunc("/admin/cache/flush", s.apiKeyMiddleware(s.adminMiddleware(s.handleFlushCache))).Methods("POST")
+		s.router.HandleFunc("/admin/cache/refresh", s.apiKeyMiddleware(s.adminMiddleware(s.handleRefreshProviders))).Methods("POST")
+		s.router.HandleFunc("/admin/cache/providers/{address}", s.apiKeyMiddleware(s.adminMiddleware(s.handleEvictProvider))).Methods("DELETE")
+		s.router.HandleFunc("/admin/config", s.apiKeyMiddleware(s.adminMiddleware(s.handleAdminConfig))).Methods("GET")
+		s.router.HandleFunc("/admin/log-level", s.apiKeyMiddleware(s.adminMiddleware(s.handleLogLevel))).Methods("GET", "PUT")
+	}
 
 	// Health check endpoint
 	s.router.HandleFunc("/health", s.handleHealth).Methods("GET")
 
+	// Kubernetes probes: the process is up, and it can serve traffic
+	s.router.HandleFunc("/livez", s.handleLivez).Methods("GET")
+	s.router.HandleFunc("/readyz", s.handleReadyz).Methods("GET")
+
 	// Status endpoint for debugging
 	s.router.HandleFunc("/status", s.handleStatus).Methods("GET")
 
 	// CORS middleware for web clients
-	s.router.Use(corsMiddleware)
-}
-
-// CORS middleware
-func corsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	s.router.Use(s.corsMiddleware)
 
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-
-		next.ServeHTTP(w, r)
-	})
+	// Identify and trace every request
+	s.router.Use(requestIDMiddleware)
+	s.router.Use(tracingMiddleware)
 }
 
 // MCP Tools response
 func (s *MCPServer) handleTools(w http.ResponseWriter, r *http.Request) {
-	tools := map[string]interface{}{
-		"tools": []map[string]interface{}{
-			{
-				"name":        "get_provider_intelligence",
-				"description": "Get comprehensive intelligence data for Akash providers",
-				"parameters": map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"provider_addresses": map[string]interface{}{
-							"type":        "array",
-							"items":       map[string]string{"type": "string"},
-							"description": "List of provider addresses to analyze",
-						},
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"tools": toolDefinitions()})
+}
+
+// Definitions of every tool, shared by /tools and MCP tools/list
+func toolDefinitions() []map[string]interface{} {
+	return []map[string]interface{}{
+		{
+			"name":        "get_provider_intelligence",
+			"description": "Get comprehensive intelligence data for Akash providers",
+			"parameters": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"provider_addresses": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]string{"type": "string"},
+						"description": "List of provider addresses to analyze",
+					},
+					"max_data_age": map[string]interface{}{
+						"type":        "string",
+						"description": "Require every provider's data to be at most this old (e.g. 30s); stale entries are refetched and the call fails if the guarantee cannot be met",
+					},
+					"fetch_order": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"input", "prior", "stalest"},
+						"description": "Order uncached providers are fetched in; prior fetches likely winners (by hint or last known health score) first, stalest the oldest data first",
+					},
+					"fetch_hints": map[string]interface{}{
+						"type":        "object",
+						"description": "Fetch priors by provider address (higher is fetched first); used with fetch_order prior",
+					},
+					"offset": map[string]interface{}{
+						"type":        "integer",
+						"description": "Skip this many providers; follow truncation.next_offset to page through large results",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Providers per page, capped at the server's max_result_providers",
+					},
+					"fields": map[string]interface{}{
+						"type":        "string",
+						"description": "Comma-separated provider fields to return, by JSON name with dots for nesting (e.g. address,health_score,cluster_info.available_resources.gpu); all fields when omitted",
 					},
-					"required": []string{"provider_addresses"},
 				},
+				"required": []string{"provider_addresses"},
 			},
-			{
-				"name":        "select_optimal_provider",
-				"description": "Choose the best provider based on requirements and available intelligence",
-				"parameters": map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"requirements": map[string]interface{}{
-							"type": "object",
-							"properties": map[string]interface{}{
-								"cpu":    map[string]string{"type": "string"},
-								"memory": map[string]string{"type": "string"},
-								"gpu":    map[string]string{"type": "boolean"},
-								"budget": map[string]string{"type": "number"},
-								"priority": map[string]interface{}{
-									"type": "string",
-									"enum": []string{"cost", "performance", "reliability"},
-								},
+		},
+		{
+			"name":        "get_provider_by_host_uri",
+			"description": "Find the on-chain provider(s) registered with a host URI and return their intelligence",
+			"parameters": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"host_uri": map[string]interface{}{
+						"type":        "string",
+						"description": "Provider endpoint, e.g. https://provider.example.com:8443 or a bare hostname",
+					},
+				},
+				"required": []string{"host_uri"},
+			},
+		},
+		{
+			"name":        "get_gpu_availability",
+			"description": "Near-real-time free/total GPU counts and models for a set of providers, most free GPUs first; reads only the status inventory with a short TTL and tight timeout",
+			"parameters": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"provider_addresses": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]string{"type": "string"},
+						"description": "Provider addresses to check",
+					},
+				},
+				"required": []string{"provider_addresses"},
+			},
+		},
+		{
+			"name":        "select_optimal_provider",
+			"description": "Choose the best provider based on requirements and available intelligence",
+			"parameters": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"requirements": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"cpu":    map[string]string{"type": "string"},
+							"memory": map[string]string{"type": "string"},
+							"gpu":    map[string]string{"type": "boolean"},
+							"budget": map[string]string{"type": "number"},
+							"deployment_duration": map[string]interface{}{
+								"type":        "string",
+								"description": "Expected deployment lifetime (e.g. 1h, 720h, 30d); long deployments favour providers with stable leases",
+							},
+							"priority": map[string]interface{}{
+								"type": "string",
+								"enum": []string{"cost", "performance", "reliability"},
+							},
+							"max_data_age": map[string]interface{}{
+								"type":        "string",
+								"description": "Require candidate data to be at most this old (e.g. 30s)",
+							},
+							"fetch_order": map[string]interface{}{
+								"type":        "string",
+								"enum":        []string{"input", "prior", "stalest"},
+								"description": "Order uncached candidates are fetched in; prior fetches likely winners first, stalest the oldest data first",
+							},
+							"fetch_hints": map[string]interface{}{
+								"type":        "object",
+								"description": "Fetch priors by provider address (higher is fetched first)",
+							},
+							"reasoning_style": map[string]interface{}{
+								"type":        "string",
+								"enum":        []string{"rich", "plain", "markdown", "html", "custom"},
+								"description": "Reasoning template: rich (emoji), plain, markdown, html, or the operator's custom template",
+							},
+							"units": map[string]interface{}{
+								"type":        "string",
+								"enum":        []string{"binary", "decimal"},
+								"description": "Memory units in the reasoning: binary (GiB) or decimal (GB)",
+							},
+							"min_available_nodes": map[string]interface{}{
+								"type":        "integer",
+								"description": "Exclude providers with fewer available nodes (single-node providers are a single point of failure)",
+							},
+							"prefer_confident": map[string]interface{}{
+								"type":        "boolean",
+								"description": "Among providers scoring within 0.02 of the best, prefer the one whose score is backed by the most history",
+							},
+							"min_bandwidth": map[string]interface{}{
+								"type":        "string",
+								"description": "Exclude providers that don't advertise at least this bandwidth (e.g. 1Gbps, 500Mbps)",
+							},
+							"try_order": map[string]interface{}{
+								"type":        "integer",
+								"description": "Also return the top N providers (max 20) in the order a client should try them, weighing score candidates by likelihood of quick success",
+							},
+							"cpu_arch": map[string]interface{}{
+								"type":        "string",
+								"description": "Only consider providers that run this CPU architecture (amd64/x86_64 or arm64/aarch64)",
+							},
+							"weights": map[string]interface{}{
+								"type":        "object",
+								"description": "Per-request weight overrides (price, reliability, performance, geographic, stability, provisioning, network)",
 							},
 						},
-						"provider_bids": map[string]interface{}{
-							"type":        "array",
-							"description": "Array of bid data with provider addresses and prices",
-						},
 					},
-					"required": []string{"requirements", "provider_bids"},
+					"provider_bids": map[string]interface{}{
+						"type":        "array",
+						"description": "Array of bid data with provider addresses and prices",
+					},
+					"snapshots": map[string]interface{}{
+						"type":        "array",
+						"description": "Deterministic mode: provider data (as returned by get_provider_intelligence) to select from instead of fetching; identical inputs give identical output",
+					},
+					"all_priorities": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Select under each priority (cost, performance, reliability) against the same candidates and return every winner",
+					},
+					"as_of": map[string]interface{}{
+						"type":        "string",
+						"description": "Deterministic mode: select from the bid providers' historical observations at this RFC3339 time",
+					},
 				},
+				"required": []string{"requirements"},
 			},
-			{
-				"name":        "get_market_trends",
-				"description": "Get current market trends and pricing analysis",
-				"parameters": map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"timeframe": map[string]interface{}{
-							"type":        "string",
-							"description": "Time period for analysis (1h, 24h, 7d)",
-							"default":     "24h",
-						},
+		},
+		{
+			"name":        "compare_providers",
+			"description": "Compare several providers side by side: raw metrics and weighted sub-scores per provider, with the best value per column and the overall winner",
+			"parameters": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"provider_addresses": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]string{"type": "string"},
+						"description": "Provider addresses to compare",
+					},
+					"requirements": map[string]interface{}{
+						"type":        "object",
+						"description": "Selection requirements, as for select_optimal_provider",
+					},
+				},
+				"required": []string{"provider_addresses"},
+			},
+		},
+		{
+			"name":        "list_providers",
+			"description": "List providers registered on chain, optionally only those with audited attributes",
+			"parameters": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"audited_only": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Only return providers with attributes signed by an auditor",
+						"default":     false,
+					},
+					"offset": map[string]interface{}{
+						"type":        "integer",
+						"description": "Skip this many providers; follow truncation.next_offset to page through large results",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Providers per page, capped at the server's max_result_providers",
+					},
+				},
+			},
+		},
+		{
+			"name":        "get_market_trends",
+			"description": "Get advertised price percentiles (p10/p50/p90) per unit of CPU, memory, storage and GPU across the network, with the price trend over the timeframe",
+			"parameters": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"timeframe": map[string]interface{}{
+						"type":        "string",
+						"description": "Time period for analysis (1h, 24h, 7d)",
+						"default":     "24h",
+					},
+				},
+			},
+		},
+		{
+			"name":        "get_network_stats",
+			"description": "Get a time series of network-wide aggregates (providers, capacity, GPUs, average health), downsampled for long windows",
+			"parameters": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"window": map[string]interface{}{
+						"type":        "string",
+						"description": "How far back to look, as a duration (e.g. 24h, 168h). Defaults to 24h",
+					},
+					"max_points": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of points returned; longer windows are averaged into buckets. Defaults to 200",
+					},
+				},
+			},
+		},
+		{
+			"name":        "select_replica_providers",
+			"description": "Select N distinct providers for N replicas, maximizing total score with optional region and cluster anti-affinity, with per-replica reasoning",
+			"parameters": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"provider_addresses": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]string{"type": "string"},
+						"description": "Candidate provider addresses",
+					},
+					"replicas": map[string]interface{}{
+						"type":        "integer",
+						"description": "Number of replicas, each placed on a different provider (max 50)",
+					},
+					"requirements": map[string]interface{}{
+						"type":        "object",
+						"description": "Selection requirements, as for select_optimal_provider",
+					},
+					"distinct_regions": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Place every replica in a different region",
+					},
+					"distinct_clusters": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Never place two replicas on providers sharing a host or public hostname",
 					},
 				},
+				"required": []string{"provider_addresses", "replicas"},
+			},
+		},
+		{
+			"name":        "get_capacity_trends",
+			"description": "Get how total and available network capacity (CPU, memory, storage, GPU) and utilization changed over a window, with percentage changes",
+			"parameters": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"window": map[string]interface{}{
+						"type":        "string",
+						"description": "How far back to compare, as a duration (e.g. 24h, 168h). Defaults to 24h",
+					},
+				},
+			},
+		},
+		{
+			"name":        "get_leaderboard",
+			"description": "Get the network-wide provider ranking, recomputed in the background and served from cache with its timestamp",
+			"parameters": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"offset": map[string]interface{}{
+						"type":        "integer",
+						"description": "Skip this many entries; follow truncation.next_offset for the next page",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Number of entries to return; defaults to all kept entries, capped at the server's max_result_providers",
+					},
+				},
+			},
+		},
+		{
+			"name":        "get_providers_by_tier",
+			"description": "Get provider intelligence grouped by capability tier (gpu, high-capacity, budget, enterprise)",
+			"parameters": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"provider_addresses": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]string{"type": "string"},
+						"description": "List of provider addresses to classify",
+					},
+				},
+				"required": []string{"provider_addresses"},
+			},
+		},
+		{
+			"name":        "explain_selection_change",
+			"description": "Explain which factor changed the selected provider between two points in time, using historical provider data",
+			"parameters": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"provider_addresses": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]string{"type": "string"},
+						"description": "Candidate provider addresses",
+					},
+					"requirements": map[string]interface{}{
+						"type":        "object",
+						"description": "Selection requirements, as for select_optimal_provider",
+					},
+					"from": map[string]interface{}{
+						"type":        "string",
+						"description": "Earlier time point, RFC3339 or a duration ago (e.g. 24h)",
+					},
+					"to": map[string]interface{}{
+						"type":        "string",
+						"description": "Later time point, RFC3339 or a duration ago; defaults to now",
+					},
+				},
+				"required": []string{"provider_addresses", "from"},
+			},
+		},
+		{
+			"name":        "explain_scoring",
+			"description": "Score candidate providers with full breakdowns and report where each scoring weight came from (default, config or request-override)",
+			"parameters": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"provider_addresses": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]string{"type": "string"},
+						"description": "Candidate provider addresses",
+					},
+					"requirements": map[string]interface{}{
+						"type":        "object",
+						"description": "Selection requirements, as for select_optimal_provider",
+					},
+					"sensitivity": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Also report which weight changes would flip the winner, most decisive weight first",
+					},
+					"sensitivity_step": map[string]interface{}{
+						"type":        "number",
+						"description": "Weight perturbation used by the sensitivity analysis (default 0.05)",
+					},
+				},
+				"required": []string{"provider_addresses"},
 			},
 		},
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(tools)
 }
 
 // MCP Tool call handler
@@ -208,26 +893,47 @@ func (s *MCPServer) handleToolCall(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var response interface{}
-	var err error
+	span := trace.SpanFromContext(r.Context())
+	span.SetAttributes(attribute.String("mcp.tool", request.Tool))
 
-	switch request.Tool {
-	case "get_provider_intelligence":
-		response, err = s.handleGetProviderIntelligence(request.Arguments)
-	case "select_optimal_provider":
-		response, err = s.handleSelectOptimalProvider(request.Arguments)
-	case "get_market_trends":
-		response, err = s.handleGetMarketTrends(request.Arguments)
-	default:
-		http.Error(w, fmt.Sprintf("Unknown tool: %s", request.Tool), http.StatusBadRequest)
+	if s.rejectAtToolLimit(w, request.Tool) {
 		return
 	}
+	defer s.toolLimits.release(request.Tool)
 
+	// Calls stop when the client goes away, except idempotent ones: their
+	// result is shared with concurrent duplicates and stored for retries
+	ctx := r.Context()
+	if r.Header.Get("Idempotency-Key") != "" {
+		ctx = context.WithoutCancel(ctx)
+	}
+
+	response, err := s.callTool(ctx, request.Tool, request.Arguments)
+	if errors.Is(err, errUnknownTool) {
+		http.Error(w, fmt.Sprintf("Unknown tool: %s", request.Tool), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, errResultTooLarge) {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if errors.Is(err, errToolForbidden) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "tool call failed")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	structured, err := structuredContent(response)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode tool result: %v", err), http.StatusInternalServerError)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"content": []map[string]interface{}{
@@ -236,46 +942,152 @@ func (s *MCPServer) handleToolCall(w http.ResponseWriter, r *http.Request) {
 				"text": response,
 			},
 		},
+		"structuredContent": structured,
 	})
 }
 
+var errUnknownTool = errors.New("unknown tool")
+
+// Run a tool by name, refusing callers without the tool's scope and results
+// larger than the configured payload size
+func (s *MCPServer) callTool(ctx context.Context, tool string, args map[string]interface{}) (interface{}, error) {
+	// One span and request ID per call, whichever transport or batch it arrived on
+	ctx = ensureRequestID(ctx)
+	ctx, span := tracer.Start(ctx, "tool "+tool, trace.WithAttributes(
+		attribute.String("tool.name", tool),
+		attribute.String("request.id", telemetry.RequestID(ctx)),
+	))
+	defer span.End()
+
+	ctx = telemetry.WithLogAttrs(ctx, slog.String("tool", tool))
+	if err := s.authorizeTool(ctx, tool); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	response, err := s.dispatchTool(ctx, tool, args)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "tool call failed")
+		slog.InfoContext(ctx, "Tool call failed", "duration", time.Since(start), "error", err)
+		return nil, err
+	}
+	slog.DebugContext(ctx, "Tool call completed", "duration", time.Since(start))
+	if err := s.checkResultSize(response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (s *MCPServer) dispatchTool(ctx context.Context, tool string, args map[string]interface{}) (interface{}, error) {
+	switch tool {
+	case "get_provider_intelligence":
+		return s.handleGetProviderIntelligence(ctx, args)
+	case "get_provider_by_host_uri":
+		return s.handleGetProviderByHostURI(ctx, args)
+	case "get_gpu_availability":
+		return s.handleGetGPUAvailability(ctx, args)
+	case "select_optimal_provider":
+		return s.handleSelectOptimalProvider(ctx, args)
+	case "compare_providers":
+		return s.handleCompareProviders(ctx, args)
+	case "list_providers":
+		return s.handleListProviders(ctx, args)
+	case "get_market_trends":
+		return s.handleGetMarketTrends(ctx, args)
+	case "get_network_stats":
+		return s.handleGetNetworkStats(ctx, args)
+	case "select_replica_providers":
+		return s.handleSelectReplicaProviders(ctx, args)
+	case "get_capacity_trends":
+		return s.handleGetCapacityTrends(ctx, args)
+	case "get_leaderboard":
+		return s.handleGetLeaderboard(ctx, args)
+	case "get_providers_by_tier":
+		return s.handleGetProvidersByTier(ctx, args)
+	case "explain_selection_change":
+		return s.handleExplainSelectionChange(ctx, args)
+	case "explain_scoring":
+		return s.handleExplainScoring(ctx, args)
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownTool, tool)
+	}
+}
+
 // Tool: Get Provider Intelligence
-func (s *MCPServer) handleGetProviderIntelligence(args map[string]interface{}) (interface{}, error) {
+func (s *MCPServer) handleGetProviderIntelligence(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	// Extract provider addresses from arguments
-	addresses, ok := args["provider_addresses"]
-	if !ok {
-		return nil, fmt.Errorf("provider_addresses argument is required")
+	providerAddresses, err := stringListArg(args, "provider_addresses")
+	if err != nil {
+		return nil, err
 	}
 
-	addressList, ok := addresses.([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("provider_addresses must be an array")
+	// Optional freshness contract (e.g. "30s")
+	var opts intelligence.FetchOptions
+	if maxAge, ok := args["max_data_age"].(string); ok && maxAge != "" {
+		parsed, err := parseFlexibleDuration(maxAge)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max_data_age: %w", err)
+		}
+		opts.MaxDataAge = parsed
 	}
 
-	// Convert to string slice
-	var providerAddresses []string
-	for _, addr := range addressList {
-		if strAddr, ok := addr.(string); ok {
-			providerAddresses = append(providerAddresses, strAddr)
-		}
+	// Optional fetch ordering
+	if order, ok := args["fetch_order"].(string); ok {
+		opts.Order = order
+	}
+	if err := intelligence.ValidateFetchOrder(opts.Order); err != nil {
+		return nil, err
+	}
+	hints, err := parseFetchHints(args["fetch_hints"])
+	if err != nil {
+		return nil, err
 	}
+	opts.Hints = hints
 
-	if len(providerAddresses) == 0 {
-		return nil, fmt.Errorf("no valid provider addresses provided")
+	// Responses are always bounded; large batches are paged
+	page, err := parsePage(args)
+	if err != nil {
+		return nil, err
+	}
+	opts.Page = &page
+
+	// Optional sparse fieldset, validated before any provider is fetched
+	var fields []string
+	if spec, ok := args["fields"].(string); ok {
+		if fields, err = intelligence.ParseFields(spec); err != nil {
+			return nil, err
+		}
 	}
 
 	// Use the intelligence service to get provider info
-	ctx := context.Background()
-	providers, err := s.intelligenceService.GetProviderIntelligence(ctx, providerAddresses)
+	result, err := s.intelligenceService.GetProviderIntelligenceWithOptions(ctx, providerAddresses, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get provider intelligence: %w", err)
 	}
 
-	return providers, nil
+	if fields != nil {
+		return result.WithFields(fields), nil
+	}
+	return result, nil
+}
+
+// Tool: Get Providers By Tier
+func (s *MCPServer) handleGetProvidersByTier(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	providerAddresses, err := stringListArg(args, "provider_addresses")
+	if err != nil {
+		return nil, err
+	}
+
+	grouped, err := s.intelligenceService.GetProvidersByTier(ctx, providerAddresses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get providers by tier: %w", err)
+	}
+
+	return grouped, nil
 }
 
 // Tool: Select Optimal Provider
-func (s *MCPServer) handleSelectOptimalProvider(args map[string]interface{}) (interface{}, error) {
+func (s *MCPServer) handleSelectOptimalProvider(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	// Extract requirements
 	requirements, ok := args["requirements"]
 	if !ok {
@@ -287,6 +1099,29 @@ func (s *MCPServer) handleSelectOptimalProvider(args map[string]interface{}) (in
 		return nil, fmt.Errorf("requirements must be an object")
 	}
 
+	criteria, err := s.buildSelectionCriteria(reqMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid requirements: %w", err)
+	}
+
+	// Deterministic mode over supplied snapshots needs no bids
+	if rawSnapshots, ok := args["snapshots"]; ok {
+		encoded, err := json.Marshal(rawSnapshots)
+		if err != nil {
+			return nil, fmt.Errorf("invalid snapshots: %w", err)
+		}
+		var snapshots []*akash.ProviderInfo
+		if err := json.Unmarshal(encoded, &snapshots); err != nil {
+			return nil, fmt.Errorf("snapshots must be an array of provider data: %w", err)
+		}
+
+		selection, err := s.intelligenceService.SelectFromSnapshots(ctx, snapshots, criteria)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select optimal provider: %w", err)
+		}
+		return selection, nil
+	}
+
 	// Extract provider bids
 	providerBids, ok := args["provider_bids"]
 	if !ok {
@@ -314,14 +1149,79 @@ func (s *MCPServer) handleSelectOptimalProvider(args map[string]interface{}) (in
 		return nil, fmt.Errorf("no valid provider addresses found in bids")
 	}
 
-	// Build selection criteria
+	// Deterministic mode over historical observations
+	if asOf, ok := args["as_of"].(string); ok {
+		at, err := time.Parse(time.RFC3339, asOf)
+		if err != nil {
+			return nil, fmt.Errorf("as_of must be an RFC3339 time: %w", err)
+		}
+
+		selection, err := s.intelligenceService.SelectAsOf(ctx, addresses, criteria, at)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select optimal provider: %w", err)
+		}
+		return selection, nil
+	}
+
+	if allPriorities, _ := args["all_priorities"].(bool); allPriorities {
+		selections, err := s.intelligenceService.SelectAcrossPriorities(ctx, addresses, criteria)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select optimal provider: %w", err)
+		}
+		return selections, nil
+	}
+
+	// Use intelligence service to select optimal provider
+	selection, err := s.intelligenceService.SelectOptimalProvider(ctx, addresses, criteria)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select optimal provider: %w", err)
+	}
+
+	return selection, nil
+}
+
+// Get the weights set in config, keyed by dimension name
+func configuredWeights(config *Config) map[string]float64 {
+	configured := make(map[string]float64)
+	for name, value := range map[string]*float64{
+		"price":        config.SelectionWeights.Price,
+		"reliability":  config.SelectionWeights.Reliability,
+		"performance":  config.SelectionWeights.Performance,
+		"geographic":   config.SelectionWeights.Geographic,
+		"stability":    config.SelectionWeights.Stability,
+		"provisioning": config.SelectionWeights.Provisioning,
+		"network":      config.SelectionWeights.Network,
+	} {
+		if value != nil {
+			configured[name] = *value
+		}
+	}
+	return configured
+}
+
+// Build selection criteria from configured weights and request requirements
+func (s *MCPServer) buildSelectionCriteria(reqMap map[string]interface{}) (intelligence.SelectionCriteria, error) {
+	// Per-request weight overrides take precedence over config
+	var overrides map[string]float64
+	if weightsMap, ok := reqMap["weights"].(map[string]interface{}); ok {
+		overrides = make(map[string]float64, len(weightsMap))
+		for name, value := range weightsMap {
+			weight, ok := value.(float64)
+			if !ok {
+				return intelligence.SelectionCriteria{}, fmt.Errorf("weight %q must be a number", name)
+			}
+			overrides[name] = weight
+		}
+	}
+
+	weights, provenance, err := intelligence.ResolveWeights(s.weights.get(), overrides)
+	if err != nil {
+		return intelligence.SelectionCriteria{}, err
+	}
+
 	criteria := intelligence.SelectionCriteria{
-		Weights: intelligence.Weights{
-			Price:       s.config.SelectionWeights.Price,
-			Reliability: s.config.SelectionWeights.Reliability,
-			Performance: s.config.SelectionWeights.Performance,
-			Geographic:  s.config.SelectionWeights.Geographic,
-		},
+		Weights:          weights,
+		WeightProvenance: provenance,
 	}
 
 	// Set priority from requirements
@@ -338,32 +1238,377 @@ func (s *MCPServer) handleSelectOptimalProvider(args map[string]interface{}) (in
 		}
 	}
 
-	// Use intelligence service to select optimal provider
-	ctx := context.Background()
-	selection, err := s.intelligenceService.SelectOptimalProvider(ctx, addresses, criteria)
+	// Set deployment duration from requirements (e.g. "720h" or "30d")
+	if duration, ok := reqMap["deployment_duration"].(string); ok {
+		parsed, err := parseFlexibleDuration(duration)
+		if err != nil {
+			return intelligence.SelectionCriteria{}, fmt.Errorf("invalid deployment_duration %q: %w", duration, err)
+		}
+		criteria.DeploymentDuration = parsed
+	}
+
+	// Set minimum available node count from requirements
+	if minNodes, ok := reqMap["min_available_nodes"].(float64); ok {
+		if minNodes < 0 {
+			return intelligence.SelectionCriteria{}, fmt.Errorf("min_available_nodes must not be negative")
+		}
+		criteria.MinAvailableNodes = int(minNodes)
+	}
+
+	// Prefer well-supported scores in near ties
+	if preferConfident, ok := reqMap["prefer_confident"].(bool); ok {
+		criteria.PreferConfident = preferConfident
+	}
+
+	// Set minimum advertised bandwidth from requirements ("1Gbps", or a number of Mbps)
+	switch minBandwidth := reqMap["min_bandwidth"].(type) {
+	case string:
+		mbps, err := intelligence.ParseBandwidth(minBandwidth)
+		if err != nil {
+			return intelligence.SelectionCriteria{}, fmt.Errorf("invalid min_bandwidth: %w", err)
+		}
+		criteria.MinBandwidthMbps = mbps
+	case float64:
+		if minBandwidth < 0 {
+			return intelligence.SelectionCriteria{}, fmt.Errorf("min_bandwidth must not be negative")
+		}
+		criteria.MinBandwidthMbps = minBandwidth
+	}
+
+	// Set failover try order length from requirements
+	if tryOrder, ok := reqMap["try_order"].(float64); ok {
+		if tryOrder < 0 {
+			return intelligence.SelectionCriteria{}, fmt.Errorf("try_order must not be negative")
+		}
+		criteria.TryOrder = int(tryOrder)
+	}
+
+	// Set required CPU architecture from requirements
+	if arch, ok := reqMap["cpu_arch"].(string); ok {
+		criteria.CPUArch = akash.NormalizeCPUArch(arch)
+	}
+
+	// Set reasoning rendering from requirements
+	if style, ok := reqMap["reasoning_style"].(string); ok {
+		criteria.ReasoningStyle = style
+	}
+	if units, ok := reqMap["units"].(string); ok {
+		criteria.Units = units
+	}
+	if err := intelligence.ValidateReasoningFormat(criteria.ReasoningStyle, criteria.Units); err != nil {
+		return intelligence.SelectionCriteria{}, err
+	}
+
+	// Set freshness contract from requirements (e.g. "30s")
+	if maxAge, ok := reqMap["max_data_age"].(string); ok && maxAge != "" {
+		parsed, err := parseFlexibleDuration(maxAge)
+		if err != nil {
+			return intelligence.SelectionCriteria{}, fmt.Errorf("invalid max_data_age: %w", err)
+		}
+		criteria.MaxDataAge = parsed
+	}
+
+	// Set fetch ordering from requirements
+	if order, ok := reqMap["fetch_order"].(string); ok {
+		criteria.FetchOrder = order
+	}
+	if err := intelligence.ValidateFetchOrder(criteria.FetchOrder); err != nil {
+		return intelligence.SelectionCriteria{}, err
+	}
+	hints, err := parseFetchHints(reqMap["fetch_hints"])
 	if err != nil {
-		return nil, fmt.Errorf("failed to select optimal provider: %w", err)
+		return intelligence.SelectionCriteria{}, err
 	}
+	criteria.FetchHints = hints
 
+	return criteria, nil
+}
+
+// Parse the offset and limit of a paged provider result
+func parsePage(args map[string]interface{}) (intelligence.Page, error) {
+	var page intelligence.Page
+	if offset, ok := args["offset"].(float64); ok {
+		if offset < 0 {
+			return page, fmt.Errorf("offset must not be negative")
+		}
+		page.Offset = int(offset)
+	}
+	if limit, ok := args["limit"].(float64); ok {
+		if limit < 0 {
+			return page, fmt.Errorf("limit must not be negative")
+		}
+		page.Limit = int(limit)
+	}
+	return page, nil
+}
+
+// Parse fetch priors keyed by provider address
+func parseFetchHints(raw interface{}) (map[string]float64, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	hintMap, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("fetch_hints must be an object of provider address to number")
+	}
+
+	hints := make(map[string]float64, len(hintMap))
+	for addr, value := range hintMap {
+		hint, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("fetch_hints[%s] must be a number", addr)
+		}
+		hints[addr] = hint
+	}
+	return hints, nil
+}
+
+// Read a required array argument of strings, skipping any other elements.
+// Every tool taking provider_addresses reads it this way, so they agree on
+// what a missing, malformed or empty list is.
+func stringListArg(args map[string]interface{}, name string) ([]string, error) {
+	raw, ok := args[name]
+	if !ok {
+		return nil, fmt.Errorf("%s argument is required", name)
+	}
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be an array", name)
+	}
+
+	values := make([]string, 0, len(list))
+	for _, item := range list {
+		if value, ok := item.(string); ok {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%s must contain at least one string", name)
+	}
+	return values, nil
+}
+
+// Tool: Explain Selection Change
+func (s *MCPServer) handleExplainSelectionChange(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	addresses, err := stringListArg(args, "provider_addresses")
+	if err != nil {
+		return nil, err
+	}
+
+	reqMap, _ := args["requirements"].(map[string]interface{})
+	criteria, err := s.buildSelectionCriteria(reqMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid requirements: %w", err)
+	}
+
+	fromStr, _ := args["from"].(string)
+	from, err := parseTimePoint(fromStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from: %w", err)
+	}
+
+	to := time.Now()
+	if toStr, ok := args["to"].(string); ok && toStr != "" {
+		if to, err = parseTimePoint(toStr); err != nil {
+			return nil, fmt.Errorf("invalid to: %w", err)
+		}
+	}
+
+	return s.intelligenceService.ExplainSelectionChange(ctx, addresses, criteria, from, to)
+}
+
+// Tool: Explain Scoring
+func (s *MCPServer) handleExplainScoring(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	addresses, err := stringListArg(args, "provider_addresses")
+	if err != nil {
+		return nil, err
+	}
+
+	reqMap, _ := args["requirements"].(map[string]interface{})
+	criteria, err := s.buildSelectionCriteria(reqMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid requirements: %w", err)
+	}
+
+	var opts intelligence.ExplainOptions
+	opts.Sensitivity, _ = args["sensitivity"].(bool)
+	if step, ok := args["sensitivity_step"].(float64); ok {
+		if step <= 0 {
+			return nil, fmt.Errorf("sensitivity_step must be positive")
+		}
+		opts.SensitivityStep = step
+	}
+
+	return s.intelligenceService.ExplainScoring(ctx, addresses, criteria, opts)
+}
+
+// Tool: Select Replica Providers
+func (s *MCPServer) handleSelectReplicaProviders(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	addresses, err := stringListArg(args, "provider_addresses")
+	if err != nil {
+		return nil, err
+	}
+
+	replicas, ok := args["replicas"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("replicas argument is required")
+	}
+
+	reqMap, _ := args["requirements"].(map[string]interface{})
+	criteria, err := s.buildSelectionCriteria(reqMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid requirements: %w", err)
+	}
+
+	var affinity intelligence.AntiAffinity
+	affinity.DistinctRegions, _ = args["distinct_regions"].(bool)
+	affinity.DistinctClusters, _ = args["distinct_clusters"].(bool)
+
+	selection, err := s.intelligenceService.SelectReplicas(ctx, addresses, criteria, int(replicas), affinity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select replica providers: %w", err)
+	}
 	return selection, nil
 }
 
-// Tool: Get Market Trends - PLACEHOLDER FOR NOW
-func (s *MCPServer) handleGetMarketTrends(args map[string]interface{}) (interface{}, error) {
-	timeframe := "24h"
-	if tf, ok := args["timeframe"]; ok {
-		if tfStr, ok := tf.(string); ok {
-			timeframe = tfStr
+// Parse a time point given either as RFC3339 or as a duration before now (e.g. "24h")
+func parseTimePoint(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, fmt.Errorf("time point is required")
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	ago, err := parseFlexibleDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither RFC3339 nor a duration", value)
+	}
+	return time.Now().Add(-ago), nil
+}
+
+// Parse a duration, additionally accepting whole days (e.g. "7d")
+func parseFlexibleDuration(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// Tool: Get Provider by Host URI
+func (s *MCPServer) handleGetProviderByHostURI(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	hostURI, ok := args["host_uri"].(string)
+	if !ok || hostURI == "" {
+		return nil, fmt.Errorf("host_uri must be a non-empty string")
+	}
+
+	return s.intelligenceService.GetProviderIntelligenceByHostURI(ctx, hostURI)
+}
+
+// Tool: Get GPU Availability
+func (s *MCPServer) handleGetGPUAvailability(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	addresses, err := stringListArg(args, "provider_addresses")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.intelligenceService.GetGPUAvailability(ctx, addresses)
+}
+
+// Tool: Compare Providers
+func (s *MCPServer) handleCompareProviders(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	addresses, err := stringListArg(args, "provider_addresses")
+	if err != nil {
+		return nil, err
+	}
+	if len(addresses) < 2 {
+		return nil, fmt.Errorf("at least two provider addresses are required")
+	}
+
+	reqMap, _ := args["requirements"].(map[string]interface{})
+	criteria, err := s.buildSelectionCriteria(reqMap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid requirements: %w", err)
+	}
+
+	return s.intelligenceService.CompareProviders(ctx, addresses, criteria)
+}
+
+// Tool: List Providers
+func (s *MCPServer) handleListProviders(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	auditedOnly, _ := args["audited_only"].(bool)
+
+	page, err := parsePage(args)
+	if err != nil {
+		return nil, err
+	}
+
+	listing, err := s.intelligenceService.ListProviders(ctx, auditedOnly, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return listing, nil
+}
+
+// Tool: Get Market Trends
+func (s *MCPServer) handleGetMarketTrends(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	timeframe := 24 * time.Hour
+	if tf, ok := args["timeframe"].(string); ok && tf != "" {
+		parsed, err := parseFlexibleDuration(tf)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeframe: %w", err)
 		}
+		timeframe = parsed
+	}
+
+	return s.intelligenceService.GetMarketTrends(timeframe)
+}
+
+// Tool: Get Network Stats
+func (s *MCPServer) handleGetNetworkStats(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	window := 24 * time.Hour
+	if w, ok := args["window"].(string); ok && w != "" {
+		parsed, err := time.ParseDuration(w)
+		if err != nil {
+			return nil, fmt.Errorf("invalid window: %w", err)
+		}
+		window = parsed
+	}
+
+	maxPoints := 0
+	if mp, ok := args["max_points"].(float64); ok {
+		maxPoints = int(mp)
 	}
 
-	// For now, return basic market analysis
-	// This could be enhanced with historical data
-	return map[string]interface{}{
-		"timeframe": timeframe,
-		"message":   "Market trends analysis - would integrate with historical provider data",
-		"status":    "placeholder_implementation",
-	}, nil
+	return s.intelligenceService.GetNetworkStatsSeries(window, maxPoints)
+}
+
+// Tool: Get Capacity Trends
+func (s *MCPServer) handleGetCapacityTrends(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	window := 24 * time.Hour
+	if w, ok := args["window"].(string); ok && w != "" {
+		parsed, err := time.ParseDuration(w)
+		if err != nil {
+			return nil, fmt.Errorf("invalid window: %w", err)
+		}
+		window = parsed
+	}
+
+	return s.intelligenceService.GetCapacityTrends(window)
+}
+
+// Tool: Get Leaderboard
+func (s *MCPServer) handleGetLeaderboard(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	page, err := parsePage(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.intelligenceService.GetLeaderboard(page)
 }
 
 // Add these missing handler methods to cmd/server/main.go
@@ -373,13 +1618,35 @@ func (s *MCPServer) handleHealth(w http.ResponseWriter, r *http.Request) {
 	health := map[string]interface{}{
 		"status":    "healthy",
 		"timestamp": time.Now().UTC(),
-		"version":   "1.0.0",
+		"version":   serverVersion,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(health)
 }
 
+// Liveness: answers as long as the process does, whatever its dependencies
+func (s *MCPServer) handleLivez(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  "alive",
+		"uptime":  time.Since(startTime).Round(time.Second).String(),
+		"version": serverVersion,
+	})
+}
+
+// Readiness: 503 with the failing checks while the Akash gRPC endpoint is
+// unreachable, a background loop has stopped or the server is shutting down
+func (s *MCPServer) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	readiness := s.intelligenceService.CheckReadiness(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	if !readiness.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(readiness)
+}
+
 // Status endpoint for debugging
 func (s *MCPServer) handleStatus(w http.ResponseWriter, r *http.Request) {
 	status := map[string]interface{}{
@@ -389,8 +1656,12 @@ func (s *MCPServer) handleStatus(w http.ResponseWriter, r *http.Request) {
 			"host":   s.config.Server.Host,
 		},
 		"intelligence": map[string]interface{}{
-			"cache_size": s.intelligenceService.GetCacheStats(),
+			"cache_size":    s.intelligenceService.GetCacheStats(),
+			"history":       s.intelligenceService.GetHistoryStats(),
+			"blacklist":     s.intelligenceService.GetBlacklistStatus(),
+			"open_circuits": s.intelligenceService.OpenCircuits(),
 		},
+		"tools":  s.toolLimits.stats(),
 		"config": s.config,
 	}
 
@@ -399,20 +1670,86 @@ func (s *MCPServer) handleStatus(w http.ResponseWriter, r *http.Request) {
 }
 
 func main() {
+	// One-shot subcommands
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		os.Exit(runCheck(os.Args[2:]))
+	}
+
 	// Command line flags
 	configPath := flag.String("config", "config.yaml", "Path to configuration file")
+	transport := flag.String("transport", "http", "MCP transport: http, or stdio when launched by an MCP host")
 	flag.Parse()
 
+	if *transport != "http" && *transport != "stdio" {
+		fatal("Unknown transport (valid: http, stdio)", "transport", *transport)
+	}
+
+	// Over stdio, stdout carries protocol messages only, so progress output goes to stderr
+	protocolOut := os.Stdout
+	if *transport == "stdio" {
+		os.Stdout = os.Stderr
+	}
+
 	// Load configuration
 	config, err := loadConfig(*configPath)
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		fatal("Failed to load config", "path", *configPath, "error", err)
 	}
 
+	// Structured logs on stderr, which stays free of protocol messages on either transport
+	err = telemetry.SetupLogging(os.Stderr, telemetry.LogConfig{
+		Level:  config.Logging.Level,
+		Format: config.Logging.Format,
+	})
+	if err != nil {
+		fatal("Failed to set up logging", "error", err)
+	}
+
+	// Register custom scoring plugins before the service validates its pipeline
+	if err := registerScorers(); err != nil {
+		fatal("Failed to register scoring plugins", "error", err)
+	}
+
+	// Export traces when enabled
+	shutdownTracing, err := telemetry.SetupTracing(context.Background(), telemetry.Config{
+		Enabled:      config.Tracing.Enabled,
+		OTLPEndpoint: config.Tracing.OTLPEndpoint,
+		Insecure:     config.Tracing.Insecure,
+		SampleRatio:  config.Tracing.SampleRatio,
+		ServiceName:  config.Tracing.ServiceName,
+	})
+	if err != nil {
+		fatal("Failed to set up tracing", "error", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Warn("Failed to flush traces", "error", err)
+		}
+	}()
+
 	// Create MCP server
 	server, err := NewMCPServer(config)
 	if err != nil {
-		log.Fatalf("Failed to create server: %v", err)
+		fatal("Failed to create server", "error", err)
+	}
+
+	// Pick up weight and cache TTL changes without losing the warm cache
+	go server.watchConfig(*configPath)
+
+	drainTimeout := config.Server.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = defaultDrainTimeout
+	}
+
+	if *transport == "stdio" {
+		slog.Info("Akash Provider Intelligence MCP Server serving on stdio")
+		if err := server.serveStdio(context.Background(), os.Stdin, protocolOut); err != nil {
+			fatal("Server failed", "error", err)
+		}
+		server.closeService(drainTimeout)
+		return
 	}
 
 	// Start HTTP server
@@ -424,25 +1761,112 @@ func main() {
 		WriteTimeout: config.Server.Timeout,
 	}
 
-	// Graceful shutdown
+	// TLS termination, when a key pair is configured
+	var certs *certReloader
+	if config.Server.TLS.CertFile != "" || config.Server.TLS.KeyFile != "" {
+		certs, err = newCertReloader(config.Server.TLS.CertFile, config.Server.TLS.KeyFile)
+		if err != nil {
+			fatal("Failed to set up TLS", "error", err)
+		}
+		httpServer.TLSConfig = certs.tlsConfig()
+		go certs.watch()
+	}
+
+	// gRPC API alongside HTTP, when enabled
+	var grpcServer *grpc.Server
+	if config.Server.GRPCPort > 0 {
+		grpcAddr := fmt.Sprintf("%s:%d", config.Server.Host, config.Server.GRPCPort)
+		listener, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			fatal("Failed to listen for gRPC", "addr", grpcAddr, "error", err)
+		}
+		grpcServer = server.newGRPCServer(certs)
+		go func() {
+			slog.Info("gRPC API listening", "addr", grpcAddr)
+			if err := grpcServer.Serve(listener); err != nil {
+				slog.Warn("gRPC server stopped", "error", err)
+			}
+		}()
+	}
+
+	// Graceful shutdown: stop accepting connections, let in-flight calls finish
+	// within the drain timeout, then stop background work
+	stopped := make(chan struct{})
 	go func() {
+		defer close(stopped)
 		sigChan := make(chan os.Signal, 1)
 		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 		<-sigChan
 
-		log.Println("🛑 Shutting down server...")
-		httpServer.Close()
+		slog.Info("Shutting down server", "drain_timeout", drainTimeout)
+		ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+		defer cancel()
+
+		var drained sync.WaitGroup
+		if grpcServer != nil {
+			drained.Add(1)
+			go func() {
+				defer drained.Done()
+				stopGRPC(ctx, grpcServer)
+			}()
+		}
+		if err := httpServer.Shutdown(ctx); err != nil {
+			slog.Warn("Drain timed out, closing remaining connections", "error", err)
+			httpServer.Close()
+		}
+		drained.Wait()
+
+		server.closeService(drainTimeout)
 	}()
 
 	// Start server
-	log.Printf("🚀 Akash Provider Intelligence MCP Server starting on %s", addr)
-	log.Printf("📊 Health check: http://%s/health", addr)
-	log.Printf("🔧 Status: http://%s/status", addr)
-	log.Printf("🛠️  Tools: http://%s/tools", addr)
+	scheme := "http"
+	if certs != nil {
+		scheme = "https"
+	}
+	slog.Info("Akash Provider Intelligence MCP Server starting", "addr", addr,
+		"health", fmt.Sprintf("%s://%s/health", scheme, addr),
+		"status", fmt.Sprintf("%s://%s/status", scheme, addr),
+		"tools", fmt.Sprintf("%s://%s/tools", scheme, addr))
+
+	if certs != nil {
+		err = httpServer.ListenAndServeTLS("", "")
+	} else {
+		err = httpServer.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
+		fatal("Server failed to start", "error", err)
+	}
+
+	<-stopped
+	slog.Info("Server stopped gracefully")
+}
 
-	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("Server failed to start: %v", err)
+// Let in-flight gRPC calls finish until ctx is done, then cut off the rest
+func stopGRPC(ctx context.Context, server *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		server.Stop()
+	}
+}
+
+// Stop background work and save the provider cache, waiting at most timeout
+func (s *MCPServer) closeService(timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := s.intelligenceService.Close(ctx); err != nil {
+		slog.Warn("Intelligence service did not stop cleanly", "error", err)
 	}
+}
 
-	log.Println("✅ Server stopped gracefully")
+// Log an error and exit, for failures the server cannot start without
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
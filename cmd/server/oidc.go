@@ -0,0 +1,321 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Clock skew tolerated on exp and nbf
+	jwtLeeway = time.Minute
+
+	// Signing keys are refetched after this long, or sooner for an unknown kid
+	jwksTTL = time.Hour
+
+	// Refetches for unknown kids are spaced at least this far apart
+	jwksMinRefresh = time.Minute
+)
+
+var errToolForbidden = errors.New("forbidden")
+
+// Validates bearer JWTs issued by an OIDC provider
+type jwtVerifier struct {
+	issuer   string
+	jwksURL  string
+	audience string
+	client   *http.Client
+
+	keys      map[string]crypto.PublicKey // by kid
+	fetchedAt time.Time
+	mutex     sync.Mutex
+}
+
+// Claims this server reads from a token
+type jwtClaims struct {
+	Issuer    string          `json:"iss"`
+	Subject   string          `json:"sub"`
+	Audience  json.RawMessage `json:"aud"` // string or array
+	ExpiresAt *float64        `json:"exp"`
+	NotBefore *float64        `json:"nbf"`
+	Scope     string          `json:"scope"` // space separated
+	Scp       []string        `json:"scp"`   // array form used by some providers
+}
+
+func (c *jwtClaims) audiences() []string {
+	var single string
+	if json.Unmarshal(c.Audience, &single) == nil {
+		return []string{single}
+	}
+	var list []string
+	json.Unmarshal(c.Audience, &list)
+	return list
+}
+
+func (c *jwtClaims) scopes() []string {
+	return append(strings.Fields(c.Scope), c.Scp...)
+}
+
+func newJWTVerifier(issuer, jwksURL, audience string) *jwtVerifier {
+	return &jwtVerifier{
+		issuer:   strings.TrimSuffix(issuer, "/"),
+		jwksURL:  jwksURL,
+		audience: audience,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Verify a compact JWS token's signature, issuer, audience and validity window
+func (v *jwtVerifier) verify(ctx context.Context, token string) (*jwtClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed token header: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %w", err)
+	}
+
+	key, err := v.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %w", err)
+	}
+
+	now := time.Now()
+	if claims.ExpiresAt == nil || now.After(time.Unix(int64(*claims.ExpiresAt), 0).Add(jwtLeeway)) {
+		return nil, fmt.Errorf("token expired")
+	}
+	if claims.NotBefore != nil && now.Add(jwtLeeway).Before(time.Unix(int64(*claims.NotBefore), 0)) {
+		return nil, fmt.Errorf("token not yet valid")
+	}
+	if v.issuer != "" && strings.TrimSuffix(claims.Issuer, "/") != v.issuer {
+		return nil, fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+	if v.audience != "" && !containsString(claims.audiences(), v.audience) {
+		return nil, fmt.Errorf("token is not for audience %q", v.audience)
+	}
+	return &claims, nil
+}
+
+func decodeSegment(segment string, target interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+func containsString(values []string, want string) bool {
+	for _, value := range values {
+		if value == want {
+			return true
+		}
+	}
+	return false
+}
+
+// Check a JWS signature; only asymmetric algorithms are accepted, so a token
+// can't be signed with the public key as an HMAC secret
+func verifySignature(alg string, key crypto.PublicKey, signingInput string, signature []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+	hasher := hash.New()
+	hasher.Write([]byte(signingInput))
+	digest := hasher.Sum(nil)
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return fmt.Errorf("algorithm %s does not match the RSA key", alg)
+		}
+		if err := rsa.VerifyPKCS1v15(key, hash, digest, signature); err != nil {
+			return fmt.Errorf("invalid token signature")
+		}
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if !strings.HasPrefix(alg, "ES") || len(signature) != 2*size {
+			return fmt.Errorf("algorithm %s does not match the EC key", alg)
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(key, digest, r, s) {
+			return fmt.Errorf("invalid token signature")
+		}
+	default:
+		return fmt.Errorf("unsupported key type")
+	}
+	return nil
+}
+
+// Signing key by kid, fetching the key set when it is stale or lacks the kid
+func (v *jwtVerifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	key, ok := v.keys[kid]
+	stale := time.Since(v.fetchedAt) > jwksTTL
+	if ok && !stale {
+		return key, nil
+	}
+	if !stale && time.Since(v.fetchedAt) < jwksMinRefresh {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	keys, err := v.fetchKeys(ctx)
+	if err != nil {
+		if ok {
+			return key, nil // keep using known keys while the issuer is unreachable
+		}
+		return nil, fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+	v.keys, v.fetchedAt = keys, time.Now()
+
+	if key, ok = v.keys[kid]; !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// Fetch the JWKS, discovering its URL from the issuer's OIDC metadata when not configured
+func (v *jwtVerifier) fetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	jwksURL := v.jwksURL
+	if jwksURL == "" {
+		var discovery struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := v.getJSON(ctx, v.issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+			return nil, fmt.Errorf("OIDC discovery failed: %w", err)
+		}
+		if discovery.JWKSURI == "" {
+			return nil, fmt.Errorf("OIDC discovery returned no jwks_uri")
+		}
+		jwksURL = discovery.JWKSURI
+	}
+
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := v.getJSON(ctx, jwksURL, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		switch jwk.Kty {
+		case "RSA":
+			n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+			e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+			if errN != nil || errE != nil {
+				continue
+			}
+			keys[jwk.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case "EC":
+			var curve elliptic.Curve
+			switch jwk.Crv {
+			case "P-256":
+				curve = elliptic.P256()
+			case "P-384":
+				curve = elliptic.P384()
+			case "P-521":
+				curve = elliptic.P521()
+			default:
+				continue
+			}
+			x, errX := base64.RawURLEncoding.DecodeString(jwk.X)
+			y, errY := base64.RawURLEncoding.DecodeString(jwk.Y)
+			if errX != nil || errY != nil {
+				continue
+			}
+			keys[jwk.Kid] = &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		}
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no usable signing keys at %s", jwksURL)
+	}
+	return keys, nil
+}
+
+func (v *jwtVerifier) getJSON(ctx context.Context, url string, target interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+type scopesKey struct{}
+
+// Record the scopes a token granted; calls without them (API keys, open
+// servers) are not scope checked
+func withScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, scopesKey{}, scopes)
+}
+
+// Refuse a tool call when the caller's token lacks the scope configured for the tool
+func (s *MCPServer) authorizeTool(ctx context.Context, tool string) error {
+	scopes, ok := ctx.Value(scopesKey{}).([]string)
+	if !ok {
+		return nil
+	}
+	required := s.config.Server.OIDC.ToolScopes[tool]
+	if required == "" || containsString(scopes, required) {
+		return nil
+	}
+	return fmt.Errorf("%w: calling %s requires scope %q", errToolForbidden, tool, required)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// GET /openapi.json - OpenAPI 3.1 description of the REST surface. Request
+// schemas are generated from the tool definitions, so the document can't
+// drift from what the tools accept.
+func (s *MCPServer) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(openAPIDocument())
+}
+
+func openAPIDocument() map[string]interface{} {
+	schemas := map[string]interface{}{}
+	var toolNames []interface{}
+	for _, definition := range toolDefinitions() {
+		name := definition["name"].(string)
+		toolNames = append(toolNames, name)
+		schemas[name] = definition["parameters"]
+	}
+
+	jsonResponse := func(description string) map[string]interface{} {
+		return map[string]interface{}{
+			"description": description,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": map[string]interface{}{"type": "object"},
+				},
+			},
+		}
+	}
+	errorResponse := map[string]interface{}{"description": "Plain text error"}
+	toolRef := func(tool string) map[string]interface{} {
+		return map[string]interface{}{"$ref": "#/components/schemas/" + tool}
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.1.0",
+		"info": map[string]interface{}{
+			"title":       serverName,
+			"version":     serverVersion,
+			"description": "Akash provider intelligence over REST. Every MCP tool is also callable through POST /call.",
+		},
+		"paths": map[string]interface{}{
+			"/api/v1/providers": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary": "Provider intelligence as JSON or CSV",
+					"parameters": []interface{}{
+						queryParameter("addresses", "Comma separated provider addresses", map[string]interface{}{"type": "string"}, true),
+						queryParameter("offset", "Skip this many providers", map[string]interface{}{"type": "integer"}, false),
+						queryParameter("limit", "Providers per page, capped at max_result_providers", map[string]interface{}{"type": "integer"}, false),
+						queryParameter("fields", "Comma separated fields to return (JSON only)", map[string]interface{}{"type": "string"}, false),
+						queryParameter("format", "json or csv", map[string]interface{}{"type": "string", "enum": []string{"json", "csv"}}, false),
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Provider intelligence; paged results set X-Total-Count and X-Next-Offset",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{"schema": map[string]interface{}{"type": "object"}},
+								"text/csv":         map[string]interface{}{"schema": map[string]interface{}{"type": "string"}},
+							},
+						},
+						"400": errorResponse,
+					},
+				},
+			},
+			"/api/v1/selection": map[string]interface{}{
+				"post": map[string]interface{}{
+					"summary": "Select the optimal provider; the body takes select_optimal_provider's arguments",
+					"requestBody": map[string]interface{}{
+						"required": true,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{"schema": toolRef("select_optimal_provider")},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": jsonResponse("Provider selection"),
+						"400": errorResponse,
+						"422": errorResponse,
+						"500": errorResponse,
+						"503": errorResponse,
+					},
+				},
+			},
+			"/api/v1/market": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary":    "Network price percentiles and trends",
+					"parameters": toolQueryParameters("get_market_trends"),
+					"responses": map[string]interface{}{
+						"200": jsonResponse("Market trends"),
+						"500": errorResponse,
+						"503": errorResponse,
+					},
+				},
+			},
+			"/call": map[string]interface{}{
+				"post": map[string]interface{}{
+					"summary": "Call an MCP tool",
+					"requestBody": map[string]interface{}{
+						"required": true,
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": map[string]interface{}{
+									"type":     "object",
+									"required": []string{"tool"},
+									"properties": map[string]interface{}{
+										"tool":      map[string]interface{}{"type": "string", "enum": toolNames},
+										"arguments": map[string]interface{}{"type": "object", "description": "See the schema named after the tool"},
+									},
+								},
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"200": jsonResponse("Tool result as content and structuredContent"),
+						"400": errorResponse,
+						"422": errorResponse,
+						"500": errorResponse,
+						"503": errorResponse,
+					},
+				},
+			},
+		},
+		"components": map[string]interface{}{
+			"schemas": schemas,
+		},
+	}
+}
+
+func queryParameter(name, description string, schema map[string]interface{}, required bool) map[string]interface{} {
+	return map[string]interface{}{
+		"name":        name,
+		"in":          "query",
+		"description": description,
+		"required":    required,
+		"schema":      schema,
+	}
+}
+
+// Query parameters for a REST endpoint backed by a tool with scalar arguments
+func toolQueryParameters(tool string) []interface{} {
+	var parameters []interface{}
+	for _, definition := range toolDefinitions() {
+		if definition["name"] != tool {
+			continue
+		}
+		properties, _ := definition["parameters"].(map[string]interface{})["properties"].(map[string]interface{})
+		for name, raw := range properties {
+			property, _ := raw.(map[string]interface{})
+			schema := map[string]interface{}{"type": property["type"]}
+			if value, ok := property["default"]; ok {
+				schema["default"] = value
+			}
+			description, _ := property["description"].(string)
+			parameters = append(parameters, queryParameter(name, description, schema, false))
+		}
+	}
+	return parameters
+}
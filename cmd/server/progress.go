@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Turns provider fetches during a tool call into MCP progress notifications.
+// Counts accumulate across fetch batches so progress only ever increases.
+type toolProgress struct {
+	ctx   context.Context
+	token json.RawMessage
+	done  int
+	total int
+	mutex sync.Mutex
+}
+
+// Report progress for a call when the client asked for it with a progress token
+func withToolProgress(ctx context.Context, token json.RawMessage) context.Context {
+	if len(token) == 0 {
+		return ctx
+	}
+	return akash.WithProgress(ctx, &toolProgress{ctx: ctx, token: token})
+}
+
+func (p *toolProgress) Queued(providers int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.total += providers
+	p.send(fmt.Sprintf("fetching %d providers", providers))
+}
+
+func (p *toolProgress) Completed(address string, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.done++
+	if err != nil {
+		p.send(fmt.Sprintf("%s failed", address))
+		return
+	}
+	p.send(fmt.Sprintf("fetched %s", address))
+}
+
+// Callers hold the mutex, so notifications go out in order
+func (p *toolProgress) send(message string) {
+	notify(p.ctx, "notifications/progress", map[string]interface{}{
+		"progressToken": p.token,
+		"progress":      p.done,
+		"total":         p.total,
+		"message":       message,
+	})
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type promptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+}
+
+// Prebuilt prompt that steers a model through the intelligence tools
+type promptDefinition struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Arguments   []promptArgument `json:"arguments"`
+
+	render func(args map[string]string) string
+}
+
+var prompts = []promptDefinition{
+	{
+		Name:        "select_provider_for_sdl",
+		Description: "Pick the best Akash provider for a deployment described by an SDL",
+		Arguments: []promptArgument{
+			{Name: "sdl", Description: "The deployment's SDL (YAML)", Required: true},
+			{Name: "priority", Description: "What matters most: cost, reliability or performance"},
+			{Name: "provider_addresses", Description: "Comma separated candidate providers, e.g. the bidders; discovered with list_providers if omitted"},
+		},
+		render: func(args map[string]string) string {
+			var text strings.Builder
+			text.WriteString("Choose the best Akash provider for the deployment below.\n\n")
+			text.WriteString("1. Read the SDL and note what each service needs: CPU, memory, storage, GPU models and count, and any placement attributes such as region.\n")
+			if candidates := args["provider_addresses"]; candidates != "" {
+				fmt.Fprintf(&text, "2. The candidates are: %s.\n", candidates)
+			} else {
+				text.WriteString("2. Find candidates with list_providers (use audited_only if the SDL requires signed-by attributes).\n")
+			}
+			text.WriteString("3. Call select_optimal_provider with those candidates as provider_bids. Set requirements from the SDL: cpu_arch, min_available_nodes, and try_order 3 so there are fallbacks.")
+			if priority := args["priority"]; priority != "" {
+				fmt.Fprintf(&text, " Use priority %q.", priority)
+			}
+			text.WriteString("\n")
+			text.WriteString("4. If the workload needs GPUs, confirm the winner's free GPUs with get_gpu_availability.\n")
+			text.WriteString("5. Recommend one provider and explain why in terms of the SDL's needs, naming the runner-up and any caveats from the reasoning (unproven providers, maintenance, missing data).\n\n")
+			fmt.Fprintf(&text, "SDL:\n```yaml\n%s\n```\n", strings.TrimSpace(args["sdl"]))
+			return text.String()
+		},
+	},
+	{
+		Name:        "explain_provider_health",
+		Description: "Explain what drives an Akash provider's health score and how it ranks",
+		Arguments: []promptArgument{
+			{Name: "address", Description: "Provider address (akash1...)", Required: true},
+		},
+		render: func(args map[string]string) string {
+			address := args["address"]
+			var text strings.Builder
+			fmt.Fprintf(&text, "Explain the health of Akash provider %s.\n\n", address)
+			fmt.Fprintf(&text, "1. Call get_provider_intelligence for %s and read its health_score, smoothed_health_score, status query time, active leases, available resources and any failure diagnostics.\n", address)
+			fmt.Fprintf(&text, "2. Call explain_scoring for %s to see the weighted contribution of each dimension, including any missing_data.\n", address)
+			text.WriteString("3. Explain in plain language which inputs raise or lower the score, whether it looks stable or noisy, and what the provider could do to improve it.\n")
+			return text.String()
+		},
+	},
+}
+
+func (s *MCPServer) rpcListPrompts() interface{} {
+	return map[string]interface{}{"prompts": prompts}
+}
+
+// Render a prompt with its arguments into a user message
+func (s *MCPServer) rpcGetPrompt(request *rpcRequest) (interface{}, *rpcResponse) {
+	var params struct {
+		Name      string            `json:"name"`
+		Arguments map[string]string `json:"arguments"`
+	}
+	if err := json.Unmarshal(request.Params, &params); err != nil || params.Name == "" {
+		return nil, rpcFailure(request.ID, rpcInvalidParams, "prompts/get requires params with a prompt name")
+	}
+
+	for _, prompt := range prompts {
+		if prompt.Name != params.Name {
+			continue
+		}
+		for _, argument := range prompt.Arguments {
+			if argument.Required && strings.TrimSpace(params.Arguments[argument.Name]) == "" {
+				return nil, rpcFailure(request.ID, rpcInvalidParams, "prompt %s requires argument %s", prompt.Name, argument.Name)
+			}
+		}
+		return map[string]interface{}{
+			"description": prompt.Description,
+			"messages": []map[string]interface{}{
+				{
+					"role":    "user",
+					"content": map[string]interface{}{"type": "text", "text": prompt.render(params.Arguments)},
+				},
+			},
+		}, nil
+	}
+
+	return nil, rpcFailure(request.ID, rpcInvalidParams, "unknown prompt: %s", params.Name)
+}
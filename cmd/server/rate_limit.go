@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Buckets untouched for this long are full again and are dropped
+const rateLimitIdle = 10 * time.Minute
+
+// Token bucket per client, so one misbehaving client can't take every
+// provider query slot from the rest
+type rateLimiter struct {
+	rate      float64 // tokens added per second
+	burst     float64
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	mutex     sync.Mutex
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Create a limiter; nil when rate is not positive, which disables limiting
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = int(math.Max(1, math.Ceil(rate)))
+	}
+	return &rateLimiter{
+		rate:      rate,
+		burst:     float64(burst),
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// Take n tokens from the client's bucket. When there aren't enough, nothing is
+// taken and the wait until there will be is returned.
+func (l *rateLimiter) take(client string, n float64) (bool, time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) > rateLimitIdle {
+		for key, bucket := range l.buckets {
+			if now.Sub(bucket.last) > rateLimitIdle {
+				delete(l.buckets, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	bucket, ok := l.buckets[client]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[client] = bucket
+	}
+	bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+	bucket.last = now
+
+	if bucket.tokens >= n {
+		bucket.tokens -= n
+		return true, 0
+	}
+	wait := time.Duration((n - bucket.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+// Take n tokens for the request's client. When it is over its limit nothing
+// is taken and the reason is returned, with the seconds until enough tokens
+// are back, or 0 when n exceeds the burst and can never pass.
+func (s *MCPServer) chargeRateLimit(r *http.Request, n int) (string, int) {
+	if s.rateLimiter == nil || n <= 0 {
+		return "", 0
+	}
+	allowed, wait := s.rateLimiter.take(requestClient(r), float64(n))
+	if allowed {
+		return "", 0
+	}
+	if float64(n) > s.rateLimiter.burst {
+		return fmt.Sprintf("Request exceeds the rate limit burst of %.0f calls", s.rateLimiter.burst), 0
+	}
+	return "Rate limit exceeded, retry later", int(math.Ceil(wait.Seconds()))
+}
+
+// Take n tokens for the request's client, answering 429 with Retry-After when
+// it is over its limit
+func (s *MCPServer) rejectOverRateLimit(w http.ResponseWriter, r *http.Request, n int) bool {
+	rejected, retryAfter := s.chargeRateLimit(r, n)
+	if rejected == "" {
+		return false
+	}
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	}
+	http.Error(w, rejected, http.StatusTooManyRequests)
+	return true
+}
+
+// Take n tokens for the request's client, returning the JSON-RPC error to
+// answer with when it is over its limit
+func (s *MCPServer) rpcOverRateLimit(w http.ResponseWriter, r *http.Request, n int) *rpcResponse {
+	rejected, retryAfter := s.chargeRateLimit(r, n)
+	if rejected == "" {
+		return nil
+	}
+	failure := rpcFailure(nil, rpcRateLimited, "%s", rejected)
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		failure.Error.Data = map[string]int{"retry_after": retryAfter}
+	}
+	return failure
+}
+
+// Middleware: charge each request one token from its client's bucket. Wrapped
+// in authMiddleware, so buckets follow verified clients.
+func (s *MCPServer) rateLimitMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.rejectOverRateLimit(w, r, 1) {
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/intelligence"
+	"github.com/chainzero/akash-provider-intelligence/internal/telemetry"
+)
+
+// How often the config file is checked for changes
+const configCheckInterval = 5 * time.Second
+
+// Selection weights from config, swapped on reload
+type reloadableWeights struct {
+	configured map[string]float64
+	mutex      sync.RWMutex
+}
+
+func (w *reloadableWeights) get() map[string]float64 {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.configured
+}
+
+func (w *reloadableWeights) set(configured map[string]float64) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.configured = configured
+}
+
+// Reload the config file on SIGHUP or when it changes on disk. Only selection
+// weights, the cache TTL and the log level are applied; everything else needs
+// a restart.
+func (s *MCPServer) watchConfig(path string) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	ticker := time.NewTicker(configCheckInterval)
+	defer ticker.Stop()
+
+	lastModified := fileModTime(path)
+	for {
+		select {
+		case <-hup:
+		case <-ticker.C:
+			modified := fileModTime(path)
+			if !modified.After(lastModified) {
+				continue
+			}
+			lastModified = modified
+		}
+
+		if err := s.reloadConfig(path); err != nil {
+			slog.Warn("Config reload failed, keeping the running config", "path", path, "error", err)
+			continue
+		}
+		slog.Info("Config reloaded", "path", path)
+	}
+}
+
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// Validate the file's reloadable settings, then apply them together so a bad
+// value leaves the running config untouched
+func (s *MCPServer) reloadConfig(path string) error {
+	config, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+
+	weights := configuredWeights(config)
+	leaderboardWeights, _, err := intelligence.ResolveWeights(weights, nil)
+	if err != nil {
+		return fmt.Errorf("invalid selection_weights: %w", err)
+	}
+	if _, err := telemetry.ParseLogLevel(config.Logging.Level); err != nil {
+		return fmt.Errorf("invalid logging.level: %w", err)
+	}
+	if config.Intelligence.CacheTTL <= 0 {
+		return fmt.Errorf("invalid intelligence.cache_ttl: must be positive, got %v", config.Intelligence.CacheTTL)
+	}
+
+	err = s.intelligenceService.Reconfigure(intelligence.RuntimeSettings{
+		CacheTTL:           config.Intelligence.CacheTTL,
+		LeaderboardWeights: leaderboardWeights,
+	})
+	if err != nil {
+		return err
+	}
+	s.weights.set(weights)
+	return telemetry.SetLogLevel(config.Logging.Level)
+}
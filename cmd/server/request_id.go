@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/telemetry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// Middleware: give every request an ID, the client's X-Request-ID when it is
+// usable or a generated one otherwise, and return it in X-Request-ID on every
+// response, errors included
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !telemetry.ValidRequestID(id) {
+			id = telemetry.NewRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(telemetry.WithRequestID(r.Context(), id)))
+	})
+}
+
+// gRPC interceptor: the same for x-request-id metadata, returned as a header
+func grpcRequestIDInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDHeader); len(values) > 0 {
+			id = values[0]
+		}
+	}
+	if !telemetry.ValidRequestID(id) {
+		id = telemetry.NewRequestID()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, id))
+	return handler(telemetry.WithRequestID(ctx, id), req)
+}
+
+// Make sure a tool call has a request ID, for transports without one (stdio)
+func ensureRequestID(ctx context.Context) context.Context {
+	if telemetry.RequestID(ctx) != "" {
+		return ctx
+	}
+	return telemetry.WithRequestID(ctx, telemetry.NewRequestID())
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const providerURIPrefix = "akash://provider/"
+
+// MCP error code for an unknown resource
+const rpcResourceNotFound = -32002
+
+// List every provider currently cached as a resource
+func (s *MCPServer) rpcListResources() interface{} {
+	cached := s.intelligenceService.CachedProviders()
+
+	resources := make([]map[string]interface{}, 0, len(cached))
+	for _, entry := range cached {
+		description := "Cached provider intelligence"
+		if entry.Info.HostURI != "" {
+			description = fmt.Sprintf("Cached provider intelligence for %s", entry.Info.HostURI)
+		}
+		resources = append(resources, map[string]interface{}{
+			"uri":         providerURIPrefix + entry.Info.Address,
+			"name":        entry.Info.Address,
+			"description": description,
+			"mimeType":    "application/json",
+		})
+	}
+	return map[string]interface{}{"resources": resources}
+}
+
+// Any provider is addressable, cached or not
+func (s *MCPServer) rpcListResourceTemplates() interface{} {
+	return map[string]interface{}{
+		"resourceTemplates": []map[string]interface{}{
+			{
+				"uriTemplate": providerURIPrefix + "{address}",
+				"name":        "Akash provider",
+				"description": "Provider intelligence (ProviderInfo) for an akash1... address, served from cache when fresh",
+				"mimeType":    "application/json",
+			},
+		},
+	}
+}
+
+// Read a provider resource. Fresh cache entries are returned as they are; others
+// are fetched, exactly as get_provider_intelligence would.
+func (s *MCPServer) rpcReadResource(ctx context.Context, request *rpcRequest) (interface{}, *rpcResponse) {
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(request.Params, &params); err != nil || params.URI == "" {
+		return nil, rpcFailure(request.ID, rpcInvalidParams, "resources/read requires params with a uri")
+	}
+
+	address, ok := strings.CutPrefix(params.URI, providerURIPrefix)
+	if !ok || !strings.HasPrefix(address, "akash1") || strings.Contains(address, "/") {
+		return nil, rpcFailure(request.ID, rpcInvalidParams, "unsupported resource uri %q (expected %s{address})", params.URI, providerURIPrefix)
+	}
+
+	result, err := s.intelligenceService.GetProviderIntelligence(ctx, []string{address})
+	if err != nil || len(result.Providers) == 0 {
+		reason := "provider could not be fetched"
+		if err != nil {
+			reason = err.Error()
+		} else if len(result.FailedProviders) > 0 {
+			reason = result.FailedProviders[0].Error
+		}
+		failure := rpcFailure(request.ID, rpcResourceNotFound, "resource not found: %s", reason)
+		failure.Error.Data = map[string]interface{}{"uri": params.URI}
+		return nil, failure
+	}
+
+	text, err := json.Marshal(result.Providers[0])
+	if err != nil {
+		return nil, rpcFailure(request.ID, rpcInternalError, "failed to encode provider: %v", err)
+	}
+	return map[string]interface{}{
+		"contents": []map[string]interface{}{
+			{"uri": params.URI, "mimeType": "application/json", "text": string(text)},
+		},
+	}, nil
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/intelligence"
+)
+
+// Columns of the provider intelligence CSV export
+var providerCSVHeader = []string{
+	"address",
+	"health_score",
+	"active_leases",
+	"latency_ms",
+	"region",
+	"available_cpu",
+	"available_memory",
+	"available_gpu",
+	"error",
+}
+
+// REST: GET /api/v1/providers?addresses=akash1...,akash1...
+// Responds with JSON by default, or CSV when requested via ?format=csv or an Accept: text/csv header.
+// JSON responses can be narrowed to ?fields=address,health_score,...
+func (s *MCPServer) handleRESTProviders(w http.ResponseWriter, r *http.Request) {
+	var addresses []string
+	for _, value := range r.URL.Query()["addresses"] {
+		for _, addr := range strings.Split(value, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				addresses = append(addresses, addr)
+			}
+		}
+	}
+
+	if len(addresses) == 0 {
+		http.Error(w, "addresses query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	// Responses are always bounded; large batches are paged with ?offset= and ?limit=
+	var page intelligence.Page
+	for name, target := range map[string]*int{"offset": &page.Offset, "limit": &page.Limit} {
+		if value := r.URL.Query().Get(name); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 0 {
+				http.Error(w, fmt.Sprintf("invalid %s %q", name, value), http.StatusBadRequest)
+				return
+			}
+			*target = parsed
+		}
+	}
+
+	// Sparse fieldsets apply to JSON only; CSV has fixed columns
+	var fields []string
+	if spec := r.URL.Query().Get("fields"); spec != "" {
+		parsed, err := intelligence.ParseFields(spec)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fields = parsed
+	}
+
+	result, err := s.intelligenceService.GetProviderIntelligenceWithOptions(r.Context(), addresses, intelligence.FetchOptions{Page: &page})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to get provider intelligence: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// CSV has nowhere else to carry the truncation metadata
+	if result.Truncation != nil {
+		w.Header().Set("X-Total-Count", strconv.Itoa(result.Truncation.Total))
+		if result.Truncation.NextOffset != nil {
+			w.Header().Set("X-Next-Offset", strconv.Itoa(*result.Truncation.NextOffset))
+		}
+	}
+
+	if wantsCSV(r) {
+		// Buffered so a failure can still be reported before anything is sent
+		var body bytes.Buffer
+		if err := writeProvidersCSV(&body, result); err != nil {
+			http.Error(w, fmt.Sprintf("failed to write CSV: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="providers.csv"`)
+		w.Write(body.Bytes())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if fields != nil {
+		json.NewEncoder(w).Encode(result.WithFields(fields))
+		return
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+// REST: POST /api/v1/selection
+// The body holds select_optimal_provider's arguments as-is, without the tool
+// call envelope, and the selection is returned as plain JSON.
+func (s *MCPServer) handleRESTSelection(w http.ResponseWriter, r *http.Request) {
+	var args map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	s.writeRESTToolResult(w, r, "select_optimal_provider", args)
+}
+
+// REST: GET /api/v1/market?timeframe=7d
+func (s *MCPServer) handleRESTMarket(w http.ResponseWriter, r *http.Request) {
+	args := map[string]interface{}{}
+	if timeframe := r.URL.Query().Get("timeframe"); timeframe != "" {
+		args["timeframe"] = timeframe
+	}
+	s.writeRESTToolResult(w, r, "get_market_trends", args)
+}
+
+// Run a tool for a REST endpoint, under the same limits as /call, and write its
+// result without the MCP content wrapping
+func (s *MCPServer) writeRESTToolResult(w http.ResponseWriter, r *http.Request, tool string, args map[string]interface{}) {
+	if s.rejectAtToolLimit(w, tool) {
+		return
+	}
+	defer s.toolLimits.release(tool)
+
+	response, err := s.callTool(r.Context(), tool, args)
+	if errors.Is(err, errResultTooLarge) {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if errors.Is(err, errToolForbidden) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Check whether the client asked for CSV output
+func wantsCSV(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return strings.EqualFold(format, "csv")
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/csv")
+}
+
+// Write provider intelligence as CSV, one row per provider including those that failed
+func writeProvidersCSV(w io.Writer, result *intelligence.IntelligenceResult) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(providerCSVHeader); err != nil {
+		return err
+	}
+
+	for _, provider := range result.Providers {
+		row := []string{
+			provider.Address,
+			strconv.FormatFloat(provider.HealthScore, 'f', 3, 64),
+			"",
+			"",
+			provider.Attributes["region"],
+			"",
+			"",
+			"",
+			provider.Error,
+		}
+
+		if provider.StatusQueryTime > 0 {
+			row[3] = strconv.FormatInt(provider.StatusQueryTime.Milliseconds(), 10)
+		}
+
+		if provider.ClusterInfo != nil {
+			available := provider.ClusterInfo.AvailableResources
+			row[2] = strconv.Itoa(provider.ClusterInfo.ActiveLeases)
+			row[5] = strconv.FormatInt(available.CPU, 10)
+			row[6] = strconv.FormatInt(available.Memory, 10)
+			row[7] = strconv.Itoa(available.GPU)
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	for _, failed := range result.FailedProviders {
+		row := make([]string, len(providerCSVHeader))
+		row[0] = failed.Address
+		row[8] = fmt.Sprintf("%s: %s", failed.Category, failed.Error)
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var errResultTooLarge = errors.New("result too large")
+
+// Refuse a tool result whose encoding exceeds server.max_response_bytes, so a
+// client never receives a response that overflows its context window
+func (s *MCPServer) checkResultSize(response interface{}) error {
+	limit := s.config.Server.MaxResponseBytes
+	if limit <= 0 {
+		return nil
+	}
+
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to encode tool result: %w", err)
+	}
+	if len(encoded) > limit {
+		return fmt.Errorf("%w: %d bytes exceeds the %d byte maximum; request fewer providers with a smaller limit, or select fewer fields",
+			errResultTooLarge, len(encoded), limit)
+	}
+	return nil
+}
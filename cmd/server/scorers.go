@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+	"github.com/chainzero/akash-provider-intelligence/internal/intelligence"
+)
+
+// Register custom scoring plugins. A registered plugin only takes part in
+// selection once it is referenced by name in config.yaml:
+//
+//	scoring:
+//	  plugins:
+//	    - name: provider_transparency
+//	      weight: 0.1
+func registerScorers() error {
+	return intelligence.RegisterScorer("provider_transparency", intelligence.ScorerFunc(scoreProviderTransparency))
+}
+
+// Example plugin: reward providers that publish contact and organization details
+func scoreProviderTransparency(ctx context.Context, provider *akash.ProviderInfo) (float64, error) {
+	keys := []string{"organization", "email", "website"}
+
+	published := 0
+	for _, key := range keys {
+		if value, ok := provider.Attributes[key]; ok && value != "" {
+			published++
+		}
+	}
+
+	return float64(published) / float64(len(keys)), nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// Largest message accepted on stdin
+const maxStdioMessage = 16 << 20
+
+// Serve MCP over newline-delimited JSON-RPC on in/out until in closes. Messages
+// are handled concurrently so a slow tool call doesn't hold up a ping; writes
+// are serialized so responses never interleave.
+func (s *MCPServer) serveStdio(ctx context.Context, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), maxStdioMessage)
+
+	var writeMutex sync.Mutex
+	encoder := json.NewEncoder(out)
+	write := func(message interface{}) {
+		writeMutex.Lock()
+		defer writeMutex.Unlock()
+		if err := encoder.Encode(message); err != nil {
+			slog.Warn("Failed to write stdio message", "error", err)
+		}
+	}
+
+	var pending sync.WaitGroup
+	defer pending.Wait()
+
+	ctx = withCallScope(ctx, "stdio")
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		message := append([]byte(nil), line...)
+
+		pending.Add(1)
+		go func() {
+			defer pending.Done()
+			ctx := withNotifier(ctx, func(method string, params interface{}) {
+				write(rpcNotification{JSONRPC: "2.0", Method: method, Params: params})
+			})
+			if isRPCBatch(message) {
+				responses, failure := s.handleRPCBatch(ctx, message, nil)
+				if failure != nil {
+					write(failure)
+				} else if len(responses) > 0 {
+					write(responses)
+				}
+				return
+			}
+			if response := s.handleRPCMessage(ctx, message); response != nil {
+				write(response)
+			}
+		}()
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read stdin: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Comment line sent on an idle event stream so proxies keep it open
+const sseKeepAliveInterval = 15 * time.Second
+
+// Server-to-client JSON-RPC notification
+type rpcNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// Sends notifications to the client of the message being handled
+type rpcNotifier func(method string, params interface{})
+
+type notifierKey struct{}
+
+func withNotifier(ctx context.Context, notifier rpcNotifier) context.Context {
+	return context.WithValue(ctx, notifierKey{}, notifier)
+}
+
+// Notify the client, if the transport can carry messages before the response
+func notify(ctx context.Context, method string, params interface{}) {
+	if notifier, ok := ctx.Value(notifierKey{}).(rpcNotifier); ok {
+		notifier(method, params)
+	}
+}
+
+// Whether the client accepts a streamed (SSE) response
+func acceptsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// Answer a request over Server-Sent Events: notifications raised while it runs
+// are streamed as they happen, the stream is kept alive while idle, and the
+// JSON-RPC response is the final event.
+func (s *MCPServer) streamRPC(ctx context.Context, w http.ResponseWriter, r *http.Request, request *rpcRequest) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(s.handleRPC(ctx, request))
+		return
+	}
+
+	// A stream outlives the server's write timeout
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		slog.WarnContext(ctx, "Failed to clear write deadline for event stream", "error", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// Writes stop once the handler returns; late notifications are dropped
+	var mutex sync.Mutex
+	closed := false
+	defer func() {
+		mutex.Lock()
+		closed = true
+		mutex.Unlock()
+	}()
+	write := func(frame string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if closed {
+			return
+		}
+		fmt.Fprint(w, frame)
+		flusher.Flush()
+	}
+	send := func(message interface{}) {
+		data, err := json.Marshal(message)
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to encode event", "error", err)
+			return
+		}
+		write(fmt.Sprintf("event: message\ndata: %s\n\n", data))
+	}
+
+	ctx = withNotifier(ctx, func(method string, params interface{}) {
+		send(rpcNotification{JSONRPC: "2.0", Method: method, Params: params})
+	})
+
+	done := make(chan *rpcResponse, 1)
+	go func() {
+		done <- s.handleRPC(ctx, request)
+	}()
+
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case response := <-done:
+			if response != nil {
+				send(response)
+			}
+			return
+		case <-keepAlive.C:
+			write(": keepalive\n\n")
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// How often the certificate files are checked for changes
+const certCheckInterval = time.Minute
+
+// Serves the current certificate and swaps in renewed ones without a restart
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	cert    *tls.Certificate
+	modTime time.Time
+	mutex   sync.RWMutex
+}
+
+// Load the key pair, failing fast when it can't be used
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	reloader := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := reloader.reload(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+// Load the key pair from disk; the current certificate stays in use on failure
+func (c *certReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS key pair: %w", err)
+	}
+	modTime, err := c.latestModTime()
+	if err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	c.cert = &cert
+	c.modTime = modTime
+	c.mutex.Unlock()
+	return nil
+}
+
+func (c *certReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, path := range []string{c.certFile, c.keyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+func (c *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.cert, nil
+}
+
+func (c *certReloader) tlsConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: c.getCertificate,
+	}
+}
+
+// Reload on SIGHUP, and when the files change on disk (e.g. renewed by
+// certbot or cert-manager)
+func (c *certReloader) watch() {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	ticker := time.NewTicker(certCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-hup:
+		case <-ticker.C:
+			modTime, err := c.latestModTime()
+			c.mutex.RLock()
+			unchanged := err != nil || !modTime.After(c.modTime)
+			c.mutex.RUnlock()
+			if unchanged {
+				continue
+			}
+		}
+
+		if err := c.reload(); err != nil {
+			slog.Warn("TLS certificate reload failed, keeping the current one", "cert_file", c.certFile, "error", err)
+			continue
+		}
+		slog.Info("TLS certificate reloaded", "cert_file", c.certFile)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// In-flight calls per tool, bounded for tools with a configured limit so an
+// expensive tool can't take every worker from the cheap ones
+type toolLimiter struct {
+	limits   map[string]int
+	inFlight map[string]int
+	mutex    sync.Mutex
+}
+
+func newToolLimiter(limits map[string]int) *toolLimiter {
+	return &toolLimiter{limits: limits, inFlight: make(map[string]int)}
+}
+
+// Claim a slot for a call to tool; false when the tool is at its limit
+func (l *toolLimiter) acquire(tool string) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if limit, ok := l.limits[tool]; ok && limit > 0 && l.inFlight[tool] >= limit {
+		return false
+	}
+	l.inFlight[tool]++
+	return true
+}
+
+func (l *toolLimiter) release(tool string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.inFlight[tool]--; l.inFlight[tool] <= 0 {
+		delete(l.inFlight, tool)
+	}
+}
+
+// Current in-flight calls and configured limits, for /status
+func (l *toolLimiter) stats() map[string]interface{} {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	inFlight := make(map[string]int, len(l.inFlight))
+	for tool, count := range l.inFlight {
+		inFlight[tool] = count
+	}
+	return map[string]interface{}{
+		"in_flight": inFlight,
+		"limits":    l.limits,
+	}
+}
+
+// Reject a call with 503 when its tool is at its limit, leaving other tools available
+func (s *MCPServer) rejectAtToolLimit(w http.ResponseWriter, tool string) bool {
+	if s.toolLimits.acquire(tool) {
+		return false
+	}
+	w.Header().Set("Retry-After", "1")
+	http.Error(w, fmt.Sprintf("Tool %s is at its concurrency limit (%d), retry later", tool, s.toolLimits.limits[tool]),
+		http.StatusServiceUnavailable)
+	return true
+}
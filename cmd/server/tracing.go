@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/telemetry"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var tracer = otel.Tracer("github.com/chainzero/akash-provider-intelligence/cmd/server")
+
+// Middleware: start a server span per request, continuing the caller's trace when
+// it sends a W3C traceparent header. The request ID is recorded on the span and
+// the trace ID is returned so logs and traces can be joined. Records logged
+// while handling the request carry its method and route.
+func tracingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Path
+		if route := mux.CurrentRoute(r); route != nil {
+			if template, err := route.GetPathTemplate(); err == nil {
+				name = template
+			}
+		}
+
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, r.Method+" "+name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.method", r.Method),
+				attribute.String("http.route", name),
+			))
+		defer span.End()
+
+		ctx = telemetry.WithLogAttrs(ctx, slog.String("method", r.Method), slog.String("route", name))
+		if requestID := telemetry.RequestID(ctx); requestID != "" {
+			span.SetAttributes(attribute.String("request.id", requestID))
+		}
+		if span.SpanContext().HasTraceID() {
+			w.Header().Set("X-Trace-Id", span.SpanContext().TraceID().String())
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// gRPC interceptor: start a server span per call, continuing the caller's trace
+// from its request metadata
+func grpcTracingInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	ctx, span := tracer.Start(ctx, info.FullMethod,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.method", info.FullMethod),
+			attribute.String("request.id", telemetry.RequestID(ctx)),
+		))
+	defer span.End()
+
+	ctx = telemetry.WithLogAttrs(ctx, slog.String("method", "grpc"), slog.String("route", info.FullMethod))
+	response, err := handler(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, status.Code(err).String())
+	}
+	return response, err
+}
+
+// Reads and writes trace context in gRPC metadata
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/intelligence"
+	"golang.org/x/net/websocket"
+)
+
+// Most providers one /ws connection can watch
+const maxWatchedProviders = 500
+
+// Message from a /ws client
+type wsRequest struct {
+	Action    string   `json:"action"` // subscribe or unsubscribe
+	Addresses []string `json:"addresses"`
+}
+
+// Message to a /ws client
+type wsMessage struct {
+	Type      string                       `json:"type"` // subscribed, update or error
+	Addresses []string                     `json:"addresses,omitempty"`
+	Update    *intelligence.ProviderUpdate `json:"update,omitempty"`
+	Error     string                       `json:"error,omitempty"`
+}
+
+// GET /ws - push provider updates to clients watching a set of addresses
+func (s *MCPServer) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	// A subscription outlives the server's read and write timeouts
+	controller := http.NewResponseController(w)
+	if err := controller.SetReadDeadline(time.Time{}); err != nil {
+		slog.WarnContext(r.Context(), "Failed to clear read deadline for WebSocket", "error", err)
+	}
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil {
+		slog.WarnContext(r.Context(), "Failed to clear write deadline for WebSocket", "error", err)
+	}
+
+	// Non-browser clients send no Origin, so it is not checked (CORS is open too)
+	server := websocket.Server{Handler: s.serveSubscription}
+	server.ServeHTTP(w, r)
+}
+
+// Run one subscription until the client disconnects
+func (s *MCPServer) serveSubscription(conn *websocket.Conn) {
+	sub := s.intelligenceService.Subscribe()
+	defer sub.Close()
+
+	// Updates are sent as they arrive, alongside replies to the client's requests
+	go func() {
+		for update := range sub.Updates() {
+			if err := websocket.JSON.Send(conn, wsMessage{Type: "update", Update: &update}); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		var request wsRequest
+		if err := websocket.JSON.Receive(conn, &request); err != nil {
+			return
+		}
+
+		reply := wsMessage{Type: "subscribed"}
+		switch request.Action {
+		case "subscribe":
+			if len(sub.Addresses())+len(request.Addresses) > maxWatchedProviders {
+				reply = wsMessage{Type: "error", Error: fmt.Sprintf("at most %d providers can be watched per connection", maxWatchedProviders)}
+				break
+			}
+			sub.Watch(request.Addresses)
+		case "unsubscribe":
+			sub.Unwatch(request.Addresses)
+		default:
+			reply = wsMessage{Type: "error", Error: fmt.Sprintf("unknown action %q (valid: subscribe, unsubscribe)", request.Action)}
+		}
+		if reply.Type == "subscribed" {
+			reply.Addresses = sub.Addresses()
+		}
+
+		if err := websocket.JSON.Send(conn, reply); err != nil {
+			return
+		}
+	}
+}
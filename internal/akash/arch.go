@@ -0,0 +1,65 @@
+package akash
+
+import (
+	"sort"
+	"strings"
+)
+
+// Attributes a provider can use to advertise CPU architecture, either as
+// capabilities/cpu/arch/<arch>=true or capabilities/cpu/arch=<arch>[,<arch>]
+const ArchAttributePrefix = "capabilities/cpu/arch"
+
+// Canonical names of the common architecture aliases
+var cpuArchAliases = map[string]string{
+	"amd64":   "amd64",
+	"x86_64":  "amd64",
+	"x86-64":  "amd64",
+	"x64":     "amd64",
+	"x86":     "amd64",
+	"arm64":   "arm64",
+	"aarch64": "arm64",
+	"armv8":   "arm64",
+}
+
+// Normalize a CPU architecture name (e.g. x86_64 -> amd64, aarch64 -> arm64).
+// Unknown architectures are returned lowercased.
+func NormalizeCPUArch(arch string) string {
+	arch = strings.ToLower(strings.TrimSpace(arch))
+	if canonical, ok := cpuArchAliases[arch]; ok {
+		return canonical
+	}
+	return arch
+}
+
+// Parse the advertised CPU architectures, sorted. Returns nil if none are advertised.
+func ParseCPUArchitectures(attributes map[string]string) []string {
+	found := make(map[string]bool)
+	for key, value := range attributes {
+		if key == ArchAttributePrefix {
+			for _, arch := range strings.Split(value, ",") {
+				if arch = NormalizeCPUArch(arch); arch != "" {
+					found[arch] = true
+				}
+			}
+			continue
+		}
+
+		arch, ok := strings.CutPrefix(key, ArchAttributePrefix+"/")
+		if !ok || strings.EqualFold(strings.TrimSpace(value), "false") {
+			continue
+		}
+		if arch = NormalizeCPUArch(arch); arch != "" {
+			found[arch] = true
+		}
+	}
+
+	if len(found) == 0 {
+		return nil
+	}
+	archs := make([]string, 0, len(found))
+	for arch := range found {
+		archs = append(archs, arch)
+	}
+	sort.Strings(archs)
+	return archs
+}
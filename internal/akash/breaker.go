@@ -0,0 +1,129 @@
+package akash
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Returned instead of querying the status endpoint of a host whose circuit is open
+var ErrCircuitOpen = errors.New("status endpoint circuit open")
+
+// When to stop querying a host whose status endpoint keeps failing. A zero
+// FailureThreshold disables the breaker.
+type CircuitBreakerConfig struct {
+	FailureThreshold int           // consecutive status failures that open the circuit
+	Cooldown         time.Duration // how long the circuit stays open before one probe query
+}
+
+// A host whose status queries are being skipped
+type OpenCircuit struct {
+	HostURI   string    `json:"host_uri"`
+	Failures  int       `json:"failures"`
+	LastError string    `json:"last_error"`
+	NextProbe time.Time `json:"next_probe"`
+}
+
+type circuitState struct {
+	failures  int
+	lastError string
+	openUntil time.Time // zero while closed
+	probing   bool      // a probe query is running
+}
+
+// Tracks consecutive status failures per host. A host reaching the threshold
+// is skipped for the cooldown, then one query is let through as a probe:
+// success closes the circuit, failure opens it for another cooldown.
+type circuitBreaker struct {
+	config CircuitBreakerConfig
+	hosts  map[string]*circuitState
+	mutex  sync.Mutex
+}
+
+func newCircuitBreaker(config CircuitBreakerConfig) *circuitBreaker {
+	return &circuitBreaker{config: config, hosts: make(map[string]*circuitState)}
+}
+
+// Whether a status query to host may run. When the circuit is open the
+// returned error says why not.
+func (b *circuitBreaker) allow(host string) error {
+	if b.config.FailureThreshold <= 0 {
+		return nil
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	state, ok := b.hosts[host]
+	if !ok || state.openUntil.IsZero() {
+		return nil
+	}
+	if state.probing || time.Now().Before(state.openUntil) {
+		return fmt.Errorf("%w after %d consecutive failures (last: %s), next probe at %s",
+			ErrCircuitOpen, state.failures, state.lastError, state.openUntil.Format(time.RFC3339))
+	}
+	state.probing = true
+	return nil
+}
+
+// Record the outcome of a status query allowed by allow
+func (b *circuitBreaker) record(host string, err error) {
+	if b.config.FailureThreshold <= 0 {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if err == nil {
+		delete(b.hosts, host)
+		return
+	}
+	state, ok := b.hosts[host]
+	if !ok {
+		state = &circuitState{}
+		b.hosts[host] = state
+	}
+	state.failures++
+	state.lastError = err.Error()
+	state.probing = false
+	if state.failures >= b.config.FailureThreshold {
+		state.openUntil = time.Now().Add(b.config.Cooldown)
+	}
+}
+
+// Forget a query allowed by allow that ended without an outcome, e.g. because
+// the caller gave up, so the next one can probe
+func (b *circuitBreaker) abandon(host string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if state, ok := b.hosts[host]; ok {
+		state.probing = false
+	}
+}
+
+// Hosts currently skipped, by host URI
+func (b *circuitBreaker) open() []OpenCircuit {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	circuits := make([]OpenCircuit, 0)
+	for host, state := range b.hosts {
+		if state.openUntil.IsZero() {
+			continue
+		}
+		circuits = append(circuits, OpenCircuit{
+			HostURI:   host,
+			Failures:  state.failures,
+			LastError: state.lastError,
+			NextProbe: state.openUntil,
+		})
+	}
+	sort.Slice(circuits, func(i, j int) bool { return circuits[i].HostURI < circuits[j].HostURI })
+	return circuits
+}
+
+// Hosts whose status queries are currently skipped by the circuit breaker
+func (c *Client) OpenCircuits() []OpenCircuit {
+	return c.breaker.open()
+}
@@ -4,21 +4,114 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	providertypes "github.com/akash-network/akash-api/go/node/provider/v1beta3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/semaphore"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	grpccodes "google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+var tracer = otel.Tracer("github.com/chainzero/akash-provider-intelligence/internal/akash")
+
+type Config struct {
+	// Chain queries are balanced over the healthy endpoints
+	GRPCEndpoints []string
+
+	// CometBFT RPC endpoint queried while no gRPC endpoint is reachable; empty disables the fallback
+	RPCEndpoint string
+
+	// TLS and credentials for the gRPC endpoint; the zero value is plaintext
+	GRPCSecurity GRPCSecurity
+
+	// Retries of status endpoint and chain queries
+	StatusRetry RetryPolicy
+	ChainRetry  RetryPolicy
+
+	// Skipping status endpoints that keep failing
+	CircuitBreaker CircuitBreakerConfig
+
+	// Provider queries running at once, and how long each part may take:
+	// the status query with its retries, the blockchain query with its
+	// retries, all of one provider, and a whole GetMultipleProviderInfo
+	// batch. Zero values use the defaults (10, 3s, 5s, 8s and 15s).
+	MaxConcurrent   int
+	StatusTimeout   time.Duration
+	ChainTimeout    time.Duration
+	ProviderTimeout time.Duration
+	BatchTimeout    time.Duration
+
+	// Additional provider endpoints (e.g. /version) fetched alongside /status
+	ExtraEndpoints []string
+
+	// Sanity bounds on reported per-node inventory; zero fields use the defaults
+	ResourceLimits ResourceLimits
+
+	// Active lease contribution to the health score; zero value uses the defaults
+	LeaseScoring LeaseScoring
+
+	// Provider list pages fetched concurrently; 1 walks pages sequentially
+	ListConcurrency int
+
+	// Status response field mappings, tried before the built-in schemas
+	StatusSchemas []StatusSchema
+}
+
+// Limits used where the Config leaves them unset
+const (
+	defaultMaxConcurrent   = 10
+	defaultStatusTimeout   = 3 * time.Second
+	defaultChainTimeout    = 5 * time.Second
+	defaultProviderTimeout = 8 * time.Second
+	defaultBatchTimeout    = 15 * time.Second
 )
 
 type Client struct {
-	grpcEndpoint string
-	httpClient   *http.Client
-	semaphore    *semaphore.Weighted
+	config        *Config
+	grpcEndpoints []string
+	httpClient    *http.Client
+	semaphore     *semaphore.Weighted
+	maxConcurrent int64
+
+	// Config timeouts with defaults filled in
+	statusTimeout   time.Duration
+	chainTimeout    time.Duration
+	providerTimeout time.Duration
+	batchTimeout    time.Duration
+
+	// Provider queries running and waiting for a semaphore slot
+	inFlight int64
+	waiting  int64
+
+	// Hosts already reported for serving an unrecognized status schema
+	unknownSchemaHosts sync.Map
+
+	// Connection shared by all chain queries, see conn
+	dialOptions []grpc.DialOption
+	rpc         *rpcConn // nil without a fallback
+	grpcConn    *grpc.ClientConn
+	connMutex   sync.Mutex
+
+	breaker *circuitBreaker
+
+	// Host URI each provider was last seen registered with, so its status
+	// query can start before the blockchain query confirms it
+	hostURIs sync.Map
 }
 
 type ProviderInfo struct {
@@ -30,9 +123,60 @@ type ProviderInfo struct {
 	ClusterInfo         *ClusterStatus    `json:"cluster_info,omitempty"`
 	ResponseTime        time.Duration     `json:"response_time"`
 	HealthScore         float64           `json:"health_score"`
+	SmoothedHealthScore *float64          `json:"smoothed_health_score,omitempty"`
 	Error               string            `json:"error,omitempty"`
+	Diagnostics         *ErrorDiagnostics `json:"diagnostics,omitempty"`
 	BlockchainQueryTime time.Duration     `json:"blockchain_query_time"`
 	StatusQueryTime     time.Duration     `json:"status_query_time"`
+	StatusAttempts      int               `json:"status_attempts,omitempty"`
+	Tiers               []string          `json:"tiers,omitempty"`
+
+	// Status endpoint skipped because its recent queries all failed
+	CircuitOpen bool `json:"circuit_open,omitempty"`
+
+	// Reachable but running no leases yet, so reliability is unproven
+	Unproven bool `json:"unproven,omitempty"`
+
+	// Results of the configured additional endpoints, keyed by path
+	Endpoints map[string]*EndpointResult `json:"endpoints,omitempty"`
+
+	// Pricing advertised through provider attributes, if any
+	Pricing *PricingPolicy `json:"pricing,omitempty"`
+
+	// Planned maintenance advertised through provider attributes
+	Maintenance []MaintenanceWindow `json:"maintenance,omitempty"`
+
+	// Advertised CPU architectures (e.g. amd64, arm64), if any
+	CPUArchitectures []string `json:"cpu_architectures,omitempty"`
+
+	// IP addresses the host URI resolved to at fetch time
+	ResolvedIPs []string `json:"resolved_ips,omitempty"`
+
+	// Physical cluster behind the address, for deduplication across addresses
+	ClusterIdentity *ClusterIdentity `json:"cluster_identity,omitempty"`
+}
+
+// Failure categories for providers that could not be fetched at all
+const (
+	FailureConcurrencyLimit = "concurrency_limit"
+	FailureTimeout          = "timeout"
+	FailureBlockchainQuery  = "blockchain_query_failed"
+	FailureNotFound         = "not_found"
+)
+
+// ErrProviderNotFound is returned when the chain has no provider record for an
+// address, either explicitly or as an empty record without an error
+var ErrProviderNotFound = errors.New("provider not found on chain")
+
+// FailedProvider describes a provider for which no intelligence could be gathered
+type FailedProvider struct {
+	Address  string    `json:"address"`
+	Error    string    `json:"error"`
+	Category string    `json:"category"`
+	FailedAt time.Time `json:"failed_at"`
+
+	// Underlying gRPC/HTTP codes, when the error carried any
+	Diagnostics *ErrorDiagnostics `json:"diagnostics,omitempty"`
 }
 
 type ClusterStatus struct {
@@ -42,6 +186,12 @@ type ClusterStatus struct {
 	AvailableNodes     int                    `json:"available_nodes"`
 	TotalResources     ResourceSummary        `json:"total_resources"`
 	AvailableResources ResourceSummary        `json:"available_resources"`
+
+	// Inventory values that were implausible and had to be clamped
+	SuspiciousInventory []string `json:"suspicious_inventory,omitempty"`
+
+	// Status schema the response was parsed with, "unrecognized" if none matched
+	Schema string `json:"schema,omitempty"`
 }
 
 type ResourceSummary struct {
@@ -51,61 +201,124 @@ type ResourceSummary struct {
 	GPU     int   `json:"gpu"`
 }
 
-func NewClient(grpcEndpoint string) *Client {
-	return &Client{
-		grpcEndpoint: grpcEndpoint,
+// Create a client, failing when the gRPC TLS settings can't be used
+func NewClient(config *Config) (*Client, error) {
+	dialOptions, err := grpcDialOptions(config.GRPCSecurity)
+	if err != nil {
+		return nil, err
+	}
+
+	maxConcurrent := config.MaxConcurrent
+	if maxConcurrent <= 0 {
+		maxConcurrent = defaultMaxConcurrent
+	}
+	statusTimeout := orDefault(config.StatusTimeout, defaultStatusTimeout)
+
+	client := &Client{
+		config:          config,
+		grpcEndpoints:   config.GRPCEndpoints,
+		dialOptions:     dialOptions,
+		breaker:         newCircuitBreaker(config.CircuitBreaker),
+		maxConcurrent:   int64(maxConcurrent),
+		statusTimeout:   statusTimeout,
+		chainTimeout:    orDefault(config.ChainTimeout, defaultChainTimeout),
+		providerTimeout: orDefault(config.ProviderTimeout, defaultProviderTimeout),
+		batchTimeout:    orDefault(config.BatchTimeout, defaultBatchTimeout),
 		httpClient: &http.Client{
-			Timeout: 5 * time.Second,
+			Timeout: statusTimeout,
 			Transport: &http.Transport{
 				TLSClientConfig: &tls.Config{
 					InsecureSkipVerify: true, // Skip SSL verification for provider status
 				},
 			},
 		},
-		semaphore: semaphore.NewWeighted(10),
+		semaphore: semaphore.NewWeighted(int64(maxConcurrent)),
 	}
+	if config.RPCEndpoint != "" {
+		client.rpc = newRPCConn(config.RPCEndpoint)
+	}
+	return client, nil
+}
+
+// A configured duration, or the default when unset
+func orDefault(configured, fallback time.Duration) time.Duration {
+	if configured <= 0 {
+		return fallback
+	}
+	return configured
 }
 
 // Get multiple providers intelligence concurrently - THIS IS THE KEY PERFORMANCE FEATURE
-func (c *Client) GetMultipleProviderInfo(ctx context.Context, addresses []string) ([]*ProviderInfo, error) {
+// Providers that could not be fetched are returned separately instead of as error stubs.
+// Queries are dispatched in the order given, so when the deadline cuts the batch
+// short it is the tail of the list that goes unfetched.
+func (c *Client) GetMultipleProviderInfo(ctx context.Context, addresses []string) ([]*ProviderInfo, []*FailedProvider, error) {
 	if len(addresses) == 0 {
-		return []*ProviderInfo{}, nil
+		return []*ProviderInfo{}, []*FailedProvider{}, nil
 	}
 
+	// Per-provider spans started in the goroutines below nest under this one
+	ctx, span := tracer.Start(ctx, "akash.GetMultipleProviderInfo",
+		trace.WithAttributes(attribute.Int("provider.count", len(addresses))))
+	defer span.End()
+
 	// Create context with timeout for the entire operation
-	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, c.batchTimeout)
 	defer cancel()
 
 	results := make([]*ProviderInfo, len(addresses))
+	failures := make([]*FailedProvider, len(addresses))
 	var wg sync.WaitGroup
 
-	// Launch concurrent queries
+	progress := progressFrom(ctx)
+	if progress != nil {
+		progress.Queued(len(addresses))
+	}
+
+	// Launch concurrent queries, acquiring slots in input order
+	atomic.AddInt64(&c.waiting, int64(len(addresses)))
 	for i, addr := range addresses {
+		// Acquire semaphore to limit concurrency
+		err := c.semaphore.Acquire(ctx, 1)
+		atomic.AddInt64(&c.waiting, -1)
+		if err != nil {
+			failures[i] = &FailedProvider{
+				Address:  addr,
+				Error:    "concurrency limit exceeded",
+				Category: FailureConcurrencyLimit,
+				FailedAt: time.Now(),
+			}
+			if progress != nil {
+				progress.Completed(addr, err)
+			}
+			continue
+		}
+
 		wg.Add(1)
 		go func(index int, address string) {
 			defer wg.Done()
 
-			// Acquire semaphore to limit concurrency
-			if err := c.semaphore.Acquire(ctx, 1); err != nil {
-				results[index] = &ProviderInfo{
-					Address:     address,
-					LastSeen:    time.Now(),
-					Error:       "concurrency limit exceeded",
-					HealthScore: 0.0,
-				}
-				return
-			}
-			defer c.semaphore.Release(1)
+			atomic.AddInt64(&c.inFlight, 1)
+			defer func() {
+				atomic.AddInt64(&c.inFlight, -1)
+				c.semaphore.Release(1)
+			}()
 
 			// Query provider with timeout
 			info, err := c.GetProviderInfo(ctx, address)
+			if progress != nil {
+				progress.Completed(address, err)
+			}
 			if err != nil {
-				info = &ProviderInfo{
+				slog.DebugContext(ctx, "Provider query failed", "provider", address, "category", categorizeFailure(err), "error", err)
+				failures[index] = &FailedProvider{
 					Address:     address,
-					LastSeen:    time.Now(),
 					Error:       err.Error(),
-					HealthScore: 0.0,
+					Category:    categorizeFailure(err),
+					FailedAt:    time.Now(),
+					Diagnostics: Diagnose(err),
 				}
+				return
 			}
 			results[index] = info
 		}(i, addr)
@@ -114,85 +327,314 @@ func (c *Client) GetMultipleProviderInfo(ctx context.Context, addresses []string
 	// Wait for all goroutines to complete
 	wg.Wait()
 
-	return results, nil
+	// Compact results, preserving input order
+	providers := make([]*ProviderInfo, 0, len(addresses))
+	failed := make([]*FailedProvider, 0)
+	for i := range addresses {
+		if results[i] != nil {
+			providers = append(providers, results[i])
+		} else if failures[i] != nil {
+			failed = append(failed, failures[i])
+		}
+	}
+	span.SetAttributes(attribute.Int("provider.failed", len(failed)))
+
+	return providers, failed, nil
+}
+
+// Upstream saturation: queries running or queued relative to the concurrency limit.
+// 1 means every slot is busy; above 1 means queries are waiting.
+func (c *Client) Saturation() float64 {
+	busy := atomic.LoadInt64(&c.inFlight) + atomic.LoadInt64(&c.waiting)
+	return float64(busy) / float64(c.maxConcurrent)
+}
+
+// Map a provider query error to a failure category
+func categorizeFailure(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return FailureTimeout
+	}
+	if errors.Is(err, ErrProviderNotFound) {
+		return FailureNotFound
+	}
+	return FailureBlockchainQuery
 }
 
-// Get provider information from blockchain and status endpoint
+// Health score of a provider whose status endpoint could not be queried
+func (c *Client) PartialHealthScore(info *ProviderInfo) float64 {
+	return c.calculatePartialHealthScore(info)
+}
+
+// Get provider information from blockchain and status endpoint. When the
+// provider's host URI is already known the status query starts alongside the
+// blockchain query; if the chain reports a different host it is redone there.
 func (c *Client) GetProviderInfo(ctx context.Context, providerAddr string) (*ProviderInfo, error) {
+	ctx, span := tracer.Start(ctx, "akash.GetProviderInfo",
+		trace.WithAttributes(attribute.String("provider.address", providerAddr)))
+	defer span.End()
+
 	info := &ProviderInfo{
 		Address:  providerAddr,
 		LastSeen: time.Now(),
 	}
 
 	// Shorter timeout for individual queries
-	ctx, cancel := context.WithTimeout(ctx, 8*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, c.providerTimeout)
 	defer cancel()
 
+	// Start on the host the provider was last registered with
+	knownHost := c.knownHostURI(providerAddr)
+	var early chan *statusResult
+	earlyCtx, cancelEarly := context.WithCancel(ctx)
+	defer cancelEarly()
+	if knownHost != "" {
+		early = make(chan *statusResult, 1)
+		go func() {
+			early <- c.queryStatus(earlyCtx, knownHost)
+		}()
+	}
+
 	// Step 1: Query blockchain for provider info
 	blockchainStart := time.Now()
-	provider, err := c.queryBlockchainProvider(ctx, providerAddr)
+	chainCtx, chainCancel := context.WithTimeout(ctx, c.chainTimeout)
+	provider, err := c.queryBlockchainProvider(chainCtx, providerAddr)
+	chainCancel()
 	info.BlockchainQueryTime = time.Since(blockchainStart)
 
+	span.SetAttributes(attribute.Int64("blockchain.query_ms", info.BlockchainQueryTime.Milliseconds()))
+
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "blockchain query failed")
 		return info, fmt.Errorf("blockchain query failed: %w", err)
 	}
 
 	// Parse blockchain data
 	info.HostURI = provider.HostURI
+	span.SetAttributes(attribute.String("provider.host_uri", provider.HostURI))
 	info.Attributes = make(map[string]string)
 	for _, attr := range provider.Attributes {
 		info.Attributes[attr.Key] = attr.Value
 	}
+	info.Pricing = ParsePricingPolicy(info.Attributes)
+	info.Maintenance = ParseMaintenanceWindows(info.Attributes)
+	info.CPUArchitectures = ParseCPUArchitectures(info.Attributes)
 
-	// Step 2: Query provider status endpoint if available
-	if provider.HostURI != "" {
-		info.StatusEndpoint = provider.HostURI
-
-		// Create shorter timeout context for status query
-		statusCtx, statusCancel := context.WithTimeout(ctx, 3*time.Second)
-		defer statusCancel()
-
-		statusStart := time.Now()
-		clusterInfo, err := c.queryProviderStatus(statusCtx, provider.HostURI)
-		info.StatusQueryTime = time.Since(statusStart)
-		info.ResponseTime = info.StatusQueryTime // For backward compatibility
+	// Step 2: Query provider status endpoint if available, unless it keeps failing
+	if provider.HostURI == "" {
+		info.HealthScore = c.calculatePartialHealthScore(info)
+	} else {
+		var status *statusResult
+		if early != nil && knownHost == provider.HostURI {
+			status = <-early
+			span.SetAttributes(attribute.Bool("status.parallel", true))
+		} else {
+			cancelEarly()
+			status = c.queryStatus(ctx, provider.HostURI)
+		}
+		status.apply(info)
 
-		if err != nil {
-			info.Error = err.Error()
+		if status.circuitOpen {
+			span.SetAttributes(attribute.Bool("status.circuit_open", true))
+			info.HealthScore = c.calculatePartialHealthScore(info)
+		} else if status.err != nil {
+			slog.DebugContext(ctx, "Provider status query failed", "provider", providerAddr, "attempts", status.attempts, "error", status.err)
 			info.HealthScore = c.calculatePartialHealthScore(info)
 		} else {
-			info.ClusterInfo = clusterInfo
+			info.Unproven = info.ClusterInfo.ActiveLeases == 0
 			info.HealthScore = c.calculateHealthScore(info)
 		}
-	} else {
-		info.HealthScore = c.calculatePartialHealthScore(info)
 	}
 
+	span.SetAttributes(
+		attribute.Int64("status.query_ms", info.StatusQueryTime.Milliseconds()),
+		attribute.Int("status.attempts", info.StatusAttempts),
+		attribute.Float64("provider.health_score", info.HealthScore),
+	)
+
 	return info, nil
 }
 
+// Everything learned from a provider's status endpoint, kept apart from the
+// ProviderInfo until the blockchain query confirms the host
+type statusResult struct {
+	hostURI     string
+	clusterInfo *ClusterStatus
+	attempts    int
+	queryTime   time.Duration
+	resolvedIPs []string
+	endpoints   map[string]*EndpointResult
+	circuitOpen bool
+	err         error
+}
+
+// Query a host's status endpoint, extra endpoints and addresses, unless its
+// circuit is open
+func (c *Client) queryStatus(ctx context.Context, hostURI string) *statusResult {
+	result := &statusResult{hostURI: hostURI}
+	if err := c.breaker.allow(hostURI); err != nil {
+		result.circuitOpen = true
+		result.err = err
+		return result
+	}
+
+	// Create shorter timeout context for status query
+	statusCtx, statusCancel := context.WithTimeout(ctx, c.statusTimeout)
+	defer statusCancel()
+
+	// Query additional endpoints and resolve the host in parallel with /status
+	var endpointsWG sync.WaitGroup
+	endpointsWG.Add(1)
+	go func() {
+		defer endpointsWG.Done()
+		result.resolvedIPs = resolveHost(statusCtx, hostURI)
+	}()
+	if len(c.config.ExtraEndpoints) > 0 {
+		endpointsWG.Add(1)
+		go func() {
+			defer endpointsWG.Done()
+			result.endpoints = c.queryProviderEndpoints(statusCtx, hostURI)
+		}()
+	}
+
+	statusStart := time.Now()
+	result.clusterInfo, result.attempts, result.err = c.queryProviderStatusWithRetry(statusCtx, hostURI)
+	result.queryTime = time.Since(statusStart)
+
+	endpointsWG.Wait()
+
+	// A query cut short by the caller says nothing about the host
+	if result.err != nil && ctx.Err() != nil {
+		c.breaker.abandon(hostURI)
+	} else {
+		c.breaker.record(hostURI, result.err)
+	}
+	return result
+}
+
+// Copy a status result into the provider's info
+func (r *statusResult) apply(info *ProviderInfo) {
+	info.StatusEndpoint = r.hostURI
+	info.StatusAttempts = r.attempts
+	info.StatusQueryTime = r.queryTime
+	info.ResponseTime = r.queryTime // For backward compatibility
+	info.ResolvedIPs = r.resolvedIPs
+	info.Endpoints = r.endpoints
+	info.CircuitOpen = r.circuitOpen
+	if r.err != nil {
+		info.Error = r.err.Error()
+		if !r.circuitOpen {
+			info.Diagnostics = Diagnose(r.err)
+		}
+		return
+	}
+	info.ClusterInfo = r.clusterInfo
+}
+
 // Query provider from Akash blockchain
 func (c *Client) queryBlockchainProvider(ctx context.Context, providerAddr string) (*providertypes.Provider, error) {
-	conn, err := grpc.DialContext(ctx, c.grpcEndpoint,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
-	)
+	ctx, span := tracer.Start(ctx, "akash.queryBlockchainProvider",
+		trace.WithAttributes(attribute.String("grpc.endpoints", c.endpointList())))
+	defer span.End()
+
+	conn, err := c.queryConn()
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to gRPC %s: %w", c.grpcEndpoint, err)
+		return nil, err
 	}
-	defer conn.Close()
 
 	client := providertypes.NewQueryClient(conn)
 	resp, err := client.Provider(ctx, &providertypes.QueryProviderRequest{
 		Owner: providerAddr,
 	})
+	if grpcstatus.Code(err) == grpccodes.NotFound {
+		c.hostURIs.Delete(providerAddr)
+		return nil, withGRPCStatus(fmt.Errorf("%w: %s", ErrProviderNotFound, providerAddr), err)
+	}
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "provider query failed")
 		return nil, fmt.Errorf("failed to query provider %s: %w", providerAddr, err)
 	}
 
+	// Some endpoints answer unknown providers with an empty record instead of an error
+	if resp == nil || isEmptyProviderRecord(&resp.Provider) {
+		c.hostURIs.Delete(providerAddr)
+		return nil, fmt.Errorf("%w: %s (empty provider record)", ErrProviderNotFound, providerAddr)
+	}
+	c.rememberHostURI(providerAddr, resp.Provider.HostURI)
+
 	return &resp.Provider, nil
 }
 
+// Record the host URI a provider is registered with
+func (c *Client) rememberHostURI(providerAddr, hostURI string) {
+	if hostURI == "" {
+		c.hostURIs.Delete(providerAddr)
+		return
+	}
+	c.hostURIs.Store(providerAddr, hostURI)
+}
+
+// Get the host URI a provider was last seen registered with, or ""
+func (c *Client) knownHostURI(providerAddr string) string {
+	hostURI, _ := c.hostURIs.Load(providerAddr)
+	uri, _ := hostURI.(string)
+	return uri
+}
+
+// Check whether a provider record carries no registration data at all
+func isEmptyProviderRecord(provider *providertypes.Provider) bool {
+	return provider.Owner == "" && provider.HostURI == "" && len(provider.Attributes) == 0
+}
+
+// Marks a status query failure as transient and worth retrying
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+// Timeouts, connection resets and dropped connections are transient
+func isTransientNetworkError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// Query provider status endpoint, retrying transient failures under the
+// status retry policy within the status query deadline carried by ctx
+func (c *Client) queryProviderStatusWithRetry(ctx context.Context, hostURI string) (clusterInfo *ClusterStatus, attempts int, err error) {
+	ctx, span := tracer.Start(ctx, "akash.queryProviderStatus",
+		trace.WithAttributes(attribute.String("provider.host_uri", hostURI)))
+	defer func() {
+		span.SetAttributes(attribute.Int("status.attempts", attempts))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "status query failed")
+		}
+		span.End()
+	}()
+
+	attempts, err = c.config.StatusRetry.do(ctx, "provider status query", isRetryableStatusError, func(ctx context.Context) error {
+		var queryErr error
+		clusterInfo, queryErr = c.queryProviderStatus(ctx, hostURI)
+		return queryErr
+	})
+	if err != nil {
+		return nil, attempts, err
+	}
+	return clusterInfo, attempts, nil
+}
+
+// Status failures marked retryable by queryProviderStatus
+func isRetryableStatusError(err error) bool {
+	var retryable *retryableError
+	return errors.As(err, &retryable)
+}
+
 // Query provider status endpoint
 func (c *Client) queryProviderStatus(ctx context.Context, hostURI string) (*ClusterStatus, error) {
 	statusURL := fmt.Sprintf("%s/status", hostURI)
@@ -205,74 +647,70 @@ func (c *Client) queryProviderStatus(ctx context.Context, hostURI string) (*Clus
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query status endpoint %s: %w", statusURL, err)
+		err = fmt.Errorf("failed to query status endpoint %s: %w", statusURL, err)
+		if isTransientNetworkError(err) {
+			return nil, &retryableError{err: err}
+		}
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status endpoint returned %d for %s", resp.StatusCode, statusURL)
-	}
-
-	var status struct {
-		Cluster struct {
-			Leases    int                    `json:"leases"`
-			Inventory map[string]interface{} `json:"inventory"`
-		} `json:"cluster"`
-		ClusterPublicHostname string `json:"cluster_public_hostname"`
+		err := withHTTPStatus(fmt.Errorf("status endpoint returned %d for %s", resp.StatusCode, statusURL), resp.StatusCode)
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+			return nil, &retryableError{err: err}
+		}
+		return nil, err
 	}
 
+	var status map[string]interface{}
 	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
 		return nil, fmt.Errorf("failed to decode status response from %s: %w", statusURL, err)
 	}
 
-	clusterInfo := &ClusterStatus{
-		ActiveLeases:   status.Cluster.Leases,
-		Inventory:      status.Cluster.Inventory,
-		PublicHostname: status.ClusterPublicHostname,
+	// Field locations differ between provider versions
+	schema, recognized := c.detectStatusSchema(status)
+	if !recognized {
+		if _, reported := c.unknownSchemaHosts.LoadOrStore(hostURI, true); !reported {
+			slog.WarnContext(ctx, "Unrecognized status schema", "url", statusURL, "mapping", builtinStatusSchemas[0].Name)
+		}
+		schema = builtinStatusSchemas[0]
+	}
+	clusterInfo := schema.extract(status)
+	if !recognized {
+		clusterInfo.Schema = "unrecognized"
 	}
 
 	// Parse inventory for resource summary
-	clusterInfo.TotalResources, clusterInfo.AvailableResources = c.parseInventory(status.Cluster.Inventory)
-
-	// Count available nodes
-	if inventory, ok := status.Cluster.Inventory["available"]; ok {
-		if availableData, ok := inventory.(map[string]interface{}); ok {
-			if nodes, ok := availableData["nodes"]; ok {
-				if nodesList, ok := nodes.([]interface{}); ok {
-					clusterInfo.AvailableNodes = len(nodesList)
-				}
-			}
-		}
+	clusterInfo.TotalResources, clusterInfo.AvailableResources, clusterInfo.SuspiciousInventory = c.parseInventory(clusterInfo.Inventory)
+	if len(clusterInfo.SuspiciousInventory) > 0 {
+		slog.WarnContext(ctx, "Clamped implausible inventory values", "url", statusURL,
+			"count", len(clusterInfo.SuspiciousInventory), "values", strings.Join(clusterInfo.SuspiciousInventory, "; "))
 	}
 
 	return clusterInfo, nil
 }
 
-// Parse inventory data to extract resource summaries
-func (c *Client) parseInventory(inventory map[string]interface{}) (ResourceSummary, ResourceSummary) {
+// Parse inventory data to extract resource summaries, clamping implausible node values
+func (c *Client) parseInventory(inventory map[string]interface{}) (ResourceSummary, ResourceSummary, []string) {
 	var total, available ResourceSummary
+	var warnings []string
 
 	// Parse available resources
 	if availableData, ok := inventory["available"]; ok {
 		if availableMap, ok := availableData.(map[string]interface{}); ok {
 			if nodes, ok := availableMap["nodes"]; ok {
 				if nodesList, ok := nodes.([]interface{}); ok {
-					for _, node := range nodesList {
+					for i, node := range nodesList {
 						if nodeMap, ok := node.(map[string]interface{}); ok {
 							if availableRes, ok := nodeMap["available"]; ok {
 								if resMap, ok := availableRes.(map[string]interface{}); ok {
-									available.CPU += parseResourceValue(resMap, "cpu")
-									available.Memory += parseResourceValue(resMap, "memory")
-									available.Storage += parseResourceValue(resMap, "storage_ephemeral")
-									available.GPU += int(parseResourceValue(resMap, "gpu"))
+									available.add(c.parseNodeResources(resMap, "available", i, &warnings))
 								}
 							}
 							if allocatableRes, ok := nodeMap["allocatable"]; ok {
 								if resMap, ok := allocatableRes.(map[string]interface{}); ok {
-									total.CPU += parseResourceValue(resMap, "cpu")
-									total.Memory += parseResourceValue(resMap, "memory")
-									total.Storage += parseResourceValue(resMap, "storage_ephemeral")
-									total.GPU += int(parseResourceValue(resMap, "gpu"))
+									total.add(c.parseNodeResources(resMap, "allocatable", i, &warnings))
 								}
 							}
 						}
@@ -282,7 +720,7 @@ func (c *Client) parseInventory(inventory map[string]interface{}) (ResourceSumma
 		}
 	}
 
-	return total, available
+	return total, available, warnings
 }
 
 // Helper function to parse resource values
@@ -325,22 +763,9 @@ func (c *Client) calculateHealthScore(info *ProviderInfo) float64 {
 		}
 	}
 
-	// Active leases scoring (40%) - more leases = more reliable
+	// Active leases scoring (40%) - more leases = more reliable, see LeaseScoring
 	if info.ClusterInfo != nil {
-		leases := info.ClusterInfo.ActiveLeases
-		if leases > 100 {
-			score += 0.4
-		} else if leases > 50 {
-			score += 0.35
-		} else if leases > 20 {
-			score += 0.3
-		} else if leases > 10 {
-			score += 0.25
-		} else if leases > 5 {
-			score += 0.2
-		} else if leases > 0 {
-			score += 0.1
-		}
+		score += c.leaseScore(info.ClusterInfo.ActiveLeases)
 	}
 
 	// Resource availability scoring (10%)
@@ -388,62 +813,18 @@ func (c *Client) calculatePartialHealthScore(info *ProviderInfo) float64 {
 
 // Get provider statistics summary
 func (c *Client) GetProviderStats(providers []*ProviderInfo) map[string]interface{} {
-	stats := map[string]interface{}{
-		"total_providers":       len(providers),
-		"healthy_providers":     0,
-		"providers_with_status": 0,
-		"average_response_time": time.Duration(0),
-		"total_active_leases":   0,
-		"providers_by_region":   make(map[string]int),
-		"providers_with_gpu":    0,
-	}
-
-	if len(providers) == 0 {
-		return stats
-	}
-
-	var totalResponseTime time.Duration
-	var responseTimeCount int
-	regionCount := make(map[string]int)
-
+	accumulator := NewStatsAccumulator()
 	for _, provider := range providers {
-		// Count healthy providers
-		if provider.HealthScore > 0.5 {
-			stats["healthy_providers"] = stats["healthy_providers"].(int) + 1
-		}
-
-		// Count providers with status
-		if provider.ClusterInfo != nil {
-			stats["providers_with_status"] = stats["providers_with_status"].(int) + 1
-			stats["total_active_leases"] = stats["total_active_leases"].(int) + provider.ClusterInfo.ActiveLeases
-		}
-
-		// Calculate average response time
-		if provider.StatusQueryTime > 0 {
-			totalResponseTime += provider.StatusQueryTime
-			responseTimeCount++
-		}
-
-		// Count by region
-		if region, ok := provider.Attributes["region"]; ok {
-			regionCount[region]++
-		}
-
-		// Count GPU providers
-		for key := range provider.Attributes {
-			if key == "capabilities/gpu/vendor/nvidia" {
-				stats["providers_with_gpu"] = stats["providers_with_gpu"].(int) + 1
-				break
-			}
-		}
+		accumulator.Add(provider)
 	}
+	return accumulator.Finalize()
+}
 
-	// Calculate average response time
-	if responseTimeCount > 0 {
-		stats["average_response_time"] = totalResponseTime / time.Duration(responseTimeCount)
+// Get network-wide totals for a set of providers
+func (c *Client) GetNetworkAggregate(providers []*ProviderInfo) NetworkAggregate {
+	accumulator := NewStatsAccumulator()
+	for _, provider := range providers {
+		accumulator.Add(provider)
 	}
-
-	stats["providers_by_region"] = regionCount
-
-	return stats
+	return accumulator.Aggregate()
 }
@@ -0,0 +1,81 @@
+package akash
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	providertypes "github.com/akash-network/akash-api/go/node/provider/v1beta3"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+// Answers provider queries with a fixed response or error
+type fakeProviderQuery struct {
+	providertypes.UnimplementedQueryServer
+	response *providertypes.QueryProviderResponse
+	err      error
+}
+
+func (f *fakeProviderQuery) Provider(ctx context.Context, req *providertypes.QueryProviderRequest) (*providertypes.QueryProviderResponse, error) {
+	return f.response, f.err
+}
+
+// Start a gRPC server answering provider queries with query, and a client
+// pointed at it
+func newTestClient(t *testing.T, query *fakeProviderQuery) *Client {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := grpc.NewServer()
+	providertypes.RegisterQueryServer(server, query)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	client, err := NewClient(&Config{GRPCEndpoints: []string{listener.Addr().String()}})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestQueryBlockchainProviderNotFound(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *fakeProviderQuery
+	}{
+		{
+			name:  "empty provider record",
+			query: &fakeProviderQuery{response: &providertypes.QueryProviderResponse{}},
+		},
+		{
+			name:  "gRPC NotFound",
+			query: &fakeProviderQuery{err: grpcstatus.Error(grpccodes.NotFound, "provider not found")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, tt.query)
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			provider, err := client.queryBlockchainProvider(ctx, "akash1missing")
+			if provider != nil {
+				t.Errorf("got provider %+v, want none", provider)
+			}
+			if !errors.Is(err, ErrProviderNotFound) {
+				t.Fatalf("got error %v, want ErrProviderNotFound", err)
+			}
+			if kind := categorizeFailure(err); kind != FailureNotFound {
+				t.Errorf("got failure kind %q, want %q", kind, FailureNotFound)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package akash
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	_ "google.golang.org/grpc/health" // client side health checking
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+// How to secure the connection to the gRPC endpoints, for hosted gateways that
+// require TLS and often a token
+type GRPCSecurity struct {
+	TLS        bool
+	CAFile     string // PEM bundle trusted instead of the system roots
+	ServerName string // name verified in the certificate instead of the endpoint host
+
+	// Sent as authorization metadata on every call, only over TLS. A bearer
+	// token takes precedence over basic credentials.
+	BearerToken string
+	Username    string
+	Password    string
+}
+
+// Options for the shared connection: transport credentials, per call
+// authorization, keepalive and load balancing
+func grpcDialOptions(security GRPCSecurity) ([]grpc.DialOption, error) {
+	options := []grpc.DialOption{
+		grpc.WithKeepaliveParams(grpcKeepalive),
+		grpc.WithDefaultServiceConfig(grpcServiceConfig),
+	}
+	if !security.TLS {
+		if security.BearerToken != "" || security.Username != "" {
+			return nil, fmt.Errorf("gRPC credentials require TLS")
+		}
+		return append(options, grpc.WithTransportCredentials(insecure.NewCredentials())), nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: security.ServerName,
+	}
+	if security.CAFile != "" {
+		pem, err := os.ReadFile(security.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gRPC CA file: %w", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in gRPC CA file %s", security.CAFile)
+		}
+		tlsConfig.RootCAs = roots
+	}
+	options = append(options, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+
+	switch {
+	case security.BearerToken != "":
+		options = append(options, grpc.WithPerRPCCredentials(authorization("Bearer "+security.BearerToken)))
+	case security.Username != "":
+		basic := base64.StdEncoding.EncodeToString([]byte(security.Username + ":" + security.Password))
+		options = append(options, grpc.WithPerRPCCredentials(authorization("Basic "+basic)))
+	}
+	return options, nil
+}
+
+// A fixed authorization metadata value sent with every call
+type authorization string
+
+func (a authorization) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": string(a)}, nil
+}
+
+// Never send the credentials in plaintext
+func (a authorization) RequireTransportSecurity() bool {
+	return true
+}
+
+// Keepalive pings on an active connection. Nodes running the default gRPC
+// server enforcement policy drop clients that ping more often than every
+// five minutes, so this stays at that floor.
+var grpcKeepalive = keepalive.ClientParameters{
+	Time:    5 * time.Minute,
+	Timeout: 20 * time.Second,
+}
+
+// Resolver scheme of the shared connection, which resolves to the configured
+// endpoint list
+const endpointScheme = "akash-endpoints"
+
+// Spread calls over every endpoint that is connected and passes the standard
+// gRPC health check; one that fails is skipped and reconnected in the
+// background. Endpoints that don't serve the health check count as healthy.
+const grpcServiceConfig = `{
+	"loadBalancingConfig": [{"round_robin": {}}],
+	"healthCheckConfig": {"serviceName": ""}
+}`
+
+// Addresses of the configured endpoints. Each is its own authority, so TLS
+// verifies every endpoint against its own host unless a server name is set.
+func (c *Client) endpointAddresses() []resolver.Address {
+	addresses := make([]resolver.Address, 0, len(c.grpcEndpoints))
+	for _, endpoint := range c.grpcEndpoints {
+		addresses = append(addresses, resolver.Address{Addr: endpoint, ServerName: endpoint})
+	}
+	return addresses
+}
+
+// Get the connection to the Akash gRPC endpoints shared by all queries. It is
+// created on first use and connects lazily; a connection that has been shut
+// down is replaced, and one backing off after a failure is told to retry now.
+func (c *Client) conn() (*grpc.ClientConn, error) {
+	c.connMutex.Lock()
+	defer c.connMutex.Unlock()
+
+	if c.grpcConn != nil {
+		switch c.grpcConn.GetState() {
+		case connectivity.Shutdown:
+			c.grpcConn = nil
+		case connectivity.TransientFailure:
+			c.grpcConn.ResetConnectBackoff()
+		}
+	}
+	if c.grpcConn == nil {
+		endpoints := manual.NewBuilderWithScheme(endpointScheme)
+		endpoints.InitialState(resolver.State{Addresses: c.endpointAddresses()})
+		options := append([]grpc.DialOption{grpc.WithResolvers(endpoints)}, c.dialOptions...)
+		conn, err := grpc.NewClient(endpointScheme+":///akash", options...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to gRPC %s: %w", c.endpointList(), err)
+		}
+		c.grpcConn = conn
+	}
+	return c.grpcConn, nil
+}
+
+// Check that at least one Akash gRPC endpoint accepts connections and is
+// healthy, waiting until one is or ctx is done
+func (c *Client) Ping(ctx context.Context) error {
+	conn, err := c.conn()
+	if err != nil {
+		return err
+	}
+
+	conn.Connect()
+	for {
+		state := conn.GetState()
+		if state == connectivity.Ready {
+			return nil
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("no gRPC endpoint of %s ready (%s): %w", c.endpointList(), state, ctx.Err())
+		}
+	}
+}
+
+// Close the shared gRPC connection. A later query opens a new one.
+func (c *Client) Close() error {
+	c.connMutex.Lock()
+	defer c.connMutex.Unlock()
+
+	if c.grpcConn == nil {
+		return nil
+	}
+	err := c.grpcConn.Close()
+	c.grpcConn = nil
+	return err
+}
+
+// The configured endpoints, for errors and spans
+func (c *Client) endpointList() string {
+	return strings.Join(c.grpcEndpoints, ",")
+}
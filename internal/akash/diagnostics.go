@@ -0,0 +1,117 @@
+package akash
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+// Network failure classes for errors that never produced a response
+const (
+	NetworkConnectionRefused = "connection_refused"
+	NetworkConnectionReset   = "connection_reset"
+	NetworkTimeout           = "timeout"
+	NetworkDNS               = "dns"
+	NetworkTLS               = "tls"
+	NetworkClosed            = "connection_closed"
+)
+
+// Structured codes behind an error message, so operators can tell an
+// Unavailable node from a DeadlineExceeded one, or a 503 from a 429 or a
+// refused connection, without parsing error strings
+type ErrorDiagnostics struct {
+	GRPCCode     string `json:"grpc_code,omitempty"`
+	GRPCCodeNum  *int   `json:"grpc_code_num,omitempty"`
+	GRPCMessage  string `json:"grpc_message,omitempty"`
+	HTTPStatus   int    `json:"http_status,omitempty"`
+	NetworkError string `json:"network_error,omitempty"`
+}
+
+// Carries the diagnostics of a failure whose wrapped error no longer holds them
+type diagnosticError struct {
+	err         error
+	diagnostics ErrorDiagnostics
+}
+
+func (e *diagnosticError) Error() string { return e.err.Error() }
+func (e *diagnosticError) Unwrap() error { return e.err }
+
+// Attach an HTTP status code to an error
+func withHTTPStatus(err error, statusCode int) error {
+	return &diagnosticError{err: err, diagnostics: ErrorDiagnostics{HTTPStatus: statusCode}}
+}
+
+// Attach the gRPC status of cause to an error that replaced it
+func withGRPCStatus(err, cause error) error {
+	diagnostics := ErrorDiagnostics{}
+	setGRPCStatus(&diagnostics, cause)
+	return &diagnosticError{err: err, diagnostics: diagnostics}
+}
+
+// Extract the gRPC status code, HTTP status code and network failure class
+// from an error chain. Returns nil when the error carries none of them.
+func Diagnose(err error) *ErrorDiagnostics {
+	if err == nil {
+		return nil
+	}
+
+	diagnostics := ErrorDiagnostics{}
+	var tagged *diagnosticError
+	if errors.As(err, &tagged) {
+		diagnostics = tagged.diagnostics
+	}
+	if diagnostics.GRPCCode == "" {
+		setGRPCStatus(&diagnostics, err)
+	}
+	diagnostics.NetworkError = classifyNetworkError(err)
+
+	if diagnostics == (ErrorDiagnostics{}) {
+		return nil
+	}
+	return &diagnostics
+}
+
+// Record the gRPC status found anywhere in an error chain
+func setGRPCStatus(diagnostics *ErrorDiagnostics, err error) {
+	var carrier interface{ GRPCStatus() *grpcstatus.Status }
+	if !errors.As(err, &carrier) {
+		return
+	}
+	status := carrier.GRPCStatus()
+	if status == nil {
+		return
+	}
+	code := int(status.Code())
+	diagnostics.GRPCCode = status.Code().String()
+	diagnostics.GRPCCodeNum = &code
+	diagnostics.GRPCMessage = status.Message()
+}
+
+// Classify failures that happened below HTTP and gRPC
+func classifyNetworkError(err error) string {
+	var dnsErr *net.DNSError
+	var certErr *tls.CertificateVerificationError
+	var recordErr tls.RecordHeaderError
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return NetworkConnectionRefused
+	case errors.Is(err, syscall.ECONNRESET):
+		return NetworkConnectionReset
+	case errors.As(err, &dnsErr):
+		return NetworkDNS
+	case errors.As(err, &certErr), errors.As(err, &recordErr):
+		return NetworkTLS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return NetworkTimeout
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return NetworkClosed
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+package akash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Upper bound on the body read from an additional endpoint
+const maxEndpointBodySize = 1 << 20
+
+// Result of querying one additional provider endpoint (e.g. /version)
+type EndpointResult struct {
+	Path       string        `json:"path"`
+	StatusCode int           `json:"status_code,omitempty"`
+	Data       interface{}   `json:"data,omitempty"`
+	QueryTime  time.Duration `json:"query_time"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// Query every configured additional endpoint concurrently. Each endpoint
+// succeeds or fails on its own; failures are recorded in its result.
+func (c *Client) queryProviderEndpoints(ctx context.Context, hostURI string) map[string]*EndpointResult {
+	results := make(map[string]*EndpointResult, len(c.config.ExtraEndpoints))
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, path := range c.config.ExtraEndpoints {
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+
+			result := c.queryProviderEndpoint(ctx, hostURI, path)
+
+			mutex.Lock()
+			results[path] = result
+			mutex.Unlock()
+		}(path)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// Query a single additional endpoint, keeping the body as JSON when possible
+func (c *Client) queryProviderEndpoint(ctx context.Context, hostURI, path string) *EndpointResult {
+	result := &EndpointResult{Path: path}
+	endpointURL := strings.TrimSuffix(hostURI, "/") + "/" + strings.TrimPrefix(path, "/")
+
+	ctx, span := tracer.Start(ctx, "akash.queryProviderEndpoint",
+		trace.WithAttributes(attribute.String("endpoint.path", path)))
+	start := time.Now()
+	defer func() {
+		result.QueryTime = time.Since(start)
+		span.SetAttributes(attribute.Int("http.status_code", result.StatusCode))
+		if result.Error != "" {
+			span.SetAttributes(attribute.String("endpoint.error", result.Error))
+		}
+		span.End()
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpointURL, nil)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to create request: %v", err)
+		return result
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to query %s: %v", endpointURL, err)
+		return result
+	}
+	defer resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	if resp.StatusCode != http.StatusOK {
+		result.Error = fmt.Sprintf("endpoint returned %d for %s", resp.StatusCode, endpointURL)
+		return result
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxEndpointBodySize))
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to read response from %s: %v", endpointURL, err)
+		return result
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		data = strings.TrimSpace(string(body))
+	}
+	result.Data = data
+
+	return result
+}
@@ -0,0 +1,72 @@
+package akash
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GPU capacity of a provider as read from its status inventory
+type GPUAvailability struct {
+	Address    string        `json:"address"`
+	Free       int           `json:"free"`
+	Total      int           `json:"total"`
+	Models     []string      `json:"models,omitempty"` // vendor/model, from advertised attributes
+	QueryTime  time.Duration `json:"query_time"`
+	ObservedAt time.Time     `json:"observed_at"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// Get the host URI and attributes a provider registered on chain
+func (c *Client) GetProviderRegistration(ctx context.Context, providerAddr string) (string, map[string]string, error) {
+	provider, err := c.queryBlockchainProvider(ctx, providerAddr)
+	if err != nil {
+		return "", nil, err
+	}
+
+	attributes := make(map[string]string, len(provider.Attributes))
+	for _, attr := range provider.Attributes {
+		attributes[attr.Key] = attr.Value
+	}
+	return provider.HostURI, attributes, nil
+}
+
+// Read free and total GPUs from a provider's status endpoint. A single attempt
+// without extra endpoints, so the caller's deadline bounds it tightly.
+func (c *Client) QueryGPUAvailability(ctx context.Context, hostURI string) (int, int, error) {
+	if hostURI == "" {
+		return 0, 0, fmt.Errorf("provider has no host URI")
+	}
+
+	cluster, err := c.queryProviderStatus(ctx, hostURI)
+	if err != nil {
+		return 0, 0, err
+	}
+	return cluster.AvailableResources.GPU, cluster.TotalResources.GPU, nil
+}
+
+// Extract advertised GPU models from attributes such as
+// capabilities/gpu/vendor/nvidia/model/a100/ram/80Gi
+func ParseGPUModels(attributes map[string]string) []string {
+	seen := make(map[string]bool)
+	var models []string
+	for key := range attributes {
+		rest, ok := strings.CutPrefix(key, "capabilities/gpu/vendor/")
+		if !ok {
+			continue
+		}
+		parts := strings.Split(rest, "/")
+		if len(parts) < 3 || parts[1] != "model" || parts[2] == "" {
+			continue
+		}
+		model := parts[0] + "/" + parts[2]
+		if !seen[model] {
+			seen[model] = true
+			models = append(models, model)
+		}
+	}
+	sort.Strings(models)
+	return models
+}
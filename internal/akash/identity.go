@@ -0,0 +1,75 @@
+package akash
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Sources of the hostname a cluster fingerprint is derived from
+const (
+	IdentityPublicHostname     = "public_hostname"            // reported by the cluster in /status
+	IdentityRememberedHostname = "remembered_public_hostname" // reported in an earlier observation
+	IdentityHostURI            = "host_uri"                   // on-chain registration
+)
+
+// Bumped whenever the fingerprint derivation changes
+const clusterFingerprintVersion = "cf1"
+
+// Stable identity of the physical cluster behind a provider address
+type ClusterIdentity struct {
+	Fingerprint string   `json:"fingerprint"`
+	Hostname    string   `json:"hostname"`
+	Source      string   `json:"source"`
+	ResolvedIPs []string `json:"resolved_ips,omitempty"`
+}
+
+// Derive a cluster fingerprint from a hostname. Equal hostnames (case and
+// trailing dot aside) always give equal fingerprints.
+func ClusterFingerprint(hostname string) string {
+	sum := sha256.Sum256([]byte("akash-cluster:" + NormalizeHostname(hostname)))
+	return clusterFingerprintVersion + "-" + hex.EncodeToString(sum[:8])
+}
+
+func NormalizeHostname(hostname string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(hostname)), ".")
+}
+
+// Hostname of a host URI, empty if it has none
+func HostURIHostname(hostURI string) string {
+	if !strings.Contains(hostURI, "://") {
+		hostURI = "https://" + hostURI
+	}
+	parsed, err := url.Parse(hostURI)
+	if err != nil {
+		return ""
+	}
+	return NormalizeHostname(parsed.Hostname())
+}
+
+// Resolve the provider host to its IP addresses, sorted. Returns nil if it
+// cannot be resolved within ctx.
+func resolveHost(ctx context.Context, hostURI string) []string {
+	host := HostURIHostname(hostURI)
+	if host == "" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return []string{ip.String()}
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil
+	}
+	ips := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		ips = append(ips, addr.IP.String())
+	}
+	sort.Strings(ips)
+	return ips
+}
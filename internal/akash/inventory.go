@@ -0,0 +1,74 @@
+package akash
+
+import "fmt"
+
+// Upper bounds on what a single node can plausibly report. Values above a
+// bound are clamped to it and the provider is flagged as reporting suspicious
+// inventory, so garbage numbers cannot dominate scoring or aggregate stats.
+type ResourceLimits struct {
+	MaxNodeCPU     int64 // millicores
+	MaxNodeMemory  int64 // bytes
+	MaxNodeStorage int64 // bytes
+	MaxNodeGPU     int64
+}
+
+// Generous defaults: 1024 cores, 16 TiB memory, 1 PiB storage and 64 GPUs per node
+func DefaultResourceLimits() ResourceLimits {
+	return ResourceLimits{
+		MaxNodeCPU:     1024 * 1000,
+		MaxNodeMemory:  16 << 40,
+		MaxNodeStorage: 1 << 50,
+		MaxNodeGPU:     64,
+	}
+}
+
+// Fill unset limits from the defaults
+func (l ResourceLimits) withDefaults() ResourceLimits {
+	defaults := DefaultResourceLimits()
+	if l.MaxNodeCPU <= 0 {
+		l.MaxNodeCPU = defaults.MaxNodeCPU
+	}
+	if l.MaxNodeMemory <= 0 {
+		l.MaxNodeMemory = defaults.MaxNodeMemory
+	}
+	if l.MaxNodeStorage <= 0 {
+		l.MaxNodeStorage = defaults.MaxNodeStorage
+	}
+	if l.MaxNodeGPU <= 0 {
+		l.MaxNodeGPU = defaults.MaxNodeGPU
+	}
+	return l
+}
+
+// Parse one node's resources, clamping implausible values and describing each clamp
+func (c *Client) parseNodeResources(resMap map[string]interface{}, section string, node int, warnings *[]string) ResourceSummary {
+	limits := c.config.ResourceLimits.withDefaults()
+
+	clamp := func(key string, limit int64) int64 {
+		value := parseResourceValue(resMap, key)
+		switch {
+		case value < 0:
+			*warnings = append(*warnings, fmt.Sprintf("node %d %s %s is negative (%d), treated as 0", node, section, key, value))
+			return 0
+		case value > limit:
+			*warnings = append(*warnings, fmt.Sprintf("node %d %s %s of %d exceeds limit %d, clamped", node, section, key, value, limit))
+			return limit
+		}
+		return value
+	}
+
+	return ResourceSummary{
+		CPU:     clamp("cpu", limits.MaxNodeCPU),
+		Memory:  clamp("memory", limits.MaxNodeMemory),
+		Storage: clamp("storage_ephemeral", limits.MaxNodeStorage),
+		GPU:     int(clamp("gpu", limits.MaxNodeGPU)),
+	}
+}
+
+// Add a node's resources to a running summary
+func (r *ResourceSummary) add(other ResourceSummary) {
+	r.CPU += other.CPU
+	r.Memory += other.Memory
+	r.Storage += other.Storage
+	r.GPU += other.GPU
+}
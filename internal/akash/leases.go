@@ -0,0 +1,106 @@
+package akash
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Treatment of providers reporting zero active leases
+const (
+	ZeroLeasePenalize = "penalize" // no lease points, as for any other lease count below the first tier
+	ZeroLeaseNeutral  = "neutral"  // NeutralScore lease points, so new providers are judged on the rest
+)
+
+// Maximum share of the health score earned from active leases
+const maxLeaseScore = 0.4
+
+// Lease points awarded once a provider has more than Above active leases
+type LeaseTier struct {
+	Above int     `yaml:"above" json:"above"`
+	Score float64 `yaml:"score" json:"score"`
+}
+
+// Curve mapping active leases to health score points (at most 0.4).
+//
+// Lease count is the best available evidence that a provider actually runs
+// workloads, so the default curve rewards proven providers and gives nothing
+// for zero leases. That also buries brand-new providers however fast and
+// well-resourced they are; the neutral policy scores zero leases at
+// NeutralScore instead, trading some of that evidence for a fair shot.
+type LeaseScoring struct {
+	Tiers           []LeaseTier
+	ZeroLeasePolicy string
+	NeutralScore    float64
+}
+
+// Curve used before lease scoring was configurable
+func DefaultLeaseScoring() LeaseScoring {
+	return LeaseScoring{
+		Tiers: []LeaseTier{
+			{Above: 100, Score: 0.4},
+			{Above: 50, Score: 0.35},
+			{Above: 20, Score: 0.3},
+			{Above: 10, Score: 0.25},
+			{Above: 5, Score: 0.2},
+			{Above: 0, Score: 0.1},
+		},
+		ZeroLeasePolicy: ZeroLeasePenalize,
+		NeutralScore:    maxLeaseScore / 2,
+	}
+}
+
+// Fill unset fields from the defaults and order tiers highest threshold first
+func (l LeaseScoring) withDefaults() LeaseScoring {
+	defaults := DefaultLeaseScoring()
+	if len(l.Tiers) == 0 {
+		l.Tiers = defaults.Tiers
+	} else {
+		l.Tiers = append([]LeaseTier(nil), l.Tiers...)
+		sort.Slice(l.Tiers, func(i, j int) bool {
+			return l.Tiers[i].Above > l.Tiers[j].Above
+		})
+	}
+	if l.ZeroLeasePolicy == "" {
+		l.ZeroLeasePolicy = defaults.ZeroLeasePolicy
+	}
+	if l.NeutralScore <= 0 {
+		l.NeutralScore = defaults.NeutralScore
+	}
+	return l
+}
+
+// Validate a lease scoring configuration
+func (l LeaseScoring) Validate() error {
+	switch l.ZeroLeasePolicy {
+	case "", ZeroLeasePenalize, ZeroLeaseNeutral:
+	default:
+		return fmt.Errorf("unknown zero lease policy %q (valid: %s, %s)", l.ZeroLeasePolicy, ZeroLeasePenalize, ZeroLeaseNeutral)
+	}
+	if l.NeutralScore < 0 || l.NeutralScore > maxLeaseScore {
+		return fmt.Errorf("neutral lease score must be between 0 and %.1f, got %v", maxLeaseScore, l.NeutralScore)
+	}
+	for _, tier := range l.Tiers {
+		if tier.Above < 0 {
+			return fmt.Errorf("lease tier threshold must not be negative, got %d", tier.Above)
+		}
+		if tier.Score < 0 || tier.Score > maxLeaseScore {
+			return fmt.Errorf("lease tier score must be between 0 and %.1f, got %v", maxLeaseScore, tier.Score)
+		}
+	}
+	return nil
+}
+
+// Health score points for a number of active leases
+func (c *Client) leaseScore(leases int) float64 {
+	scoring := c.config.LeaseScoring.withDefaults()
+
+	if leases == 0 && scoring.ZeroLeasePolicy == ZeroLeaseNeutral {
+		return scoring.NeutralScore
+	}
+	for _, tier := range scoring.Tiers {
+		if leases > tier.Above {
+			return tier.Score
+		}
+	}
+	return 0
+}
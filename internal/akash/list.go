@@ -0,0 +1,265 @@
+package akash
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	audittypes "github.com/akash-network/akash-api/go/node/audit/v1beta3"
+	providertypes "github.com/akash-network/akash-api/go/node/provider/v1beta3"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"golang.org/x/sync/semaphore"
+)
+
+// Page size used when walking paginated chain queries
+const listPageSize = 500
+
+// Provider list pages fetched at once when ListConcurrency is not set
+const defaultListConcurrency = 4
+
+type ListProvidersOptions struct {
+	// Only return providers with attributes signed by an auditor
+	AuditedOnly bool
+}
+
+// Provider as registered on chain, before any status enrichment
+type ListedProvider struct {
+	Address    string            `json:"address"`
+	HostURI    string            `json:"host_uri"`
+	Attributes map[string]string `json:"attributes"`
+	Auditors   []string          `json:"auditors,omitempty"`
+}
+
+type ProviderList struct {
+	Providers []*ListedProvider `json:"providers"`
+
+	// Set when the audit filter was requested but could not be applied
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// List providers registered on chain. With AuditedOnly the list is narrowed using
+// the audit module so that enrichment only runs on audited providers; if the audit
+// query fails, all providers are returned with a warning instead.
+func (c *Client) ListProviders(ctx context.Context, opts ListProvidersOptions) (*ProviderList, error) {
+	conn, err := c.queryConn()
+	if err != nil {
+		return nil, err
+	}
+
+	list := &ProviderList{}
+
+	// The audit query is independent of the provider pages, so run it alongside them
+	var auditors map[string][]string
+	var auditErr error
+	var auditWG sync.WaitGroup
+	if opts.AuditedOnly {
+		auditWG.Add(1)
+		go func() {
+			defer auditWG.Done()
+			auditors, auditErr = queryAuditedProviders(ctx, audittypes.NewQueryClient(conn))
+		}()
+	}
+
+	providers, err := c.fetchProviderPages(ctx, providertypes.NewQueryClient(conn))
+	auditWG.Wait()
+	if err != nil {
+		return nil, err
+	}
+	if auditErr != nil {
+		auditors = nil
+		list.Warnings = append(list.Warnings, fmt.Sprintf("audit filter not applied, returning all providers: %v", auditErr))
+		slog.WarnContext(ctx, "Audit query failed, listing all providers", "error", auditErr)
+	}
+
+	for _, provider := range providers {
+		if auditors != nil && len(auditors[provider.Owner]) == 0 {
+			continue
+		}
+
+		c.rememberHostURI(provider.Owner, provider.HostURI)
+
+		listed := &ListedProvider{
+			Address:    provider.Owner,
+			HostURI:    provider.HostURI,
+			Attributes: make(map[string]string, len(provider.Attributes)),
+			Auditors:   auditors[provider.Owner],
+		}
+		for _, attr := range provider.Attributes {
+			listed.Attributes[attr.Key] = attr.Value
+		}
+		list.Providers = append(list.Providers, listed)
+	}
+
+	return list, nil
+}
+
+// Fetch every provider record. The first page reports the total count; the
+// pages after it are then fetched by offset, up to ListConcurrency at a time,
+// and the listing is finished by page key from where they end. Endpoints that
+// don't report a total are walked sequentially by page key.
+func (c *Client) fetchProviderPages(ctx context.Context, client providertypes.QueryClient) ([]providertypes.Provider, error) {
+	first, err := client.Providers(ctx, &providertypes.QueryProvidersRequest{
+		Pagination: &query.PageRequest{Limit: listPageSize, CountTotal: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list providers: %w", err)
+	}
+
+	list := &providerListing{seen: make(map[string]bool)}
+	list.add(first.Providers)
+	nextKey := pageNextKey(first.Pagination)
+	if len(nextKey) == 0 {
+		return list.providers, nil
+	}
+
+	concurrency := c.config.ListConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultListConcurrency
+	}
+	total := first.Pagination.Total
+	if concurrency == 1 || total <= uint64(len(first.Providers)) {
+		return c.fetchRemainingPagesByKey(ctx, client, list, nextKey)
+	}
+
+	pages, err := fetchOffsetPages(ctx, client, total, concurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	// Offsets are stable only while the provider set is. Consecutive pages
+	// overlap, so a page still holding the last provider of the one before it
+	// proves nothing between them was skipped; records shifted by providers
+	// registered or removed mid-listing are deduplicated here. Once a page
+	// doesn't join up, the rest is walked by key from the last one that did.
+	last := first.Providers
+	for _, page := range pages {
+		if len(last) == 0 || !containsProvider(page.Providers, last[len(last)-1].Owner) {
+			break
+		}
+		list.add(page.Providers)
+		last = page.Providers
+		nextKey = pageNextKey(page.Pagination)
+	}
+
+	// Continue by key from the last joined page, which also picks up providers
+	// registered after the first page counted them
+	return c.fetchRemainingPagesByKey(ctx, client, list, nextKey)
+}
+
+// Records shared by consecutive offset pages, so a few providers removed
+// mid-listing can't shift one across a page boundary unseen
+const listPageOverlap = listPageSize / 10
+
+// Fetch the pages after the first by offset, overlapping by listPageOverlap,
+// up to concurrency at a time
+func fetchOffsetPages(ctx context.Context, client providertypes.QueryClient, total uint64, concurrency int) ([]*providertypes.QueryProvidersResponse, error) {
+	const step = listPageSize - listPageOverlap
+	var offsets []uint64
+	for offset := uint64(step); offset < total; offset += step {
+		offsets = append(offsets, offset)
+	}
+
+	pages := make([]*providertypes.QueryProvidersResponse, len(offsets))
+	errs := make([]error, len(offsets))
+	sem := semaphore.NewWeighted(int64(concurrency))
+	var wg sync.WaitGroup
+	for i, offset := range offsets {
+		if err := sem.Acquire(ctx, 1); err != nil {
+			wg.Wait()
+			return nil, fmt.Errorf("failed to list providers: %w", err)
+		}
+		wg.Add(1)
+		go func(i int, offset uint64) {
+			defer wg.Done()
+			defer sem.Release(1)
+
+			pages[i], errs[i] = client.Providers(ctx, &providertypes.QueryProvidersRequest{
+				Pagination: &query.PageRequest{Offset: offset, Limit: listPageSize},
+			})
+		}(i, offset)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("failed to list providers (page %d): %w", i+2, err)
+		}
+	}
+	return pages, nil
+}
+
+// Walk the remaining pages by page key, requesting each page as soon as its key is known
+func (c *Client) fetchRemainingPagesByKey(ctx context.Context, client providertypes.QueryClient, list *providerListing, nextKey []byte) ([]providertypes.Provider, error) {
+	for len(nextKey) > 0 {
+		resp, err := client.Providers(ctx, &providertypes.QueryProvidersRequest{
+			Pagination: &query.PageRequest{Key: nextKey, Limit: listPageSize},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list providers: %w", err)
+		}
+		list.add(resp.Providers)
+		nextKey = pageNextKey(resp.Pagination)
+	}
+	return list.providers, nil
+}
+
+// Provider records collected so far, each owner once
+type providerListing struct {
+	providers []providertypes.Provider
+	seen      map[string]bool
+}
+
+func (l *providerListing) add(page []providertypes.Provider) {
+	for _, provider := range page {
+		if !l.seen[provider.Owner] {
+			l.seen[provider.Owner] = true
+			l.providers = append(l.providers, provider)
+		}
+	}
+}
+
+func containsProvider(page []providertypes.Provider, owner string) bool {
+	for _, provider := range page {
+		if provider.Owner == owner {
+			return true
+		}
+	}
+	return false
+}
+
+// Key of the page after this one, nil on the last page
+func pageNextKey(pagination *query.PageResponse) []byte {
+	if pagination == nil {
+		return nil
+	}
+	return pagination.NextKey
+}
+
+// Get the auditors that signed attributes for each audited provider
+func queryAuditedProviders(ctx context.Context, client audittypes.QueryClient) (map[string][]string, error) {
+	auditors := make(map[string][]string)
+
+	var nextKey []byte
+	for {
+		resp, err := client.AllProvidersAttributes(ctx, &audittypes.QueryAllProvidersAttributesRequest{
+			Pagination: &query.PageRequest{Key: nextKey, Limit: listPageSize},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query audited attributes: %w", err)
+		}
+
+		for _, provider := range resp.Providers {
+			if len(provider.Attributes) > 0 {
+				auditors[provider.Owner] = append(auditors[provider.Owner], provider.Auditor)
+			}
+		}
+
+		if resp.Pagination == nil || len(resp.Pagination.NextKey) == 0 {
+			break
+		}
+		nextKey = resp.Pagination.NextKey
+	}
+
+	return auditors, nil
+}
@@ -0,0 +1,153 @@
+package akash
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	providertypes "github.com/akash-network/akash-api/go/node/provider/v1beta3"
+	"github.com/cosmos/cosmos-sdk/types/query"
+	"google.golang.org/grpc"
+)
+
+// Paginates provider records by key or offset like the chain's store, keyed
+// by owner. changed runs before each query is answered, to register or remove
+// providers mid-listing.
+type fakeProviderStore struct {
+	providertypes.QueryClient
+	owners  []string // sorted
+	latency time.Duration
+	changed func(call int, store *fakeProviderStore)
+	calls   int
+	mutex   sync.Mutex
+}
+
+func newFakeProviderStore(n int) *fakeProviderStore {
+	store := &fakeProviderStore{}
+	for i := 0; i < n; i++ {
+		store.owners = append(store.owners, fmt.Sprintf("akash1provider%05d", i))
+	}
+	return store
+}
+
+func (f *fakeProviderStore) remove(owners ...string) {
+	for _, owner := range owners {
+		i := sort.SearchStrings(f.owners, owner)
+		f.owners = append(f.owners[:i], f.owners[i+1:]...)
+	}
+}
+
+func (f *fakeProviderStore) Providers(ctx context.Context, req *providertypes.QueryProvidersRequest, opts ...grpc.CallOption) (*providertypes.QueryProvidersResponse, error) {
+	time.Sleep(f.latency)
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.calls++
+	if f.changed != nil {
+		f.changed(f.calls, f)
+	}
+
+	page := req.Pagination
+	start := int(page.Offset)
+	if page.Key != nil {
+		start = sort.SearchStrings(f.owners, string(page.Key))
+	}
+	start = min(start, len(f.owners))
+	end := min(start+int(page.Limit), len(f.owners))
+
+	resp := &providertypes.QueryProvidersResponse{Pagination: &query.PageResponse{}}
+	for _, owner := range f.owners[start:end] {
+		resp.Providers = append(resp.Providers, providertypes.Provider{Owner: owner})
+	}
+	if end < len(f.owners) {
+		resp.Pagination.NextKey = []byte(f.owners[end])
+	}
+	if page.CountTotal {
+		resp.Pagination.Total = uint64(len(f.owners))
+	}
+	return resp, nil
+}
+
+func TestFetchProviderPagesWhileProvidersChange(t *testing.T) {
+	tests := []struct {
+		name    string
+		changed func(call int, store *fakeProviderStore)
+	}{
+		{
+			name: "unchanged",
+		},
+		{
+			name: "few removed before a page boundary",
+			changed: func(call int, store *fakeProviderStore) {
+				if call == 2 {
+					store.remove("akash1provider00010", "akash1provider00011")
+				}
+			},
+		},
+		{
+			name: "more removed than pages overlap",
+			changed: func(call int, store *fakeProviderStore) {
+				if call == 2 {
+					store.remove(store.owners[100 : 100+2*listPageOverlap]...)
+				}
+			},
+		},
+		{
+			name: "registered after the total was counted",
+			changed: func(call int, store *fakeProviderStore) {
+				if call == 2 {
+					store.owners = append(store.owners, "akash1zregistered0", "akash1zregistered1")
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeProviderStore(3*listPageSize + 7)
+			store.changed = tt.changed
+			client := &Client{config: &Config{ListConcurrency: 4}}
+
+			providers, err := client.fetchProviderPages(context.Background(), store)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			listed := make(map[string]int, len(providers))
+			for _, provider := range providers {
+				listed[provider.Owner]++
+			}
+			for _, owner := range store.owners {
+				if listed[owner] != 1 {
+					t.Errorf("%s listed %d times, want once", owner, listed[owner])
+				}
+			}
+		})
+	}
+}
+
+// Listing a realistic number of providers over an endpoint with 50ms round trips
+func BenchmarkFetchProviderPages(b *testing.B) {
+	for _, count := range []int{1000, 5000} {
+		for _, concurrency := range []int{1, 4} {
+			b.Run(fmt.Sprintf("providers=%d/concurrency=%d", count, concurrency), func(b *testing.B) {
+				store := newFakeProviderStore(count)
+				store.latency = 50 * time.Millisecond
+				client := &Client{config: &Config{ListConcurrency: concurrency}}
+
+				for i := 0; i < b.N; i++ {
+					providers, err := client.fetchProviderPages(context.Background(), store)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if len(providers) != count {
+						b.Fatalf("listed %d providers, want %d", len(providers), count)
+					}
+				}
+			})
+		}
+	}
+}
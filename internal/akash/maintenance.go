@@ -0,0 +1,73 @@
+package akash
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Attributes a provider can use to advertise planned maintenance. maintenance/window
+// holds one or more comma separated RFC3339 intervals ("start/end"); a single window
+// can also be given as maintenance/start and maintenance/end.
+const (
+	MaintenanceAttributeWindow = "maintenance/window"
+	MaintenanceAttributeStart  = "maintenance/start"
+	MaintenanceAttributeEnd    = "maintenance/end"
+)
+
+// Planned provider downtime
+type MaintenanceWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Check whether the window overlaps [from, to]
+func (w MaintenanceWindow) Overlaps(from, to time.Time) bool {
+	return w.Start.Before(to) && w.End.After(from)
+}
+
+// Parse advertised maintenance windows, soonest first. Malformed values are skipped.
+func ParseMaintenanceWindows(attributes map[string]string) []MaintenanceWindow {
+	var windows []MaintenanceWindow
+
+	if value, ok := attributes[MaintenanceAttributeWindow]; ok {
+		for _, interval := range strings.Split(value, ",") {
+			bounds := strings.SplitN(strings.TrimSpace(interval), "/", 2)
+			if len(bounds) != 2 {
+				continue
+			}
+			if window, err := newMaintenanceWindow(bounds[0], bounds[1]); err == nil {
+				windows = append(windows, window)
+			}
+		}
+	}
+
+	start, hasStart := attributes[MaintenanceAttributeStart]
+	end, hasEnd := attributes[MaintenanceAttributeEnd]
+	if hasStart && hasEnd {
+		if window, err := newMaintenanceWindow(start, end); err == nil {
+			windows = append(windows, window)
+		}
+	}
+
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].Start.Before(windows[j].Start)
+	})
+	return windows
+}
+
+func newMaintenanceWindow(start, end string) (MaintenanceWindow, error) {
+	startTime, err := time.Parse(time.RFC3339, strings.TrimSpace(start))
+	if err != nil {
+		return MaintenanceWindow{}, err
+	}
+	endTime, err := time.Parse(time.RFC3339, strings.TrimSpace(end))
+	if err != nil {
+		return MaintenanceWindow{}, err
+	}
+	if !endTime.After(startTime) {
+		return MaintenanceWindow{}, fmt.Errorf("maintenance window ends before it starts")
+	}
+	return MaintenanceWindow{Start: startTime, End: endTime}, nil
+}
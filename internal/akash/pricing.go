@@ -0,0 +1,136 @@
+package akash
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Attributes a provider can use to advertise its pricing. Values are an amount
+// followed by a denom (e.g. "1.5uakt", "0.25 usdc"); rates are per CPU core, per
+// GiB of memory or storage, or per GPU, for the period given by pricing/period.
+const (
+	PricingAttributeCPU     = "pricing/cpu"
+	PricingAttributeMemory  = "pricing/memory"
+	PricingAttributeStorage = "pricing/storage"
+	PricingAttributeGPU     = "pricing/gpu"
+	PricingAttributePeriod  = "pricing/period" // block (default), hour, day or month
+)
+
+// Average Akash block time, used to convert per-block rates
+const averageBlockTime = 6098 * time.Millisecond
+
+const month = 30 * 24 * time.Hour
+
+// Provider-advertised pricing, normalized to micro-denom per unit per 30-day month
+type PricingPolicy struct {
+	Denom              string  `json:"denom"`
+	CPUPerCoreMonth    float64 `json:"cpu_per_core_month,omitempty"`
+	MemoryPerGiBMonth  float64 `json:"memory_per_gib_month,omitempty"`
+	StoragePerGiBMonth float64 `json:"storage_per_gib_month,omitempty"`
+	GPUPerUnitMonth    float64 `json:"gpu_per_unit_month,omitempty"`
+
+	// Attributes that looked like pricing but could not be used
+	Ignored []string `json:"ignored,omitempty"`
+}
+
+// Parse the recognized pricing attributes. Returns nil if the provider advertises no pricing.
+func ParsePricingPolicy(attributes map[string]string) *PricingPolicy {
+	periodsPerMonth := float64(month / averageBlockTime)
+	if period, ok := attributes[PricingAttributePeriod]; ok {
+		switch strings.ToLower(strings.TrimSpace(period)) {
+		case "block", "":
+		case "hour":
+			periodsPerMonth = float64(month / time.Hour)
+		case "day":
+			periodsPerMonth = 30
+		case "month":
+			periodsPerMonth = 1
+		default:
+			return &PricingPolicy{Ignored: []string{fmt.Sprintf("%s: unknown period %q", PricingAttributePeriod, period)}}
+		}
+	}
+
+	policy := &PricingPolicy{}
+	found := false
+
+	rates := []struct {
+		key  string
+		rate *float64
+	}{
+		{PricingAttributeCPU, &policy.CPUPerCoreMonth},
+		{PricingAttributeMemory, &policy.MemoryPerGiBMonth},
+		{PricingAttributeStorage, &policy.StoragePerGiBMonth},
+		{PricingAttributeGPU, &policy.GPUPerUnitMonth},
+	}
+
+	for _, rate := range rates {
+		value, ok := attributes[rate.key]
+		if !ok {
+			continue
+		}
+		found = true
+
+		amount, denom, err := parsePrice(value)
+		if err != nil {
+			policy.Ignored = append(policy.Ignored, fmt.Sprintf("%s: %v", rate.key, err))
+			continue
+		}
+		if policy.Denom == "" {
+			policy.Denom = denom
+		} else if denom != policy.Denom {
+			policy.Ignored = append(policy.Ignored, fmt.Sprintf("%s: denom %s does not match %s", rate.key, denom, policy.Denom))
+			continue
+		}
+		*rate.rate = amount * periodsPerMonth
+	}
+
+	if !found {
+		return nil
+	}
+	return policy
+}
+
+// Monthly cost of a reference workload (1 CPU core, 2 GiB memory, 10 GiB storage).
+// Only comparable when both CPU and memory rates are advertised.
+func (p *PricingPolicy) ReferenceMonthlyCost() (float64, bool) {
+	if p == nil || p.Denom == "" || p.CPUPerCoreMonth <= 0 || p.MemoryPerGiBMonth <= 0 {
+		return 0, false
+	}
+	return p.CPUPerCoreMonth + 2*p.MemoryPerGiBMonth + 10*p.StoragePerGiBMonth, true
+}
+
+// Parse "1.5uakt" or "0.25 akt" into an amount in the micro denom
+func parsePrice(value string) (float64, string, error) {
+	value = strings.TrimSpace(value)
+	split := strings.IndexFunc(value, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if split <= 0 {
+		return 0, "", fmt.Errorf("expected an amount followed by a denom, got %q", value)
+	}
+
+	amount, err := strconv.ParseFloat(value[:split], 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid amount in %q", value)
+	}
+	if amount < 0 {
+		return 0, "", fmt.Errorf("negative amount in %q", value)
+	}
+
+	raw := strings.TrimSpace(value[split:])
+	denom := strings.ToLower(raw)
+	switch denom {
+	case "akt":
+		return amount * 1e6, "uakt", nil
+	case "usdc":
+		return amount * 1e6, "uusdc", nil
+	case "uakt", "uusdc":
+		return amount, denom, nil
+	}
+	if strings.HasPrefix(denom, "ibc/") {
+		return amount, "ibc/" + strings.ToUpper(raw[4:]), nil
+	}
+	return 0, "", fmt.Errorf("unknown denom %q", denom)
+}
@@ -0,0 +1,22 @@
+package akash
+
+import "context"
+
+// Receives provider fetch progress. A request may fetch in several batches, so
+// Queued can be called more than once; Completed is called once per provider.
+type Progress interface {
+	Queued(providers int)
+	Completed(address string, err error)
+}
+
+type progressKey struct{}
+
+// Report provider fetches made with ctx to progress
+func WithProgress(ctx context.Context, progress Progress) context.Context {
+	return context.WithValue(ctx, progressKey{}, progress)
+}
+
+func progressFrom(ctx context.Context) Progress {
+	progress, _ := ctx.Value(progressKey{}).(Progress)
+	return progress
+}
@@ -0,0 +1,108 @@
+package akash
+
+import (
+	"context"
+	"log/slog"
+	"math/rand"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+// How one kind of query is retried
+type RetryPolicy struct {
+	Attempts   int           // total attempts; 1 or less disables retrying
+	Backoff    time.Duration // delay before the first retry, doubled after each
+	MaxBackoff time.Duration // cap on the delay; 0 leaves it uncapped
+	Jitter     float64       // share of each delay drawn at random: 0 none, 1 full jitter
+
+	// Longest an attempt may take while more remain, so one that hangs leaves
+	// time to retry; 0 lets every attempt run to the query deadline
+	AttemptTimeout time.Duration
+}
+
+// Delay before the given retry, 1 being the first
+func (p RetryPolicy) delay(retry int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < retry && (p.MaxBackoff <= 0 || delay < p.MaxBackoff); i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+
+	// Spread out retries of queries that failed together
+	if p.Jitter > 0 {
+		delay -= time.Duration(rand.Float64() * p.Jitter * float64(delay))
+	}
+	return delay
+}
+
+// Run query until it succeeds, fails with an error retryable rejects, or runs
+// out of attempts, reporting the attempts made. An attempt cut short by
+// AttemptTimeout is always retryable; the last one gets whatever time is left.
+// Retries never sleep past ctx's deadline.
+func (p RetryPolicy) do(ctx context.Context, what string, retryable func(error) bool, query func(ctx context.Context) error) (int, error) {
+	maxAttempts := p.Attempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if p.AttemptTimeout > 0 && attempt < maxAttempts {
+			attemptCtx, cancel = context.WithTimeout(ctx, p.AttemptTimeout)
+		}
+		err := query(attemptCtx)
+		timedOut := attemptCtx.Err() != nil && ctx.Err() == nil
+		cancel()
+
+		if err == nil || attempt == maxAttempts || ctx.Err() != nil || !(timedOut || retryable(err)) {
+			return attempt, err
+		}
+
+		delay := p.delay(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+			return attempt, err
+		}
+
+		slog.DebugContext(ctx, "Retrying "+what, "attempt", attempt, "backoff", delay, "error", err)
+		trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+			attribute.Int("retry.attempt", attempt),
+			attribute.Int64("retry.backoff_ms", delay.Milliseconds()),
+			attribute.String("retry.error", err.Error()),
+		))
+		select {
+		case <-ctx.Done():
+			return attempt, err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// Chain query failures worth retrying: the endpoint unreachable, overloaded
+// or too slow. A missing record or a rejected request fails the same again.
+func isRetryableChainError(err error) bool {
+	switch grpcstatus.Code(err) {
+	case grpccodes.Unavailable, grpccodes.ResourceExhausted, grpccodes.Aborted, grpccodes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// Retries unary chain queries under the chain retry policy
+type retryingConn struct {
+	grpc.ClientConnInterface
+	policy RetryPolicy
+}
+
+func (r retryingConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	_, err := r.policy.do(ctx, "chain query "+method, isRetryableChainError, func(ctx context.Context) error {
+		return r.ClientConnInterface.Invoke(ctx, method, args, reply, opts...)
+	})
+	return err
+}
@@ -0,0 +1,200 @@
+package akash
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+)
+
+// Longest an RPC fallback query may take, and the largest answer read
+const (
+	rpcTimeout          = 10 * time.Second
+	maxRPCResponseBytes = 32 << 20
+)
+
+// Chain queries over the CometBFT RPC endpoint. Its abci_query method answers
+// the same protobuf queries as the gRPC endpoint, over plain HTTP(S), so the
+// generated query clients work on top of it unchanged.
+type rpcConn struct {
+	endpoint   string
+	httpClient *http.Client
+}
+
+func newRPCConn(endpoint string) *rpcConn {
+	return &rpcConn{
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		httpClient: &http.Client{Timeout: rpcTimeout},
+	}
+}
+
+type abciQueryResponse struct {
+	Result *struct {
+		Response struct {
+			Code      uint32 `json:"code"`
+			Log       string `json:"log"`
+			Codespace string `json:"codespace"`
+			Value     []byte `json:"value"`
+		} `json:"response"`
+	} `json:"result"`
+	Error *struct {
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	} `json:"error"`
+}
+
+// Run a unary query through abci_query. Failures carry a gRPC status so they
+// are classified like gRPC ones: Unavailable when the endpoint can't be
+// reached, NotFound for a missing record.
+func (r *rpcConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	request, ok := args.(interface{ Marshal() ([]byte, error) })
+	if !ok {
+		return grpcstatus.Errorf(grpccodes.Internal, "cannot encode %T for RPC", args)
+	}
+	response, ok := reply.(interface{ Unmarshal([]byte) error })
+	if !ok {
+		return grpcstatus.Errorf(grpccodes.Internal, "cannot decode %T from RPC", reply)
+	}
+
+	data, err := request.Marshal()
+	if err != nil {
+		return grpcstatus.Errorf(grpccodes.Internal, "failed to encode %s request: %v", method, err)
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "abci_query",
+		"params":  map[string]string{"path": method, "data": hex.EncodeToString(data)},
+	})
+	if err != nil {
+		return grpcstatus.Errorf(grpccodes.Internal, "failed to encode %s request: %v", method, err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return grpcstatus.Errorf(grpccodes.InvalidArgument, "invalid RPC endpoint %s: %v", r.endpoint, err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := r.httpClient.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return grpcstatus.FromContextError(ctx.Err()).Err()
+		}
+		return grpcstatus.Errorf(grpccodes.Unavailable, "RPC %s: %v", r.endpoint, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return grpcstatus.Errorf(grpccodes.Unavailable, "RPC %s returned HTTP %d", r.endpoint, resp.StatusCode)
+	}
+
+	var result abciQueryResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxRPCResponseBytes)).Decode(&result); err != nil {
+		return grpcstatus.Errorf(grpccodes.Unavailable, "RPC %s: invalid response: %v", r.endpoint, err)
+	}
+	if result.Error != nil {
+		return grpcstatus.Errorf(grpccodes.Unknown, "RPC %s: %s %s", r.endpoint, result.Error.Message, result.Error.Data)
+	}
+	if result.Result == nil {
+		return grpcstatus.Errorf(grpccodes.Unavailable, "RPC %s: empty response", r.endpoint)
+	}
+	if answer := result.Result.Response; answer.Code != 0 {
+		code := grpccodes.Unknown
+		if strings.Contains(strings.ToLower(answer.Log), "not found") {
+			code = grpccodes.NotFound
+		}
+		return grpcstatus.Errorf(code, "%s (%s code %d)", answer.Log, answer.Codespace, answer.Code)
+	}
+
+	if resettable, ok := reply.(interface{ Reset() }); ok {
+		resettable.Reset()
+	}
+	if err := response.Unmarshal(result.Result.Response.Value); err != nil {
+		return grpcstatus.Errorf(grpccodes.Internal, "failed to decode %s response: %v", method, err)
+	}
+	return nil
+}
+
+func (r *rpcConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, grpcstatus.Errorf(grpccodes.Unimplemented, "streaming %s is not supported over RPC", method)
+}
+
+// Check the RPC endpoint answers its health check
+func (r *rpcConn) ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.endpoint+"/health", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("RPC %s unreachable: %w", r.endpoint, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("RPC %s returned HTTP %d", r.endpoint, resp.StatusCode)
+	}
+	return nil
+}
+
+// Sends chain queries over gRPC, and over RPC while no gRPC endpoint can be
+// reached
+type fallbackConn struct {
+	grpc *grpc.ClientConn
+	rpc  *rpcConn
+}
+
+func (f fallbackConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	err := f.grpc.Invoke(ctx, method, args, reply, opts...)
+	if grpcstatus.Code(err) != grpccodes.Unavailable || ctx.Err() != nil {
+		return err
+	}
+
+	slog.DebugContext(ctx, "gRPC unavailable, querying the RPC endpoint", "method", method, "error", err)
+	trace.SpanFromContext(ctx).AddEvent("rpc fallback", trace.WithAttributes(
+		attribute.String("grpc.error", err.Error()),
+		attribute.String("rpc.endpoint", f.rpc.endpoint),
+	))
+	return f.rpc.Invoke(ctx, method, args, reply)
+}
+
+func (f fallbackConn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return f.grpc.NewStream(ctx, desc, method, opts...)
+}
+
+// Connection for chain queries: the shared gRPC connection, backed by the RPC
+// endpoint when one is configured, with failed queries retried
+func (c *Client) queryConn() (grpc.ClientConnInterface, error) {
+	conn, err := c.conn()
+	if err != nil {
+		return nil, err
+	}
+	var query grpc.ClientConnInterface = conn
+	if c.rpc != nil {
+		query = fallbackConn{grpc: conn, rpc: c.rpc}
+	}
+	return retryingConn{ClientConnInterface: query, policy: c.config.ChainRetry}, nil
+}
+
+// Whether chain queries fall back to an RPC endpoint
+func (c *Client) HasRPCFallback() bool {
+	return c.rpc != nil
+}
+
+// Check the fallback RPC endpoint is reachable
+func (c *Client) PingRPC(ctx context.Context) error {
+	if c.rpc == nil {
+		return fmt.Errorf("no RPC endpoint configured")
+	}
+	return c.rpc.ping(ctx)
+}
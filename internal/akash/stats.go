@@ -0,0 +1,127 @@
+package akash
+
+import (
+	"sync"
+	"time"
+)
+
+// StatsAccumulator aggregates provider statistics incrementally as results
+// arrive. It is safe for concurrent use.
+type StatsAccumulator struct {
+	mutex               sync.Mutex
+	totalProviders      int
+	healthyProviders    int
+	providersWithStatus int
+	totalActiveLeases   int
+	providersWithGPU    int
+	totalResponseTime   time.Duration
+	responseTimeCount   int
+	providersByRegion   map[string]int
+	totalHealthScore    float64
+	totalResources      ResourceSummary
+	availableResources  ResourceSummary
+}
+
+// Network-wide totals used for time-series snapshots
+type NetworkAggregate struct {
+	TotalProviders     int             `json:"total_providers"`
+	HealthyProviders   int             `json:"healthy_providers"`
+	ProvidersWithGPU   int             `json:"providers_with_gpu"`
+	TotalActiveLeases  int             `json:"total_active_leases"`
+	AverageHealthScore float64         `json:"average_health_score"`
+	TotalResources     ResourceSummary `json:"total_resources"`
+	AvailableResources ResourceSummary `json:"available_resources"`
+}
+
+func NewStatsAccumulator() *StatsAccumulator {
+	return &StatsAccumulator{
+		providersByRegion: make(map[string]int),
+	}
+}
+
+// Add a provider to the aggregate
+func (a *StatsAccumulator) Add(provider *ProviderInfo) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.totalProviders++
+	a.totalHealthScore += provider.HealthScore
+
+	// Count healthy providers
+	if provider.HealthScore > 0.5 {
+		a.healthyProviders++
+	}
+
+	// Count providers with status
+	if provider.ClusterInfo != nil {
+		a.providersWithStatus++
+		a.totalActiveLeases += provider.ClusterInfo.ActiveLeases
+		a.totalResources.add(provider.ClusterInfo.TotalResources)
+		a.availableResources.add(provider.ClusterInfo.AvailableResources)
+	}
+
+	// Accumulate response time for the average
+	if provider.StatusQueryTime > 0 {
+		a.totalResponseTime += provider.StatusQueryTime
+		a.responseTimeCount++
+	}
+
+	// Count by region
+	if region, ok := provider.Attributes["region"]; ok {
+		a.providersByRegion[region]++
+	}
+
+	// Count GPU providers
+	if _, hasGPU := provider.Attributes["capabilities/gpu/vendor/nvidia"]; hasGPU {
+		a.providersWithGPU++
+	}
+}
+
+// Finalize produces the statistics summary from everything added so far
+func (a *StatsAccumulator) Finalize() map[string]interface{} {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	averageResponseTime := time.Duration(0)
+	if a.responseTimeCount > 0 {
+		averageResponseTime = a.totalResponseTime / time.Duration(a.responseTimeCount)
+	}
+
+	providersByRegion := make(map[string]int, len(a.providersByRegion))
+	for region, count := range a.providersByRegion {
+		providersByRegion[region] = count
+	}
+
+	return map[string]interface{}{
+		"total_providers":       a.totalProviders,
+		"healthy_providers":     a.healthyProviders,
+		"providers_with_status": a.providersWithStatus,
+		"average_response_time": averageResponseTime,
+		"total_active_leases":   a.totalActiveLeases,
+		"providers_by_region":   providersByRegion,
+		"providers_with_gpu":    a.providersWithGPU,
+		"total_resources":       a.totalResources,
+		"available_resources":   a.availableResources,
+	}
+}
+
+// Aggregate produces the network-wide totals from everything added so far
+func (a *StatsAccumulator) Aggregate() NetworkAggregate {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	averageHealth := 0.0
+	if a.totalProviders > 0 {
+		averageHealth = a.totalHealthScore / float64(a.totalProviders)
+	}
+
+	return NetworkAggregate{
+		TotalProviders:     a.totalProviders,
+		HealthyProviders:   a.healthyProviders,
+		ProvidersWithGPU:   a.providersWithGPU,
+		TotalActiveLeases:  a.totalActiveLeases,
+		AverageHealthScore: averageHealth,
+		TotalResources:     a.totalResources,
+		AvailableResources: a.availableResources,
+	}
+}
@@ -0,0 +1,185 @@
+package akash
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Where the fields we use live in a provider /status response. Paths are
+// dot-separated keys with optional [index] segments, e.g. "cluster.leases.active"
+// or "nodes[0].name". A schema applies when every Detect path resolves.
+type StatusSchema struct {
+	Name           string   `yaml:"name" json:"name"`
+	Detect         []string `yaml:"detect" json:"detect"`
+	Leases         string   `yaml:"leases" json:"leases"`                   // number of active leases
+	Inventory      string   `yaml:"inventory" json:"inventory"`             // object parsed by parseInventory
+	PublicHostname string   `yaml:"public_hostname" json:"public_hostname"` // string
+	AvailableNodes string   `yaml:"available_nodes" json:"available_nodes"` // list, counted
+}
+
+// Schemas of known provider software versions, tried after any configured ones
+var builtinStatusSchemas = []StatusSchema{
+	{
+		// Provider services up to v0.4: lease count as a bare number
+		Name:           "v0.4",
+		Detect:         []string{"cluster.leases:number", "cluster.inventory"},
+		Leases:         "cluster.leases",
+		Inventory:      "cluster.inventory",
+		PublicHostname: "cluster_public_hostname",
+		AvailableNodes: "cluster.inventory.available.nodes",
+	},
+	{
+		// Provider services v0.5 and later: lease counts nested by state
+		Name:           "v0.5",
+		Detect:         []string{"cluster.leases.active", "cluster.inventory"},
+		Leases:         "cluster.leases.active",
+		Inventory:      "cluster.inventory",
+		PublicHostname: "cluster_public_hostname",
+		AvailableNodes: "cluster.inventory.available.nodes",
+	},
+}
+
+// Check that every configured schema is named and has a detection rule
+func ValidateStatusSchemas(schemas []StatusSchema) error {
+	for i, schema := range schemas {
+		if schema.Name == "" {
+			return fmt.Errorf("status schema %d has no name", i)
+		}
+		if len(schema.Detect) == 0 {
+			return fmt.Errorf("status schema %q has no detect paths", schema.Name)
+		}
+		for _, path := range append(append([]string(nil), schema.Detect...), schema.Leases, schema.Inventory, schema.PublicHostname, schema.AvailableNodes) {
+			if _, _, err := parseSchemaPath(path); err != nil {
+				return fmt.Errorf("status schema %q: %w", schema.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Pick the first configured or built-in schema that matches the response
+func (c *Client) detectStatusSchema(status map[string]interface{}) (StatusSchema, bool) {
+	for _, schemas := range [][]StatusSchema{c.config.StatusSchemas, builtinStatusSchemas} {
+		for _, schema := range schemas {
+			if schema.matches(status) {
+				return schema, true
+			}
+		}
+	}
+	return StatusSchema{}, false
+}
+
+func (s StatusSchema) matches(status map[string]interface{}) bool {
+	for _, rule := range s.Detect {
+		path, kind, _ := parseSchemaPath(rule)
+		value, ok := lookupPath(status, path)
+		if !ok || !hasKind(value, kind) {
+			return false
+		}
+	}
+	return true
+}
+
+// Split a detect rule into its path and optional required kind ("path:number")
+func parseSchemaPath(rule string) ([]string, string, error) {
+	path, kind, _ := strings.Cut(rule, ":")
+	switch kind {
+	case "", "number", "string", "object", "list":
+	default:
+		return nil, "", fmt.Errorf("path %q: unknown kind %q (valid: number, string, object, list)", rule, kind)
+	}
+	if path == "" {
+		return nil, kind, nil
+	}
+
+	var segments []string
+	for _, part := range strings.Split(path, ".") {
+		key, rest, indexed := strings.Cut(part, "[")
+		if key == "" && !indexed {
+			return nil, "", fmt.Errorf("path %q has an empty segment", rule)
+		}
+		if key != "" {
+			segments = append(segments, key)
+		}
+		for indexed {
+			var index string
+			index, rest, _ = strings.Cut(rest, "]")
+			if _, err := strconv.Atoi(index); err != nil {
+				return nil, "", fmt.Errorf("path %q has an invalid index %q", rule, index)
+			}
+			segments = append(segments, "["+index)
+			_, rest, indexed = strings.Cut(rest, "[")
+		}
+	}
+	return segments, kind, nil
+}
+
+// Resolve a parsed path in decoded JSON
+func lookupPath(value interface{}, path []string) (interface{}, bool) {
+	for _, segment := range path {
+		if index, ok := strings.CutPrefix(segment, "["); ok {
+			list, isList := value.([]interface{})
+			i, _ := strconv.Atoi(index)
+			if !isList || i < 0 || i >= len(list) {
+				return nil, false
+			}
+			value = list[i]
+			continue
+		}
+
+		object, isObject := value.(map[string]interface{})
+		if !isObject {
+			return nil, false
+		}
+		if value, isObject = object[segment]; !isObject {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func hasKind(value interface{}, kind string) bool {
+	switch kind {
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "list":
+		_, ok := value.([]interface{})
+		return ok
+	}
+	return value != nil
+}
+
+// Extract the cluster status fields a schema maps
+func (s StatusSchema) extract(status map[string]interface{}) *ClusterStatus {
+	field := func(rule string) interface{} {
+		path, _, _ := parseSchemaPath(rule)
+		if path == nil {
+			return nil
+		}
+		value, _ := lookupPath(status, path)
+		return value
+	}
+
+	clusterInfo := &ClusterStatus{Schema: s.Name}
+	if leases, ok := field(s.Leases).(float64); ok {
+		clusterInfo.ActiveLeases = int(leases)
+	}
+	if inventory, ok := field(s.Inventory).(map[string]interface{}); ok {
+		clusterInfo.Inventory = inventory
+	}
+	if hostname, ok := field(s.PublicHostname).(string); ok {
+		clusterInfo.PublicHostname = hostname
+	}
+	if nodes, ok := field(s.AvailableNodes).([]interface{}); ok {
+		clusterInfo.AvailableNodes = len(nodes)
+	}
+	return clusterInfo
+}
@@ -0,0 +1,77 @@
+package intelligence
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Treatment of providers that don't advertise a CPU architecture when one is required
+const (
+	UnknownArchAssumeX86 = "assume_x86"
+	UnknownArchExclude   = "exclude"
+)
+
+func ValidateUnknownArchPolicy(policy string) error {
+	switch policy {
+	case "", UnknownArchAssumeX86, UnknownArchExclude:
+		return nil
+	}
+	return fmt.Errorf("unknown architecture policy %q (valid: %s, %s)", policy, UnknownArchAssumeX86, UnknownArchExclude)
+}
+
+// Architectures a provider runs, and whether they were assumed rather than advertised.
+// Returns nil if the provider advertises none and the policy excludes it.
+func (s *Service) providerArchitectures(provider *akash.ProviderInfo) ([]string, bool) {
+	if len(provider.CPUArchitectures) > 0 {
+		return provider.CPUArchitectures, false
+	}
+	if s.config.UnknownArchPolicy == UnknownArchExclude {
+		return nil, false
+	}
+	return []string{"amd64"}, true
+}
+
+// Drop providers that cannot run images built for the required architecture
+func (s *Service) filterByArchitecture(providers []*akash.ProviderInfo, arch string) ([]*akash.ProviderInfo, []FilteredProvider) {
+	if arch == "" {
+		return providers, nil
+	}
+
+	kept := make([]*akash.ProviderInfo, 0, len(providers))
+	var filtered []FilteredProvider
+	for _, provider := range providers {
+		archs, assumed := s.providerArchitectures(provider)
+		switch {
+		case archs == nil:
+			filtered = append(filtered, FilteredProvider{
+				Address: provider.Address,
+				Reason:  "CPU architecture not advertised",
+			})
+		case !slices.Contains(archs, arch):
+			reason := fmt.Sprintf("%s CPU, %s required", strings.Join(archs, "/"), arch)
+			if assumed {
+				reason = fmt.Sprintf("CPU architecture not advertised (assumed amd64), %s required", arch)
+			}
+			filtered = append(filtered, FilteredProvider{
+				Address: provider.Address,
+				Reason:  reason,
+			})
+		default:
+			kept = append(kept, provider)
+		}
+	}
+
+	return kept, filtered
+}
+
+// Describe a provider's CPU architecture for reasoning
+func (s *Service) describeArchitecture(provider *akash.ProviderInfo) string {
+	archs, assumed := s.providerArchitectures(provider)
+	if assumed {
+		return "CPU architecture amd64 (assumed, not advertised)"
+	}
+	return fmt.Sprintf("CPU architecture %s (advertised)", strings.Join(archs, "/"))
+}
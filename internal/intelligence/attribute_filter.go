@@ -0,0 +1,56 @@
+package intelligence
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Check that every attribute denylist entry is a valid glob pattern
+func ValidateAttributeDenylist(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid attribute denylist pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Provider as seen by scoring: when an attribute denylist is configured, a copy
+// whose attributes exclude the denied keys, plus the keys that were dropped.
+// The original provider, with its raw attributes, is left untouched for display.
+func (s *Service) scoringView(provider *akash.ProviderInfo) (*akash.ProviderInfo, []string) {
+	if len(s.config.AttributeDenylist) == 0 || len(provider.Attributes) == 0 {
+		return provider, nil
+	}
+
+	var ignored []string
+	attributes := make(map[string]string, len(provider.Attributes))
+	for key, value := range provider.Attributes {
+		if s.attributeDenied(key) {
+			ignored = append(ignored, key)
+			continue
+		}
+		attributes[key] = value
+	}
+	if len(ignored) == 0 {
+		return provider, nil
+	}
+	sort.Strings(ignored)
+
+	filtered := *provider
+	filtered.Attributes = attributes
+	return &filtered, ignored
+}
+
+// Check whether an attribute key matches the denylist
+func (s *Service) attributeDenied(key string) bool {
+	for _, pattern := range s.config.AttributeDenylist {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package intelligence
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Temporarily banned provider
+type BanEntry struct {
+	Address   string    `json:"address"`
+	Reason    string    `json:"reason,omitempty"`
+	BannedAt  time.Time `json:"banned_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Providers excluded from selection, either permanently (from config) or until a ban expires
+type Blacklist struct {
+	permanent map[string]bool
+	temporary map[string]*BanEntry
+	mutex     sync.RWMutex
+}
+
+func NewBlacklist(permanent []string) *Blacklist {
+	blacklist := &Blacklist{
+		permanent: make(map[string]bool),
+		temporary: make(map[string]*BanEntry),
+	}
+	for _, addr := range permanent {
+		blacklist.permanent[addr] = true
+	}
+	return blacklist
+}
+
+// Ban a provider until the TTL passes; banning an already banned provider replaces its expiry
+func (b *Blacklist) Ban(address string, ttl time.Duration, reason string) (*BanEntry, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("ban ttl must be positive")
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.permanent[address] {
+		return nil, fmt.Errorf("provider %s is permanently blacklisted", address)
+	}
+
+	now := time.Now()
+	entry := &BanEntry{
+		Address:   address,
+		Reason:    reason,
+		BannedAt:  now,
+		ExpiresAt: now.Add(ttl),
+	}
+	b.temporary[address] = entry
+	return entry, nil
+}
+
+// Lift a temporary ban
+func (b *Blacklist) Unban(address string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.permanent[address] {
+		return fmt.Errorf("provider %s is permanently blacklisted in config", address)
+	}
+	if _, ok := b.temporary[address]; !ok {
+		return fmt.Errorf("provider %s is not banned", address)
+	}
+	delete(b.temporary, address)
+	return nil
+}
+
+// Check whether a provider is currently excluded
+func (b *Blacklist) IsBanned(address string) bool {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if b.permanent[address] {
+		return true
+	}
+	entry, ok := b.temporary[address]
+	return ok && time.Now().Before(entry.ExpiresAt)
+}
+
+// Drop expired temporary bans so the providers are reconsidered
+func (b *Blacklist) PruneExpired() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	removed := 0
+	for addr, entry := range b.temporary {
+		if !now.Before(entry.ExpiresAt) {
+			delete(b.temporary, addr)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Get permanent entries and active temporary bans, soonest expiry first
+func (b *Blacklist) Active() ([]string, []BanEntry) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	permanent := make([]string, 0, len(b.permanent))
+	for addr := range b.permanent {
+		permanent = append(permanent, addr)
+	}
+	sort.Strings(permanent)
+
+	now := time.Now()
+	temporary := make([]BanEntry, 0, len(b.temporary))
+	for _, entry := range b.temporary {
+		if now.Before(entry.ExpiresAt) {
+			temporary = append(temporary, *entry)
+		}
+	}
+	sort.Slice(temporary, func(i, j int) bool {
+		return temporary[i].ExpiresAt.Before(temporary[j].ExpiresAt)
+	})
+
+	return permanent, temporary
+}
@@ -0,0 +1,43 @@
+package intelligence
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Drop every cached provider, GPU reading and selection, so the next request
+// fetches fresh data. Returns how many providers were cached.
+func (s *Service) FlushCache() int {
+	s.cache.mutex.Lock()
+	flushed := len(s.cache.data)
+	s.cache.data = make(map[string]*CachedProvider)
+	s.cache.mutex.Unlock()
+
+	s.gpuCache.clear()
+	if s.recommendations != nil {
+		s.recommendations.clear()
+	}
+	slog.Info("Provider cache flushed", "providers", flushed)
+	return flushed
+}
+
+// Drop one provider's cached data. Selections that used it are invalidated
+// on their next read, as its cached data no longer matches. Reports whether
+// the provider was cached.
+func (s *Service) EvictProvider(address string) bool {
+	s.cache.mutex.Lock()
+	_, cached := s.cache.data[address]
+	delete(s.cache.data, address)
+	s.cache.mutex.Unlock()
+
+	s.gpuCache.remove(address)
+	if cached {
+		slog.Info("Provider evicted from cache", "provider", address)
+	}
+	return cached
+}
+
+// Refetch providers now, replacing their cached data whether or not it expired
+func (s *Service) RefreshProviders(ctx context.Context, addresses []string) (*IntelligenceResult, error) {
+	return s.GetProviderIntelligenceWithOptions(ctx, addresses, FetchOptions{Refresh: true})
+}
@@ -0,0 +1,110 @@
+package intelligence
+
+import (
+	"container/heap"
+	"sort"
+	"time"
+)
+
+// Upper edges of the time-until-expiry histogram buckets; the last bucket is open ended
+var expiryBucketEdges = []time.Duration{
+	30 * time.Second,
+	time.Minute,
+	2 * time.Minute,
+	5 * time.Minute,
+	10 * time.Minute,
+}
+
+// Count of cache entries expiring within a range
+type ExpiryBucket struct {
+	Within string `json:"within"` // "expired", an upper edge such as "1m0s", or "later"
+	Count  int    `json:"count"`
+}
+
+// Cache entry nearing expiry
+type ExpiringEntry struct {
+	Address   string        `json:"address"`
+	ExpiresAt time.Time     `json:"expires_at"`
+	ExpiresIn time.Duration `json:"expires_in"`
+}
+
+// Distribution of remaining cache TTLs and the entries that will expire first
+type CacheExpiryReport struct {
+	Entries   int             `json:"entries"`
+	TTL       string          `json:"ttl"`
+	Histogram []ExpiryBucket  `json:"histogram"`
+	Soonest   []ExpiringEntry `json:"soonest"`
+}
+
+// Max-heap on expiry, used to keep the N soonest entries without sorting the whole cache
+type expiryHeap []ExpiringEntry
+
+func (h expiryHeap) Len() int            { return len(h) }
+func (h expiryHeap) Less(i, j int) bool  { return h[i].ExpiresAt.After(h[j].ExpiresAt) }
+func (h expiryHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *expiryHeap) Push(x interface{}) { *h = append(*h, x.(ExpiringEntry)) }
+func (h *expiryHeap) Pop() interface{} {
+	old := *h
+	entry := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return entry
+}
+
+// Report cache churn: a histogram of time until expiry and the limit soonest-to-expire
+// entries. This is a single pass under the read lock, O(n log limit), intended for
+// monitoring rather than the request path.
+func (s *Service) GetCacheExpiry(limit int) *CacheExpiryReport {
+	counts := make([]int, len(expiryBucketEdges)+2) // expired, edges..., later
+	soonest := make(expiryHeap, 0, limit)
+	now := time.Now()
+
+	s.cache.mutex.RLock()
+	entries := len(s.cache.data)
+	for addr, cached := range s.cache.data {
+		remaining := cached.ExpiresAt.Sub(now)
+
+		bucket := len(counts) - 1
+		if remaining <= 0 {
+			bucket = 0
+		} else {
+			for i, edge := range expiryBucketEdges {
+				if remaining <= edge {
+					bucket = i + 1
+					break
+				}
+			}
+		}
+		counts[bucket]++
+
+		if limit <= 0 {
+			continue
+		}
+		entry := ExpiringEntry{Address: addr, ExpiresAt: cached.ExpiresAt, ExpiresIn: remaining}
+		if soonest.Len() < limit {
+			heap.Push(&soonest, entry)
+		} else if entry.ExpiresAt.Before(soonest[0].ExpiresAt) {
+			soonest[0] = entry
+			heap.Fix(&soonest, 0)
+		}
+	}
+	s.cache.mutex.RUnlock()
+
+	report := &CacheExpiryReport{
+		Entries:   entries,
+		TTL:       s.cacheTTL().String(),
+		Histogram: make([]ExpiryBucket, 0, len(counts)),
+		Soonest:   []ExpiringEntry(soonest),
+	}
+
+	report.Histogram = append(report.Histogram, ExpiryBucket{Within: "expired", Count: counts[0]})
+	for i, edge := range expiryBucketEdges {
+		report.Histogram = append(report.Histogram, ExpiryBucket{Within: edge.String(), Count: counts[i+1]})
+	}
+	report.Histogram = append(report.Histogram, ExpiryBucket{Within: "later", Count: counts[len(counts)-1]})
+
+	sort.Slice(report.Soonest, func(i, j int) bool {
+		return report.Soonest[i].ExpiresAt.Before(report.Soonest[j].ExpiresAt)
+	})
+
+	return report
+}
@@ -0,0 +1,25 @@
+package intelligence
+
+import (
+	"sort"
+	"time"
+)
+
+// Unexpired cache entries, by address
+func (s *Service) CachedProviders() []*CachedProvider {
+	now := time.Now()
+
+	s.cache.mutex.RLock()
+	entries := make([]*CachedProvider, 0, len(s.cache.data))
+	for _, cached := range s.cache.data {
+		if cached.Info != nil && now.Before(cached.ExpiresAt) {
+			entries = append(entries, cached)
+		}
+	}
+	s.cache.mutex.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Info.Address < entries[j].Info.Address
+	})
+	return entries
+}
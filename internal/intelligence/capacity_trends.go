@@ -0,0 +1,158 @@
+package intelligence
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+const (
+	// Share of the window averaged at each end, so one noisy sample doesn't set the delta
+	capacityEdgeFraction = 0.05
+
+	// Utilization changes below this many percentage points count as stable
+	stableUtilizationPoints = 1.0
+)
+
+// Change of one resource quantity between the start and end of a window
+type CapacityDelta struct {
+	Start         float64  `json:"start"`
+	End           float64  `json:"end"`
+	Change        float64  `json:"change"`
+	ChangePercent *float64 `json:"change_percent,omitempty"` // nil when the start was zero
+}
+
+// Change of one resource's utilization (share of total capacity in use)
+type UtilizationTrend struct {
+	Start        float64 `json:"start"`
+	End          float64 `json:"end"`
+	ChangePoints float64 `json:"change_points"` // percentage points
+	Direction    string  `json:"direction"`     // rising, falling or stable
+}
+
+// Per-resource capacity deltas over a window
+type ResourceCapacityTrend struct {
+	Total       CapacityDelta    `json:"total"`
+	Available   CapacityDelta    `json:"available"`
+	Utilization UtilizationTrend `json:"utilization"`
+}
+
+// How network capacity changed over a window of the network stats series
+type CapacityTrends struct {
+	Window   time.Duration `json:"window"`
+	Since    time.Time     `json:"since"`
+	Until    time.Time     `json:"until"`
+	Samples  int           `json:"samples"`
+	Coverage time.Duration `json:"coverage"` // time between the first and last sample
+
+	// False when history doesn't reach back far enough; deltas then cover only Coverage
+	Sufficient bool   `json:"sufficient"`
+	Note       string `json:"note,omitempty"`
+
+	Providers CapacityDelta                    `json:"providers"`
+	Resources map[string]ResourceCapacityTrend `json:"resources,omitempty"` // cpu, memory, storage, gpu
+}
+
+// Compare network capacity at the start and end of the window ending now
+func (s *Service) GetCapacityTrends(window time.Duration) (*CapacityTrends, error) {
+	if s.history == nil {
+		return nil, fmt.Errorf("historical store is disabled")
+	}
+	if s.config.NetworkStatsInterval <= 0 {
+		return nil, fmt.Errorf("network stats sampling is disabled")
+	}
+	if window <= 0 {
+		return nil, fmt.Errorf("window must be positive")
+	}
+
+	until := time.Now()
+	since := until.Add(-window)
+	samples := s.history.NetworkSamples(since, until)
+
+	trends := &CapacityTrends{
+		Window:  window,
+		Since:   since,
+		Until:   until,
+		Samples: len(samples),
+	}
+	if len(samples) < 2 {
+		trends.Note = fmt.Sprintf("%d network stats samples in the last %v; at least 2 are needed", len(samples), window)
+		return trends, nil
+	}
+
+	first, last := samples[0], samples[len(samples)-1]
+	trends.Coverage = last.ObservedAt.Sub(first.ObservedAt)
+
+	// History must start within a couple of sampling intervals of the window start
+	slack := max(2*s.config.NetworkStatsInterval, time.Duration(float64(window)*capacityEdgeFraction))
+	trends.Sufficient = first.ObservedAt.Sub(since) <= slack
+	if !trends.Sufficient {
+		trends.Note = fmt.Sprintf("history covers only %v of the requested %v; deltas are over the covered span",
+			trends.Coverage.Round(time.Minute), window)
+	}
+
+	// Average the samples near each end of the covered span
+	edge := max(time.Duration(float64(trends.Coverage)*capacityEdgeFraction), time.Nanosecond)
+	var head, tail []NetworkStatsSample
+	for _, sample := range samples {
+		if sample.ObservedAt.Sub(first.ObservedAt) < edge {
+			head = append(head, sample)
+		}
+		if last.ObservedAt.Sub(sample.ObservedAt) < edge {
+			tail = append(tail, sample)
+		}
+	}
+	start := averageNetworkStats(head, first.ObservedAt).NetworkAggregate
+	end := averageNetworkStats(tail, last.ObservedAt).NetworkAggregate
+
+	trends.Providers = capacityDelta(float64(start.TotalProviders), float64(end.TotalProviders))
+	trends.Resources = make(map[string]ResourceCapacityTrend)
+	for name, quantity := range map[string]func(akash.ResourceSummary) float64{
+		"cpu":     func(r akash.ResourceSummary) float64 { return float64(r.CPU) },
+		"memory":  func(r akash.ResourceSummary) float64 { return float64(r.Memory) },
+		"storage": func(r akash.ResourceSummary) float64 { return float64(r.Storage) },
+		"gpu":     func(r akash.ResourceSummary) float64 { return float64(r.GPU) },
+	} {
+		trends.Resources[name] = ResourceCapacityTrend{
+			Total:     capacityDelta(quantity(start.TotalResources), quantity(end.TotalResources)),
+			Available: capacityDelta(quantity(start.AvailableResources), quantity(end.AvailableResources)),
+			Utilization: utilizationTrend(
+				utilization(quantity(start.TotalResources), quantity(start.AvailableResources)),
+				utilization(quantity(end.TotalResources), quantity(end.AvailableResources)),
+			),
+		}
+	}
+
+	return trends, nil
+}
+
+func capacityDelta(start, end float64) CapacityDelta {
+	delta := CapacityDelta{Start: start, End: end, Change: end - start}
+	if start != 0 {
+		percent := math.Round((end-start)/start*10000) / 100
+		delta.ChangePercent = &percent
+	}
+	return delta
+}
+
+// Share of capacity in use, 0 without capacity
+func utilization(total, available float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return math.Max(0, 1-available/total)
+}
+
+func utilizationTrend(start, end float64) UtilizationTrend {
+	points := math.Round((end-start)*10000) / 100
+	trend := UtilizationTrend{Start: start, End: end, ChangePoints: points, Direction: "stable"}
+	switch {
+	case points >= stableUtilizationPoints:
+		trend.Direction = "rising"
+	case points <= -stableUtilizationPoints:
+		trend.Direction = "falling"
+	}
+	return trend
+}
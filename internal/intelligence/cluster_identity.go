@@ -0,0 +1,51 @@
+package intelligence
+
+import (
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Attach the cluster identity to a freshly fetched provider. The fingerprint
+// comes from the public hostname the cluster reports, which does not depend on
+// the address or host URI it was registered under. When this observation has no
+// status data, the hostname last reported is reused so the fingerprint doesn't
+// flip while a provider is briefly unreachable.
+func (s *Service) identifyCluster(info *akash.ProviderInfo) {
+	hostname, source := "", ""
+	switch {
+	case info.ClusterInfo != nil && info.ClusterInfo.PublicHostname != "":
+		hostname, source = info.ClusterInfo.PublicHostname, akash.IdentityPublicHostname
+	case s.rememberedPublicHostname(info) != "":
+		hostname, source = s.rememberedPublicHostname(info), akash.IdentityRememberedHostname
+	case akash.HostURIHostname(info.HostURI) != "":
+		hostname, source = akash.HostURIHostname(info.HostURI), akash.IdentityHostURI
+	default:
+		return
+	}
+
+	info.ClusterIdentity = &akash.ClusterIdentity{
+		Fingerprint: akash.ClusterFingerprint(hostname),
+		Hostname:    akash.NormalizeHostname(hostname),
+		Source:      source,
+		ResolvedIPs: info.ResolvedIPs,
+	}
+}
+
+// Public hostname from the provider's latest cached or recorded observation
+func (s *Service) rememberedPublicHostname(info *akash.ProviderInfo) string {
+	s.cache.mutex.RLock()
+	cached, ok := s.cache.data[info.Address]
+	s.cache.mutex.RUnlock()
+	if ok && cached.Info != nil && cached.Info.ClusterIdentity != nil && cached.Info.ClusterIdentity.Source != akash.IdentityHostURI {
+		return cached.Info.ClusterIdentity.Hostname
+	}
+
+	if s.history == nil {
+		return ""
+	}
+	if snapshot, ok := s.history.SnapshotAt(info.Address, time.Now()); ok && snapshot.Info.ClusterInfo != nil {
+		return snapshot.Info.ClusterInfo.PublicHostname
+	}
+	return ""
+}
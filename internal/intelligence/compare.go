@@ -0,0 +1,159 @@
+package intelligence
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Column of a comparison matrix
+type ComparisonColumn struct {
+	Name           string   `json:"name"`
+	Kind           string   `json:"kind"` // "metric" for raw values, "score" for weighted sub-scores
+	HigherIsBetter bool     `json:"higher_is_better"`
+	Best           []string `json:"best"` // addresses holding the best value, ties included
+}
+
+// Row of a comparison matrix. Values are keyed by column name; metrics that
+// could not be measured for a provider are null.
+type ComparisonRow struct {
+	Address string              `json:"address"`
+	Rank    int                 `json:"rank"`
+	Values  map[string]*float64 `json:"values"`
+}
+
+// Providers × metrics matrix for evaluating a shortlist
+type ComparisonMatrix struct {
+	Columns         []ComparisonColumn      `json:"columns"`
+	Rows            []ComparisonRow         `json:"rows"`
+	Winner          string                  `json:"winner"`
+	Criteria        SelectionCriteria       `json:"criteria"`
+	FailedProviders []*akash.FailedProvider `json:"failed_providers"`
+
+	Degraded                bool                    `json:"degraded"`
+	UnavailableCapabilities []UnavailableCapability `json:"unavailable_capabilities,omitempty"`
+}
+
+// Raw metric extracted from provider data
+type comparisonMetric struct {
+	name           string
+	higherIsBetter bool
+	value          func(*akash.ProviderInfo) (float64, bool)
+}
+
+var comparisonMetrics = []comparisonMetric{
+	{"health_score", true, func(p *akash.ProviderInfo) (float64, bool) {
+		return p.HealthScore, true
+	}},
+	{"status_latency_ms", false, func(p *akash.ProviderInfo) (float64, bool) {
+		return float64(p.StatusQueryTime.Milliseconds()), p.StatusQueryTime > 0 && p.Error == ""
+	}},
+	{"blockchain_latency_ms", false, func(p *akash.ProviderInfo) (float64, bool) {
+		return float64(p.BlockchainQueryTime.Milliseconds()), p.BlockchainQueryTime > 0
+	}},
+	{"active_leases", true, func(p *akash.ProviderInfo) (float64, bool) {
+		if p.ClusterInfo == nil {
+			return 0, false
+		}
+		return float64(p.ClusterInfo.ActiveLeases), true
+	}},
+	{"available_nodes", true, func(p *akash.ProviderInfo) (float64, bool) {
+		if p.ClusterInfo == nil {
+			return 0, false
+		}
+		return float64(p.ClusterInfo.AvailableNodes), true
+	}},
+	{"available_cpu", true, func(p *akash.ProviderInfo) (float64, bool) {
+		if p.ClusterInfo == nil {
+			return 0, false
+		}
+		return float64(p.ClusterInfo.AvailableResources.CPU), true
+	}},
+	{"available_memory", true, func(p *akash.ProviderInfo) (float64, bool) {
+		if p.ClusterInfo == nil {
+			return 0, false
+		}
+		return float64(p.ClusterInfo.AvailableResources.Memory), true
+	}},
+	{"available_gpu", true, func(p *akash.ProviderInfo) (float64, bool) {
+		if p.ClusterInfo == nil {
+			return 0, false
+		}
+		return float64(p.ClusterInfo.AvailableResources.GPU), true
+	}},
+}
+
+// Build a comparison matrix of raw metrics and weighted sub-scores for the given providers
+func (s *Service) CompareProviders(ctx context.Context, addresses []string, criteria SelectionCriteria) (*ComparisonMatrix, error) {
+	ctx, degraded := withDegradation(ctx)
+
+	intel, err := s.GetProviderIntelligence(ctx, addresses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider intelligence: %w", err)
+	}
+	if len(intel.Providers) == 0 {
+		return nil, fmt.Errorf("no provider data available (%d providers failed)", len(intel.FailedProviders))
+	}
+
+	scored := s.scoreProviders(ctx, intel.Providers, criteria)
+
+	matrix := &ComparisonMatrix{
+		Winner:          scored[0].Provider.Address,
+		Criteria:        criteria,
+		FailedProviders: intel.FailedProviders,
+	}
+
+	for _, metric := range comparisonMetrics {
+		matrix.Columns = append(matrix.Columns, ComparisonColumn{Name: metric.name, Kind: "metric", HigherIsBetter: metric.higherIsBetter})
+	}
+	for _, contribution := range s.dimensionContributions(scored[0].Breakdown, criteria) {
+		matrix.Columns = append(matrix.Columns, ComparisonColumn{Name: "score:" + contribution.Name, Kind: "score", HigherIsBetter: true})
+	}
+	matrix.Columns = append(matrix.Columns, ComparisonColumn{Name: "score:total", Kind: "score", HigherIsBetter: true})
+
+	for i, candidate := range scored {
+		row := ComparisonRow{
+			Address: candidate.Provider.Address,
+			Rank:    i + 1,
+			Values:  make(map[string]*float64, len(matrix.Columns)),
+		}
+		for _, metric := range comparisonMetrics {
+			if value, ok := metric.value(candidate.Provider); ok {
+				row.Values[metric.name] = &value
+			} else {
+				row.Values[metric.name] = nil
+			}
+		}
+		for _, contribution := range s.dimensionContributions(candidate.Breakdown, criteria) {
+			value := contribution.Contribution
+			row.Values["score:"+contribution.Name] = &value
+		}
+		total := candidate.Score
+		row.Values["score:total"] = &total
+
+		matrix.Rows = append(matrix.Rows, row)
+	}
+
+	// Mark the best provider(s) in each column
+	for c := range matrix.Columns {
+		column := &matrix.Columns[c]
+		var best *float64
+		for _, row := range matrix.Rows {
+			value := row.Values[column.Name]
+			if value == nil {
+				continue
+			}
+			switch {
+			case best == nil || (column.HigherIsBetter && *value > *best) || (!column.HigherIsBetter && *value < *best):
+				best = value
+				column.Best = []string{row.Address}
+			case *value == *best:
+				column.Best = append(column.Best, row.Address)
+			}
+		}
+	}
+
+	matrix.Degraded, matrix.UnavailableCapabilities = degraded.report()
+	return matrix, nil
+}
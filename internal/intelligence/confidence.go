@@ -0,0 +1,70 @@
+package intelligence
+
+import (
+	"math"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+const (
+	// Lead over the runner-up at which the score gap alone gives full confidence
+	decisiveScoreGap = 0.2
+
+	// Providers scoring within this margin of the winner count as near ties
+	nearTieMargin = 0.02
+)
+
+// How much to trust a selection
+type SelectionConfidence struct {
+	Score       float64 `json:"score"` // 0..1
+	Level       string  `json:"level"` // high, medium or low
+	ScoreGap    float64 `json:"score_gap"`
+	Candidates  int     `json:"candidates"`
+	NearTies    int     `json:"near_ties"`
+	MissingData int     `json:"missing_data"`
+}
+
+// Estimate confidence in the winner from its lead over the runner-up, how many
+// other providers are nearly tied with it, and how much candidate data is missing.
+// A provider with a status error only has partial data; a failed provider has none.
+func calculateConfidence(scored []ScoredProvider, failed []*akash.FailedProvider) *SelectionConfidence {
+	confidence := &SelectionConfidence{Candidates: len(scored)}
+	if len(scored) == 0 {
+		return confidence
+	}
+
+	// A lone candidate wins by default, which says little about its quality
+	gapFactor := 0.5
+	if len(scored) > 1 {
+		confidence.ScoreGap = scored[0].Score - scored[1].Score
+		gapFactor = math.Min(confidence.ScoreGap/decisiveScoreGap, 1)
+
+		for _, other := range scored[2:] {
+			if scored[0].Score-other.Score <= nearTieMargin {
+				confidence.NearTies++
+			}
+		}
+	}
+
+	complete := 0
+	for _, candidate := range scored {
+		if candidate.Provider.Error == "" && candidate.Provider.ClusterInfo != nil {
+			complete++
+		}
+	}
+	confidence.MissingData = len(scored) - complete + len(failed)
+	completeness := float64(complete) / float64(len(scored)+len(failed))
+
+	confidence.Score = gapFactor * (0.5 + 0.5*completeness) * math.Pow(0.9, float64(confidence.NearTies))
+
+	switch {
+	case confidence.Score >= 0.7:
+		confidence.Level = "high"
+	case confidence.Score >= 0.4:
+		confidence.Level = "medium"
+	default:
+		confidence.Level = "low"
+	}
+
+	return confidence
+}
@@ -0,0 +1,69 @@
+package intelligence
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Capability that was unavailable while computing a response
+type UnavailableCapability struct {
+	Capability string `json:"capability"`
+	Reason     string `json:"reason"`
+}
+
+// Collects the capabilities that were unavailable while computing a single response
+type degradation struct {
+	mutex sync.Mutex
+	items map[string]string
+}
+
+type degradationKey struct{}
+
+// Attach a degradation collector to the context unless one is already present,
+// so nested service calls report into the outermost response
+func withDegradation(ctx context.Context) (context.Context, *degradation) {
+	if existing, ok := ctx.Value(degradationKey{}).(*degradation); ok {
+		return ctx, existing
+	}
+	collector := &degradation{items: make(map[string]string)}
+	return context.WithValue(ctx, degradationKey{}, collector), collector
+}
+
+// Record that a capability was unavailable for the response being computed
+func reportDegraded(ctx context.Context, capability, reason string) {
+	collector, ok := ctx.Value(degradationKey{}).(*degradation)
+	if !ok {
+		return
+	}
+
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	if _, exists := collector.items[capability]; !exists {
+		collector.items[capability] = reason
+	}
+}
+
+// Get whether the response is degraded and which capabilities were unavailable
+func (d *degradation) report() (bool, []UnavailableCapability) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if len(d.items) == 0 {
+		return false, nil
+	}
+
+	capabilities := make([]UnavailableCapability, 0, len(d.items))
+	for capability, reason := range d.items {
+		capabilities = append(capabilities, UnavailableCapability{
+			Capability: capability,
+			Reason:     reason,
+		})
+	}
+	sort.Slice(capabilities, func(i, j int) bool {
+		return capabilities[i].Capability < capabilities[j].Capability
+	})
+
+	return true, capabilities
+}
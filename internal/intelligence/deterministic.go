@@ -0,0 +1,105 @@
+package intelligence
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Failure category for pinned providers without an observation at the requested time
+const FailureNoHistory = "no_history"
+
+// Select a provider from pinned snapshots without touching the network, the
+// cache or any other mutable state, so identical inputs always produce
+// identical output. Frozen in this mode:
+//   - provider data, latencies and health scores come from the snapshots as supplied
+//   - health smoothing and history are not used (stability scores neutral,
+//     provisioning is estimated from the supplied latency alone)
+//   - maintenance windows are evaluated at criteria.EvaluatedAt, defaulting to
+//     the latest last_seen among the snapshots
+//   - the blacklist is not applied; the pinned set is taken as is
+//   - ties are broken by address, and query time and freshness are omitted
+//
+// Custom scoring plugins still run and must be deterministic themselves.
+func (s *Service) SelectFromSnapshots(ctx context.Context, snapshots []*akash.ProviderInfo, criteria SelectionCriteria) (*ProviderSelection, error) {
+	return s.selectPinned(ctx, snapshots, nil, criteria)
+}
+
+// Select from pinned snapshots, reporting providers without data as failed
+func (s *Service) selectPinned(ctx context.Context, snapshots []*akash.ProviderInfo, failed []*akash.FailedProvider, criteria SelectionCriteria) (*ProviderSelection, error) {
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no provider snapshots supplied")
+	}
+
+	ctx, degraded := withDegradation(ctx)
+
+	// Work on copies, in address order, so callers' snapshots are never modified
+	seen := make(map[string]bool, len(snapshots))
+	providers := make([]*akash.ProviderInfo, 0, len(snapshots))
+	var evaluatedAt time.Time
+	for _, snapshot := range snapshots {
+		if snapshot == nil || snapshot.Address == "" {
+			return nil, fmt.Errorf("every snapshot needs a provider address")
+		}
+		if seen[snapshot.Address] {
+			return nil, fmt.Errorf("duplicate snapshot for provider %s", snapshot.Address)
+		}
+		seen[snapshot.Address] = true
+
+		provider := *snapshot
+		provider.SmoothedHealthScore = nil
+		providers = append(providers, &provider)
+
+		if provider.LastSeen.After(evaluatedAt) {
+			evaluatedAt = provider.LastSeen
+		}
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].Address < providers[j].Address
+	})
+
+	criteria.Deterministic = true
+	if criteria.EvaluatedAt == nil {
+		criteria.EvaluatedAt = &evaluatedAt
+	}
+
+	selection, err := s.selectFrom(ctx, providers, failed, criteria)
+	if err != nil {
+		return nil, err
+	}
+	selection.Degraded, selection.UnavailableCapabilities = degraded.report()
+	return selection, nil
+}
+
+// Select deterministically from the historical store: each provider's latest
+// observation at or before the given time is pinned and scored as in SelectFromSnapshots
+func (s *Service) SelectAsOf(ctx context.Context, addresses []string, criteria SelectionCriteria, at time.Time) (*ProviderSelection, error) {
+	if s.history == nil {
+		return nil, fmt.Errorf("historical store is disabled")
+	}
+
+	var snapshots []*akash.ProviderInfo
+	var missing []*akash.FailedProvider
+	for _, addr := range addresses {
+		snapshot, ok := s.history.SnapshotAt(addr, at)
+		if !ok {
+			missing = append(missing, &akash.FailedProvider{
+				Address:  addr,
+				Error:    fmt.Sprintf("no observation at or before %s", at.Format(time.RFC3339)),
+				Category: FailureNoHistory,
+				FailedAt: at,
+			})
+			continue
+		}
+		snapshots = append(snapshots, snapshot.Info)
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no historical data for any candidate at %s", at.Format(time.RFC3339))
+	}
+
+	criteria.EvaluatedAt = &at
+	return s.selectPinned(ctx, snapshots, missing, criteria)
+}
@@ -0,0 +1,63 @@
+package intelligence
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Full scoring detail for a candidate set, including where every weight came from
+type ScoringExplanation struct {
+	Weights         map[string]WeightProvenance `json:"weights"`
+	GeoAdjustment   *GeoAdjustment              `json:"geo_adjustment,omitempty"`
+	Providers       []ScoredProvider            `json:"providers"`
+	FailedProviders []*akash.FailedProvider     `json:"failed_providers"`
+	Sensitivity     *SensitivityAnalysis        `json:"sensitivity,omitempty"`
+
+	Degraded                bool                    `json:"degraded"`
+	UnavailableCapabilities []UnavailableCapability `json:"unavailable_capabilities,omitempty"`
+}
+
+// Optional extras for ExplainScoring
+type ExplainOptions struct {
+	// Report how sensitive the winner is to each weight, perturbing by
+	// SensitivityStep (default 0.05)
+	Sensitivity     bool
+	SensitivityStep float64
+}
+
+// Explain how each candidate is scored under the given criteria
+func (s *Service) ExplainScoring(ctx context.Context, addresses []string, criteria SelectionCriteria, opts ExplainOptions) (*ScoringExplanation, error) {
+	ctx, degraded := withDegradation(ctx)
+
+	intel, err := s.GetProviderIntelligence(ctx, addresses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider intelligence: %w", err)
+	}
+
+	criteria = s.applyGeoCoverage(intel.Providers, criteria)
+	explanation := &ScoringExplanation{
+		Weights:         make(map[string]WeightProvenance),
+		Providers:       s.scoreProviders(ctx, intel.Providers, criteria),
+		FailedProviders: intel.FailedProviders,
+		GeoAdjustment:   criteria.GeoAdjustment,
+	}
+
+	for name, weight := range criteria.WeightProvenance {
+		explanation.Weights[name] = weight
+	}
+	for _, configured := range s.config.CustomScorers {
+		explanation.Weights["plugin:"+configured.Name] = WeightProvenance{
+			Value:  configured.Weight,
+			Source: WeightSourceConfig,
+		}
+	}
+
+	if opts.Sensitivity {
+		explanation.Sensitivity = s.analyzeSensitivity(ctx, explanation.Providers, criteria, opts.SensitivityStep)
+	}
+
+	explanation.Degraded, explanation.UnavailableCapabilities = degraded.report()
+	return explanation, nil
+}
@@ -0,0 +1,209 @@
+package intelligence
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Kinds of synthetic failure
+const (
+	FaultTimeout = "timeout" // the fetch hangs for Delay, then fails as a timeout
+	FaultError   = "error"   // the fetch fails as a blockchain query failure
+	FaultStale   = "stale"   // data is returned but reported as observed StaleAge ago
+	FaultPartial = "partial" // data is returned without status (cluster) data
+)
+
+const defaultFaultDelay = 5 * time.Second
+
+// Synthetic failure forced on one provider address
+type Fault struct {
+	Address  string        `json:"address" yaml:"address"`
+	Kind     string        `json:"kind" yaml:"kind"`
+	Delay    time.Duration `json:"delay,omitempty" yaml:"delay"`         // timeout
+	StaleAge time.Duration `json:"stale_age,omitempty" yaml:"stale_age"` // stale
+	Message  string        `json:"message,omitempty" yaml:"message"`     // error, partial
+}
+
+// Synthetic failures for exercising client error paths. Only the debug build of
+// the server ever installs one, see EnableFailureInjection.
+type FailureInjector struct {
+	faults map[string]Fault
+	mutex  sync.RWMutex
+}
+
+func NewFailureInjector() *FailureInjector {
+	return &FailureInjector{faults: make(map[string]Fault)}
+}
+
+// Force a failure on an address, replacing any existing one
+func (f *FailureInjector) Set(fault Fault) error {
+	if fault.Address == "" {
+		return fmt.Errorf("fault address is required")
+	}
+	switch fault.Kind {
+	case FaultTimeout, FaultError, FaultPartial:
+	case FaultStale:
+		if fault.StaleAge <= 0 {
+			return fmt.Errorf("stale fault requires a positive stale_age")
+		}
+	default:
+		return fmt.Errorf("unknown fault kind %q (valid: %s, %s, %s, %s)", fault.Kind, FaultTimeout, FaultError, FaultStale, FaultPartial)
+	}
+	if fault.Delay < 0 {
+		return fmt.Errorf("fault delay must not be negative")
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.faults[fault.Address] = fault
+	return nil
+}
+
+func (f *FailureInjector) Clear(address string) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	_, ok := f.faults[address]
+	delete(f.faults, address)
+	return ok
+}
+
+// Get the configured faults, by address
+func (f *FailureInjector) List() []Fault {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	faults := make([]Fault, 0, len(f.faults))
+	for _, fault := range f.faults {
+		faults = append(faults, fault)
+	}
+	sort.Slice(faults, func(i, j int) bool {
+		return faults[i].Address < faults[j].Address
+	})
+	return faults
+}
+
+func (f *FailureInjector) lookup(address string) (Fault, bool) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	fault, ok := f.faults[address]
+	return fault, ok
+}
+
+// Install a failure injector. Only call this from debug builds.
+func (s *Service) EnableFailureInjection(injector *FailureInjector) {
+	slog.Warn("Failure injection enabled - responses for faulted providers are synthetic")
+	s.injector = injector
+}
+
+// Split off addresses whose fetch is forced to fail. Their failures are
+// produced concurrently with the real fetch and collected by calling wait.
+func (s *Service) injectFetchFailures(ctx context.Context, addresses []string) ([]string, func() []*akash.FailedProvider) {
+	noFailures := func() []*akash.FailedProvider { return nil }
+	if s.injector == nil {
+		return addresses, noFailures
+	}
+
+	var remaining []string
+	var faulted []Fault
+	for _, addr := range addresses {
+		fault, ok := s.injector.lookup(addr)
+		if ok && (fault.Kind == FaultTimeout || fault.Kind == FaultError) {
+			faulted = append(faulted, fault)
+		} else {
+			remaining = append(remaining, addr)
+		}
+	}
+	if len(faulted) == 0 {
+		return addresses, noFailures
+	}
+
+	failed := make([]*akash.FailedProvider, len(faulted))
+	var wg sync.WaitGroup
+	for i, fault := range faulted {
+		wg.Add(1)
+		go func(index int, fault Fault) {
+			defer wg.Done()
+			failed[index] = injectedFailure(ctx, fault)
+		}(i, fault)
+	}
+
+	return remaining, func() []*akash.FailedProvider {
+		wg.Wait()
+		return failed
+	}
+}
+
+func injectedFailure(ctx context.Context, fault Fault) *akash.FailedProvider {
+	if fault.Kind == FaultError {
+		message := fault.Message
+		if message == "" {
+			message = "injected failure"
+		}
+		return &akash.FailedProvider{
+			Address:  fault.Address,
+			Error:    fmt.Sprintf("blockchain query failed: %s", message),
+			Category: akash.FailureBlockchainQuery,
+			FailedAt: time.Now(),
+		}
+	}
+
+	delay := fault.Delay
+	if delay == 0 {
+		delay = defaultFaultDelay
+	}
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+	}
+	return &akash.FailedProvider{
+		Address:  fault.Address,
+		Error:    fmt.Sprintf("blockchain query failed: %v (injected)", context.DeadlineExceeded),
+		Category: akash.FailureTimeout,
+		FailedAt: time.Now(),
+	}
+}
+
+// Degrade returned provider data as configured. Copies are modified so the
+// cache keeps the real data and removing a fault takes effect immediately.
+func (s *Service) injectDataFaults(providers []*akash.ProviderInfo) []*akash.ProviderInfo {
+	if s.injector == nil {
+		return providers
+	}
+
+	result := make([]*akash.ProviderInfo, len(providers))
+	for i, info := range providers {
+		result[i] = info
+		fault, ok := s.injector.lookup(info.Address)
+		if !ok {
+			continue
+		}
+
+		degraded := *info
+		switch fault.Kind {
+		case FaultStale:
+			degraded.LastSeen = time.Now().Add(-fault.StaleAge)
+		case FaultPartial:
+			message := fault.Message
+			if message == "" {
+				message = "failed to query status endpoint (injected)"
+			}
+			degraded.ClusterInfo = nil
+			degraded.SmoothedHealthScore = nil
+			degraded.Unproven = false
+			degraded.Error = message
+			degraded.HealthScore = s.akashClient.PartialHealthScore(&degraded)
+		default:
+			continue
+		}
+		result[i] = &degraded
+	}
+	return result
+}
@@ -0,0 +1,133 @@
+package intelligence
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Order in which providers missing from the cache are fetched
+const (
+	FetchOrderInput   = "input"   // as requested
+	FetchOrderPrior   = "prior"   // likely winners first, by hint or last known health score
+	FetchOrderStalest = "stalest" // oldest observations first, never observed before all
+)
+
+func ValidateFetchOrder(order string) error {
+	switch order {
+	case "", FetchOrderInput, FetchOrderPrior, FetchOrderStalest:
+		return nil
+	}
+	return fmt.Errorf("unknown fetch order %q (valid: %s, %s, %s)", order, FetchOrderInput, FetchOrderPrior, FetchOrderStalest)
+}
+
+// Order the fetch queue so that, when the time budget only allows part of it to
+// complete, the providers that completed are the ones that matter most: the
+// likeliest winners (prior) or the least fresh data (stalest).
+func (s *Service) orderFetchQueue(addresses []string, opts FetchOptions) []string {
+	order := opts.Order
+	if order == "" {
+		order = s.config.FetchOrder
+	}
+	if len(addresses) < 2 {
+		return addresses
+	}
+
+	switch order {
+	case FetchOrderPrior:
+		return s.orderByPrior(addresses, opts.Hints)
+	case FetchOrderStalest:
+		return s.orderByStaleness(addresses)
+	}
+	return addresses
+}
+
+// Likely winners first; providers without a prior keep their input order after those with one
+func (s *Service) orderByPrior(addresses []string, hints map[string]float64) []string {
+	priors := s.fetchPriors(addresses, hints)
+	if len(priors) == 0 {
+		return addresses
+	}
+
+	ordered := append([]string(nil), addresses...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		pi, iok := priors[ordered[i]]
+		pj, jok := priors[ordered[j]]
+		if iok != jok {
+			return iok
+		}
+		return iok && pi > pj
+	})
+	return ordered
+}
+
+// Cheap estimate of each provider's chances: a caller-supplied hint, otherwise
+// the health score of the last observation (expired cache entries included)
+func (s *Service) fetchPriors(addresses []string, hints map[string]float64) map[string]float64 {
+	priors := make(map[string]float64)
+
+	s.cache.mutex.RLock()
+	for _, addr := range addresses {
+		if hint, ok := hints[addr]; ok {
+			priors[addr] = hint
+			continue
+		}
+		if cached, ok := s.cache.data[addr]; ok && cached.Info != nil {
+			priors[addr] = cached.Info.HealthScore
+		}
+	}
+	s.cache.mutex.RUnlock()
+
+	if s.history != nil {
+		for _, addr := range addresses {
+			if _, ok := priors[addr]; ok {
+				continue
+			}
+			if snapshot, ok := s.history.SnapshotAt(addr, time.Now()); ok {
+				priors[addr] = snapshot.Info.HealthScore
+			}
+		}
+	}
+
+	return priors
+}
+
+// Oldest observations first, so a partial fetch raises the freshness of the
+// whole set the most. Providers never observed come first, in input order.
+func (s *Service) orderByStaleness(addresses []string) []string {
+	observed := s.lastObserved(addresses)
+
+	ordered := append([]string(nil), addresses...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return observed[ordered[i]].Before(observed[ordered[j]])
+	})
+	return ordered
+}
+
+// When each provider was last observed: its cache entry (expired included),
+// otherwise its latest history sample. Unobserved providers are absent.
+func (s *Service) lastObserved(addresses []string) map[string]time.Time {
+	observed := make(map[string]time.Time)
+
+	s.cache.mutex.RLock()
+	for _, addr := range addresses {
+		if cached, ok := s.cache.data[addr]; ok {
+			observed[addr] = cached.CachedAt
+		}
+	}
+	s.cache.mutex.RUnlock()
+
+	if s.history != nil {
+		now := time.Now()
+		for _, addr := range addresses {
+			if _, ok := observed[addr]; ok {
+				continue
+			}
+			if snapshot, ok := s.history.SnapshotAt(addr, now); ok {
+				observed[addr] = snapshot.ObservedAt
+			}
+		}
+	}
+
+	return observed
+}
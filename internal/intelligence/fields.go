@@ -0,0 +1,158 @@
+package intelligence
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+var (
+	providerInfoType  = reflect.TypeOf(akash.ProviderInfo{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// Intelligence with only the requested fields of each provider
+type SparseIntelligenceResult struct {
+	*IntelligenceResult
+	Providers []map[string]interface{} `json:"providers"`
+	Fields    []string                 `json:"fields"`
+}
+
+// Parse a comma-separated list of provider field paths using JSON names
+// (e.g. "address,health_score,cluster_info.available_resources.gpu"),
+// rejecting any path that does not exist on ProviderInfo
+func ParseFields(spec string) ([]string, error) {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || seen[field] {
+			continue
+		}
+		if err := validateFieldPath(providerInfoType, strings.Split(field, ".")); err != nil {
+			return nil, fmt.Errorf("invalid field %q: %w", field, err)
+		}
+		seen[field] = true
+		fields = append(fields, field)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no fields given")
+	}
+	return fields, nil
+}
+
+// Keep only the requested fields of each provider, nesting dotted paths
+func (r *IntelligenceResult) WithFields(fields []string) *SparseIntelligenceResult {
+	sparse := &SparseIntelligenceResult{
+		IntelligenceResult: r,
+		Providers:          make([]map[string]interface{}, 0, len(r.Providers)),
+		Fields:             fields,
+	}
+	for _, provider := range r.Providers {
+		projected := make(map[string]interface{})
+		for _, field := range fields {
+			path := strings.Split(field, ".")
+			if value, ok := lookupField(reflect.ValueOf(provider), path); ok {
+				setNested(projected, path, value)
+			}
+		}
+		sparse.Providers = append(sparse.Providers, projected)
+	}
+	return sparse
+}
+
+// Check that a path of JSON names leads somewhere in t
+func validateFieldPath(t reflect.Type, path []string) error {
+	for i, segment := range path {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if segment == "" {
+			return fmt.Errorf("empty path segment")
+		}
+
+		switch {
+		case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+			// Any key may be selected
+			t = t.Elem()
+		case t.Kind() == reflect.Struct && !reflect.PointerTo(t).Implements(jsonMarshalerType):
+			field, ok := fieldByJSONName(t, segment)
+			if !ok {
+				return fmt.Errorf("unknown field %q", strings.Join(path[:i+1], "."))
+			}
+			t = field.Type
+		default:
+			return fmt.Errorf("%q has no subfields", strings.Join(path[:i], "."))
+		}
+	}
+	return nil
+}
+
+// Resolve a validated path, reporting false where a pointer or map entry is absent
+func lookupField(v reflect.Value, path []string) (interface{}, bool) {
+	for _, segment := range path {
+		for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return nil, false
+			}
+			v = v.Elem()
+		}
+
+		switch v.Kind() {
+		case reflect.Map:
+			v = v.MapIndex(reflect.ValueOf(segment))
+			if !v.IsValid() {
+				return nil, false
+			}
+		case reflect.Struct:
+			field, ok := fieldByJSONName(v.Type(), segment)
+			if !ok {
+				return nil, false
+			}
+			v = v.FieldByIndex(field.Index)
+		default:
+			return nil, false
+		}
+	}
+
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Map || v.Kind() == reflect.Slice) && v.IsNil() {
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+// Find the exported struct field serialized under name
+func fieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tagName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tagName == "-" {
+			continue
+		}
+		if tagName == "" {
+			tagName = field.Name
+		}
+		if tagName == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func setNested(target map[string]interface{}, path []string, value interface{}) {
+	for _, segment := range path[:len(path)-1] {
+		next, ok := target[segment].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			target[segment] = next
+		}
+		target = next
+	}
+	target[path[len(path)-1]] = value
+}
@@ -0,0 +1,48 @@
+package intelligence
+
+import (
+	"fmt"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Provider removed from the candidate set by a selection requirement
+type FilteredProvider struct {
+	Address string `json:"address"`
+	Reason  string `json:"reason"`
+}
+
+// Drop providers with fewer available nodes than required. Providers whose node
+// count is unknown (no status data) are kept only if configured to be.
+func (s *Service) filterByAvailableNodes(providers []*akash.ProviderInfo, minNodes int) ([]*akash.ProviderInfo, []FilteredProvider) {
+	if minNodes <= 0 {
+		return providers, nil
+	}
+
+	kept := make([]*akash.ProviderInfo, 0, len(providers))
+	var filtered []FilteredProvider
+	for _, provider := range providers {
+		if provider.ClusterInfo == nil {
+			if s.config.IncludeUnknownNodeCount {
+				kept = append(kept, provider)
+			} else {
+				filtered = append(filtered, FilteredProvider{
+					Address: provider.Address,
+					Reason:  "available node count unknown",
+				})
+			}
+			continue
+		}
+
+		if nodes := provider.ClusterInfo.AvailableNodes; nodes < minNodes {
+			filtered = append(filtered, FilteredProvider{
+				Address: provider.Address,
+				Reason:  fmt.Sprintf("%d available nodes, %d required", nodes, minNodes),
+			})
+			continue
+		}
+		kept = append(kept, provider)
+	}
+
+	return kept, filtered
+}
@@ -0,0 +1,64 @@
+package intelligence
+
+import (
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Provider skipped before full scoring because it could not reach the score floor
+type PrunedProvider struct {
+	Address    string  `json:"address"`
+	UpperBound float64 `json:"upper_bound"`
+}
+
+// Highest score a provider could reach under the given criteria. The built-in
+// dimensions are cheap and computed exactly; lease stability, provisioning speed
+// and plugin scores need history lookups or external calls, so they are assumed to score 1.
+func (s *Service) scoreUpperBound(provider *akash.ProviderInfo, criteria SelectionCriteria) float64 {
+	provider, _ = s.scoringView(provider)
+
+	bound := selectionHealthScore(provider) * criteria.Weights.Reliability
+	bound += s.calculatePerformanceScore(provider) * criteria.Weights.Performance
+	bound += s.calculateGeographicScore(provider) * criteria.Weights.Geographic
+	if _, ok := provider.Pricing.ReferenceMonthlyCost(); ok {
+		bound += criteria.Weights.Price // relative to the other candidates, at most 1
+	} else {
+		bound += s.calculatePriceScore(provider) * criteria.Weights.Price
+	}
+	bound += s.calculatePriorityBonus(provider, criteria.Priority)
+
+	if criteria.DeploymentDuration > 0 && criteria.Weights.Stability > 0 {
+		bound += criteria.Weights.Stability * stabilityDurationFactor(criteria.DeploymentDuration)
+	}
+
+	bound += criteria.Weights.Provisioning // needs history lookups, assumed fast
+	bound += networkScore(provider, assessNetwork(provider)) * criteria.Weights.Network
+
+	for _, configured := range s.config.CustomScorers {
+		if configured.Weight > 0 {
+			bound += configured.Weight
+		}
+	}
+
+	return bound
+}
+
+// Split providers into those that can still reach the configured score floor
+// and those that cannot, without running the full scoring pipeline
+func (s *Service) pruneBelowFloor(providers []*akash.ProviderInfo, criteria SelectionCriteria) ([]*akash.ProviderInfo, []PrunedProvider) {
+	if s.config.ScoreFloor <= 0 {
+		return providers, nil
+	}
+
+	kept := make([]*akash.ProviderInfo, 0, len(providers))
+	var pruned []PrunedProvider
+	for _, provider := range providers {
+		bound := s.scoreUpperBound(provider, criteria)
+		if bound < s.config.ScoreFloor {
+			pruned = append(pruned, PrunedProvider{Address: provider.Address, UpperBound: bound})
+			continue
+		}
+		kept = append(kept, provider)
+	}
+
+	return kept, pruned
+}
@@ -0,0 +1,83 @@
+package intelligence
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Providers resembling a mainnet listing: about a third unreachable, the rest
+// spread over health, latency and free capacity
+func syntheticProviders(n int, now time.Time) []*akash.ProviderInfo {
+	regions := []string{"us-west", "us-east", "eu-central", "ap-southeast"}
+	providers := make([]*akash.ProviderInfo, n)
+	for i := range providers {
+		provider := &akash.ProviderInfo{
+			Address:             fmt.Sprintf("akash1provider%04d", i),
+			HostURI:             fmt.Sprintf("https://provider%04d.example.com:8443", i),
+			Attributes:          map[string]string{"region": regions[i%len(regions)]},
+			LastSeen:            now,
+			BlockchainQueryTime: 150 * time.Millisecond,
+		}
+		if i%3 == 0 {
+			provider.Error = "status endpoint unreachable"
+			provider.HealthScore = 0.1
+			provider.StatusQueryTime = 5 * time.Second
+		} else {
+			provider.HealthScore = 0.5 + float64(i%50)/100
+			provider.StatusQueryTime = time.Duration(100+i%20*100) * time.Millisecond
+			provider.ClusterInfo = &akash.ClusterStatus{
+				ActiveLeases:       i % 40,
+				AvailableNodes:     i % 4,
+				AvailableResources: akash.ResourceSummary{CPU: int64(i%16) * 1000, Memory: int64(i%32) << 30},
+			}
+		}
+		providers[i] = provider
+	}
+	return providers
+}
+
+// Full scoring of a few hundred providers with and without a score floor, to
+// measure what pruning providers that can't reach it saves
+func BenchmarkSelectWithScoreFloor(b *testing.B) {
+	now := time.Now()
+	providers := syntheticProviders(300, now)
+
+	// Two days of hourly history per provider, as stability and provisioning read it
+	history := NewHistoryStore(7*24*time.Hour, 0)
+	for hour := 48; hour > 0; hour-- {
+		for _, provider := range providers {
+			sample := *provider
+			sample.LastSeen = now.Add(-time.Duration(hour) * time.Hour)
+			history.Record(&sample, sample.LastSeen)
+		}
+	}
+
+	criteria := SelectionCriteria{
+		Weights:            DefaultWeights(),
+		DeploymentDuration: 30 * 24 * time.Hour,
+	}
+
+	for _, floor := range []float64{0, 0.6} {
+		service := &Service{
+			config:  &Config{ScoreFloor: floor},
+			history: history,
+		}
+		b.Run(fmt.Sprintf("score_floor=%.1f", floor), func(b *testing.B) {
+			ctx := context.Background()
+			var pruned int
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, _, prunedProviders, err := service.filterAndScore(ctx, providers, criteria)
+				if err != nil {
+					b.Fatal(err)
+				}
+				pruned = len(prunedProviders)
+			}
+			b.ReportMetric(float64(pruned), "pruned")
+		})
+	}
+}
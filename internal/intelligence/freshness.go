@@ -0,0 +1,63 @@
+package intelligence
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Options controlling how provider intelligence is gathered
+type FetchOptions struct {
+	// Every returned provider must have been observed within this window;
+	// staler cache entries are refetched. Zero accepts any unexpired cache entry.
+	MaxDataAge time.Duration
+
+	// Order of the fetch queue (FetchOrderInput or FetchOrderPrior), defaulting
+	// to the configured order. Hints are caller-supplied priors by address and
+	// take precedence over the last known health score.
+	Order string
+	Hints map[string]float64
+
+	// Only fetch this page of the addresses; nil fetches all of them
+	Page *Page
+
+	// Ignore the cache and refetch every address
+	Refresh bool
+}
+
+// Confirmation that a max_data_age contract was honoured
+type FreshnessGuarantee struct {
+	MaxDataAge    time.Duration `json:"max_data_age"`
+	OldestDataAge time.Duration `json:"oldest_data_age"`
+	Met           bool          `json:"met"`
+}
+
+// Check that every provider satisfies the requested max data age. Providers that
+// could not be refreshed break the contract, since no fresh data exists for them.
+func checkFreshness(providers []*akash.ProviderInfo, failed []*akash.FailedProvider, maxAge time.Duration, now time.Time) (*FreshnessGuarantee, error) {
+	guarantee := &FreshnessGuarantee{MaxDataAge: maxAge}
+
+	var violations []string
+	for _, provider := range providers {
+		age := now.Sub(provider.LastSeen)
+		if age > guarantee.OldestDataAge {
+			guarantee.OldestDataAge = age
+		}
+		if age > maxAge {
+			violations = append(violations, fmt.Sprintf("%s (%v old)", provider.Address, age.Round(time.Millisecond)))
+		}
+	}
+	for _, failure := range failed {
+		violations = append(violations, fmt.Sprintf("%s (refresh failed: %s)", failure.Address, failure.Error))
+	}
+
+	if len(violations) > 0 {
+		return guarantee, fmt.Errorf("max_data_age of %v could not be met for %d providers: %s",
+			maxAge, len(violations), strings.Join(violations, ", "))
+	}
+
+	guarantee.Met = true
+	return guarantee, nil
+}
@@ -0,0 +1,96 @@
+package intelligence
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// What happens to the geographic weight when it is disabled for low coverage
+const (
+	GeoRedistributeProportional = "proportional" // spread over the other weighted dimensions
+	GeoRedistributeNone         = "none"         // dropped; scores shrink accordingly
+)
+
+func ValidateGeoRedistribution(policy string) error {
+	switch policy {
+	case "", GeoRedistributeProportional, GeoRedistributeNone:
+		return nil
+	}
+	return fmt.Errorf("unknown geographic redistribution %q (valid: %s, %s)", policy, GeoRedistributeProportional, GeoRedistributeNone)
+}
+
+// Record of the geographic dimension being switched off for a candidate set
+type GeoAdjustment struct {
+	Coverage       float64            `json:"coverage"`  // share of candidates with a region
+	Threshold      float64            `json:"threshold"` // configured minimum coverage
+	DisabledWeight float64            `json:"disabled_weight"`
+	Redistributed  map[string]float64 `json:"redistributed,omitempty"` // weight added per dimension
+}
+
+// Zero the geographic weight when too few candidates report a region for it to
+// carry signal: everyone else would score the neutral 0.5 and it would only add noise.
+func (s *Service) applyGeoCoverage(providers []*akash.ProviderInfo, criteria SelectionCriteria) SelectionCriteria {
+	threshold := s.config.GeoMinCoverage
+	if threshold <= 0 || criteria.Weights.Geographic <= 0 || len(providers) == 0 {
+		return criteria
+	}
+
+	located := 0
+	for _, provider := range providers {
+		if strings.TrimSpace(provider.Attributes["region"]) != "" {
+			located++
+		}
+	}
+	coverage := float64(located) / float64(len(providers))
+	if coverage >= threshold {
+		return criteria
+	}
+
+	adjustment := &GeoAdjustment{
+		Coverage:       coverage,
+		Threshold:      threshold,
+		DisabledWeight: criteria.Weights.Geographic,
+	}
+
+	weights := criteria.Weights
+	weights.Geographic = 0
+	if s.config.GeoRedistribution != GeoRedistributeNone {
+		// Stability only counts toward scores when a deployment duration is given
+		var recipients []string
+		total := 0.0
+		for _, name := range weightNames {
+			if name == "geographic" || (name == "stability" && criteria.DeploymentDuration <= 0) {
+				continue
+			}
+			if value := *weights.field(name); value > 0 {
+				recipients = append(recipients, name)
+				total += value
+			}
+		}
+
+		if total > 0 {
+			adjustment.Redistributed = make(map[string]float64, len(recipients))
+			for _, name := range recipients {
+				share := adjustment.DisabledWeight * *weights.field(name) / total
+				*weights.field(name) += share
+				adjustment.Redistributed[name] = share
+			}
+		}
+	}
+
+	criteria.Weights = weights
+	criteria.GeoAdjustment = adjustment
+	return criteria
+}
+
+// Describe a geographic adjustment for reasoning
+func describeGeoAdjustment(adjustment *GeoAdjustment) string {
+	text := fmt.Sprintf("Geographic weight (%.1f%%) disabled: only %.0f%% of candidates report a region (minimum %.0f%%)",
+		adjustment.DisabledWeight*100, adjustment.Coverage*100, adjustment.Threshold*100)
+	if len(adjustment.Redistributed) > 0 {
+		return text + " - redistributed proportionally to the other dimensions"
+	}
+	return text
+}
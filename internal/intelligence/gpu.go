@@ -0,0 +1,195 @@
+package intelligence
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+	"golang.org/x/sync/semaphore"
+)
+
+const (
+	defaultGPUCacheTTL = 30 * time.Second
+	defaultGPUTimeout  = 2 * time.Second
+)
+
+// Short-lived GPU availability readings, kept apart from the provider cache
+// because GPU counts change far faster than the rest of a provider's data
+type GPUCache struct {
+	ttl   time.Duration
+	data  map[string]*akash.GPUAvailability
+	mutex sync.RWMutex
+}
+
+func NewGPUCache(ttl time.Duration) *GPUCache {
+	if ttl <= 0 {
+		ttl = defaultGPUCacheTTL
+	}
+	return &GPUCache{
+		ttl:  ttl,
+		data: make(map[string]*akash.GPUAvailability),
+	}
+}
+
+// Get an unexpired successful reading
+func (c *GPUCache) get(address string, now time.Time) (*akash.GPUAvailability, bool) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	reading, ok := c.data[address]
+	if !ok || !now.Before(reading.ObservedAt.Add(c.ttl)) {
+		return nil, false
+	}
+	return reading, true
+}
+
+func (c *GPUCache) put(reading *akash.GPUAvailability) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.data[reading.Address] = reading
+}
+
+// Drop expired readings
+func (c *GPUCache) PruneExpired(now time.Time) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	removed := 0
+	for addr, reading := range c.data {
+		if !now.Before(reading.ObservedAt.Add(c.ttl)) {
+			delete(c.data, addr)
+			removed++
+		}
+	}
+	return removed
+}
+
+func (c *GPUCache) remove(address string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.data, address)
+}
+
+func (c *GPUCache) clear() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.data = make(map[string]*akash.GPUAvailability)
+}
+
+// Near-real-time GPU availability across a set of providers, most free GPUs first
+type GPUAvailabilityResult struct {
+	Providers  []*akash.GPUAvailability `json:"providers"`
+	TotalFree  int                      `json:"total_free"`
+	TotalGPUs  int                      `json:"total_gpus"`
+	CacheTTL   time.Duration            `json:"cache_ttl"`
+	FromCache  int                      `json:"from_cache"`
+	QueryTime  time.Duration            `json:"query_time"`
+	ObservedAt time.Time                `json:"observed_at"`
+}
+
+// Fetch only GPU availability for the given providers, skipping full enrichment.
+// Host URIs come from cached provider data where available (registrations
+// rarely change), otherwise from a single chain query.
+func (s *Service) GetGPUAvailability(ctx context.Context, addresses []string) (*GPUAvailabilityResult, error) {
+	start := time.Now()
+	result := &GPUAvailabilityResult{
+		Providers:  make([]*akash.GPUAvailability, len(addresses)),
+		CacheTTL:   s.gpuCache.ttl,
+		ObservedAt: start,
+	}
+
+	timeout := s.config.GPUTimeout
+	if timeout <= 0 {
+		timeout = defaultGPUTimeout
+	}
+	concurrency := int64(s.config.MaxConcurrent)
+	if concurrency <= 0 {
+		concurrency = 10
+	}
+	slots := semaphore.NewWeighted(concurrency)
+
+	var wg sync.WaitGroup
+	for i, addr := range addresses {
+		if cached, ok := s.gpuCache.get(addr, start); ok {
+			result.Providers[i] = cached
+			result.FromCache++
+			continue
+		}
+
+		if err := slots.Acquire(ctx, 1); err != nil {
+			result.Providers[i] = &akash.GPUAvailability{Address: addr, Error: err.Error(), ObservedAt: time.Now()}
+			continue
+		}
+		wg.Add(1)
+		go func(index int, address string) {
+			defer wg.Done()
+			defer slots.Release(1)
+			result.Providers[index] = s.queryGPUAvailability(ctx, address, timeout)
+		}(i, addr)
+	}
+	wg.Wait()
+
+	for _, reading := range result.Providers {
+		result.TotalFree += reading.Free
+		result.TotalGPUs += reading.Total
+	}
+
+	// Most free GPUs first; unreachable providers last
+	sort.SliceStable(result.Providers, func(i, j int) bool {
+		a, b := result.Providers[i], result.Providers[j]
+		if (a.Error == "") != (b.Error == "") {
+			return a.Error == ""
+		}
+		if a.Free != b.Free {
+			return a.Free > b.Free
+		}
+		return a.Address < b.Address
+	})
+
+	result.QueryTime = time.Since(start)
+	return result, nil
+}
+
+// Read one provider's GPU availability within the fast-path timeout
+func (s *Service) queryGPUAvailability(ctx context.Context, address string, timeout time.Duration) *akash.GPUAvailability {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	reading := &akash.GPUAvailability{Address: address}
+
+	hostURI, attributes := s.cachedRegistration(address)
+	var err error
+	if hostURI == "" {
+		hostURI, attributes, err = s.akashClient.GetProviderRegistration(ctx, address)
+	}
+	if err == nil {
+		reading.Models = akash.ParseGPUModels(attributes)
+		reading.Free, reading.Total, err = s.akashClient.QueryGPUAvailability(ctx, hostURI)
+	}
+
+	reading.QueryTime = time.Since(start)
+	reading.ObservedAt = time.Now()
+	if err != nil {
+		reading.Error = err.Error()
+		return reading
+	}
+
+	// Only successful readings are cached so failures are retried on the next call
+	s.gpuCache.put(reading)
+	return reading
+}
+
+// Host URI and attributes from the provider cache, expired entries included
+func (s *Service) cachedRegistration(address string) (string, map[string]string) {
+	s.cache.mutex.RLock()
+	defer s.cache.mutex.RUnlock()
+
+	cached, ok := s.cache.data[address]
+	if !ok || cached.Info == nil {
+		return "", nil
+	}
+	return cached.Info.HostURI, cached.Info.Attributes
+}
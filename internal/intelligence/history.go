@@ -0,0 +1,178 @@
+package intelligence
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Point-in-time observation of a provider, recorded whenever fresh data is fetched
+type ProviderSnapshot struct {
+	Info       *akash.ProviderInfo `json:"info"`
+	ObservedAt time.Time           `json:"observed_at"`
+}
+
+// In-memory historical store of provider observations with bounded retention
+type HistoryStore struct {
+	retention  time.Duration
+	maxSamples int
+	providers  map[string][]ProviderSnapshot
+	network    []NetworkStatsSample
+	mutex      sync.RWMutex
+}
+
+func NewHistoryStore(retention time.Duration, maxSamples int) *HistoryStore {
+	return &HistoryStore{
+		retention:  retention,
+		maxSamples: maxSamples,
+		providers:  make(map[string][]ProviderSnapshot),
+	}
+}
+
+// Record an observation of a provider
+func (h *HistoryStore) Record(info *akash.ProviderInfo, observedAt time.Time) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	samples := append(h.providers[info.Address], ProviderSnapshot{
+		Info:       info,
+		ObservedAt: observedAt,
+	})
+
+	// Observations normally arrive in order, keep the slice sorted if they don't
+	if n := len(samples); n > 1 && samples[n-1].ObservedAt.Before(samples[n-2].ObservedAt) {
+		sort.Slice(samples, func(i, j int) bool {
+			return samples[i].ObservedAt.Before(samples[j].ObservedAt)
+		})
+	}
+
+	if h.maxSamples > 0 && len(samples) > h.maxSamples {
+		samples = samples[len(samples)-h.maxSamples:]
+	}
+
+	h.providers[info.Address] = samples
+}
+
+// Get the latest observation of a provider at or before the given time
+func (h *HistoryStore) SnapshotAt(address string, at time.Time) (*ProviderSnapshot, bool) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	samples := h.providers[address]
+	index := sort.Search(len(samples), func(i int) bool {
+		return samples[i].ObservedAt.After(at)
+	})
+	if index == 0 {
+		return nil, false
+	}
+
+	snapshot := samples[index-1]
+	return &snapshot, true
+}
+
+// Get all observations of a provider within [since, until]
+func (h *HistoryStore) Samples(address string, since, until time.Time) []ProviderSnapshot {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	var result []ProviderSnapshot
+	for _, sample := range h.providers[address] {
+		if !sample.ObservedAt.Before(since) && !sample.ObservedAt.After(until) {
+			result = append(result, sample)
+		}
+	}
+	return result
+}
+
+// Addresses of every provider with recorded observations
+func (h *HistoryStore) Addresses() []string {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	addresses := make([]string, 0, len(h.providers))
+	for address := range h.providers {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+	return addresses
+}
+
+// Record a network-wide stats sample
+func (h *HistoryStore) RecordNetwork(sample NetworkStatsSample) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.network = append(h.network, sample)
+	if n := len(h.network); n > 1 && sample.ObservedAt.Before(h.network[n-2].ObservedAt) {
+		sort.Slice(h.network, func(i, j int) bool {
+			return h.network[i].ObservedAt.Before(h.network[j].ObservedAt)
+		})
+	}
+}
+
+// Get network-wide stats samples within [since, until]
+func (h *HistoryStore) NetworkSamples(since, until time.Time) []NetworkStatsSample {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	var result []NetworkStatsSample
+	for _, sample := range h.network {
+		if !sample.ObservedAt.Before(since) && !sample.ObservedAt.After(until) {
+			result = append(result, sample)
+		}
+	}
+	return result
+}
+
+// Drop observations older than the retention window
+func (h *HistoryStore) Prune(now time.Time) int {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	cutoff := now.Add(-h.retention)
+	removed := 0
+
+	for addr, samples := range h.providers {
+		keep := sort.Search(len(samples), func(i int) bool {
+			return !samples[i].ObservedAt.Before(cutoff)
+		})
+		removed += keep
+
+		if keep == len(samples) {
+			delete(h.providers, addr)
+		} else if keep > 0 {
+			h.providers[addr] = append([]ProviderSnapshot(nil), samples[keep:]...)
+		}
+	}
+
+	keep := sort.Search(len(h.network), func(i int) bool {
+		return !h.network[i].ObservedAt.Before(cutoff)
+	})
+	if keep > 0 {
+		h.network = append([]NetworkStatsSample(nil), h.network[keep:]...)
+		removed += keep
+	}
+
+	return removed
+}
+
+// Get store statistics
+func (h *HistoryStore) Stats() map[string]interface{} {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	samples := 0
+	for _, providerSamples := range h.providers {
+		samples += len(providerSamples)
+	}
+
+	return map[string]interface{}{
+		"providers":       len(h.providers),
+		"samples":         samples,
+		"network_samples": len(h.network),
+		"retention":       h.retention.String(),
+		"max_samples":     h.maxSamples,
+	}
+}
@@ -0,0 +1,185 @@
+package intelligence
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+const defaultHostIndexRefresh = 10 * time.Minute
+
+// Reverse index of on-chain host URIs to provider addresses
+type HostIndex struct {
+	byURI     map[string][]string // normalized host URI -> addresses
+	byHost    map[string][]string // hostname -> addresses
+	indexedAt time.Time
+	mutex     sync.RWMutex
+}
+
+func NewHostIndex() *HostIndex {
+	return &HostIndex{
+		byURI:  make(map[string][]string),
+		byHost: make(map[string][]string),
+	}
+}
+
+// Replace the index contents with the given provider list
+func (h *HostIndex) Rebuild(providers []*akash.ListedProvider, indexedAt time.Time) {
+	byURI := make(map[string][]string)
+	byHost := make(map[string][]string)
+	for _, provider := range providers {
+		uri, host, ok := normalizeHostURI(provider.HostURI)
+		if !ok {
+			continue
+		}
+		byURI[uri] = append(byURI[uri], provider.Address)
+		byHost[host] = append(byHost[host], provider.Address)
+	}
+	for _, addresses := range byURI {
+		sort.Strings(addresses)
+	}
+	for _, addresses := range byHost {
+		sort.Strings(addresses)
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.byURI = byURI
+	h.byHost = byHost
+	h.indexedAt = indexedAt
+}
+
+// Find the providers registered with a host URI. An exact (normalized) URI
+// match wins; otherwise providers on the same hostname with any scheme or port match.
+func (h *HostIndex) Lookup(hostURI string) ([]string, bool) {
+	uri, host, ok := normalizeHostURI(hostURI)
+	if !ok {
+		return nil, false
+	}
+
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if addresses, ok := h.byURI[uri]; ok {
+		return append([]string(nil), addresses...), true
+	}
+	if addresses, ok := h.byHost[host]; ok {
+		return append([]string(nil), addresses...), true
+	}
+	return nil, false
+}
+
+// Get when the index was last rebuilt
+func (h *HostIndex) IndexedAt() time.Time {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.indexedAt
+}
+
+// Normalize a host URI to scheme://host:port and extract the hostname.
+// Bare hostnames (e.g. copied from logs) are accepted and assumed to be https.
+func normalizeHostURI(raw string) (string, string, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", "", false
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Hostname() == "" {
+		return "", "", false
+	}
+
+	scheme := strings.ToLower(parsed.Scheme)
+	host := strings.ToLower(parsed.Hostname())
+	port := parsed.Port()
+	if port == "" {
+		port = "443"
+		if scheme == "http" {
+			port = "80"
+		}
+	}
+
+	return fmt.Sprintf("%s://%s:%s", scheme, host, port), host, true
+}
+
+// Providers registered with a host URI, with their intelligence
+type HostLookup struct {
+	HostURI      string              `json:"host_uri"`
+	Addresses    []string            `json:"addresses"`
+	Shared       bool                `json:"shared"` // more than one provider registered this host
+	IndexedAt    time.Time           `json:"indexed_at"`
+	Intelligence *IntelligenceResult `json:"intelligence"`
+}
+
+// Rebuild the host URI index from the on-chain provider list
+func (s *Service) refreshHostIndex(ctx context.Context) error {
+	list, err := s.akashClient.ListProviders(ctx, akash.ListProvidersOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list providers: %w", err)
+	}
+	s.hostIndex.Rebuild(list.Providers, time.Now())
+	return nil
+}
+
+// Periodically rebuild the host URI index
+func (s *Service) hostIndexLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		refreshCtx, cancel := context.WithTimeout(ctx, interval)
+		if err := s.refreshHostIndex(refreshCtx); err != nil && ctx.Err() == nil {
+			slog.Warn("Host URI index refresh failed", "error", err)
+		}
+		cancel()
+		s.loopRan("host_index")
+	}
+}
+
+// Find the providers registered with a host URI and return their intelligence
+func (s *Service) GetProviderIntelligenceByHostURI(ctx context.Context, hostURI string) (*HostLookup, error) {
+	if _, _, ok := normalizeHostURI(hostURI); !ok {
+		return nil, fmt.Errorf("invalid host URI %q", hostURI)
+	}
+
+	// Build the index on first use; a miss may be a provider registered since the last refresh
+	addresses, found := s.hostIndex.Lookup(hostURI)
+	if !found {
+		if err := s.refreshHostIndex(ctx); err != nil {
+			return nil, err
+		}
+		addresses, found = s.hostIndex.Lookup(hostURI)
+	}
+	if !found {
+		return nil, fmt.Errorf("no provider is registered with host URI %s", hostURI)
+	}
+
+	intel, err := s.GetProviderIntelligence(ctx, addresses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get provider intelligence: %w", err)
+	}
+
+	return &HostLookup{
+		HostURI:      hostURI,
+		Addresses:    addresses,
+		Shared:       len(addresses) > 1,
+		IndexedAt:    s.hostIndex.IndexedAt(),
+		Intelligence: intel,
+	}, nil
+}
@@ -0,0 +1,187 @@
+package intelligence
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+const (
+	defaultLeaderboardSize = 100
+
+	// Providers fetched per step of a refresh
+	leaderboardBatchSize = 25
+)
+
+// A network-wide ranking of providers by overall score
+type Leaderboard struct {
+	ComputedAt      time.Time          `json:"computed_at"`
+	Age             time.Duration      `json:"age"`
+	RefreshDuration time.Duration      `json:"refresh_duration"`
+	Refreshing      bool               `json:"refreshing"` // a newer ranking is being computed
+	Ranked          int                `json:"ranked"`     // providers scored
+	Failed          int                `json:"failed"`     // providers that could not be fetched
+	Weights         Weights            `json:"weights"`
+	Entries         []LeaderboardEntry `json:"entries"`
+
+	// Set when only a page of the entries is returned
+	Truncation *Truncation `json:"truncation,omitempty"`
+}
+
+type LeaderboardEntry struct {
+	Rank        int      `json:"rank"`
+	Address     string   `json:"address"`
+	Score       float64  `json:"score"`
+	HealthScore float64  `json:"health_score"`
+	Region      string   `json:"region,omitempty"`
+	Tiers       []string `json:"tiers,omitempty"`
+}
+
+// Last good leaderboard and refresh state
+type leaderboardState struct {
+	current    *Leaderboard
+	refreshing bool
+	mutex      sync.RWMutex
+}
+
+// Get a page of the last computed leaderboard. Reads never trigger
+// computation; the ranking is refreshed in the background.
+func (s *Service) GetLeaderboard(page Page) (*Leaderboard, error) {
+	if s.config.LeaderboardInterval <= 0 {
+		return nil, fmt.Errorf("leaderboard is disabled")
+	}
+
+	s.leaderboard.mutex.RLock()
+	defer s.leaderboard.mutex.RUnlock()
+
+	if s.leaderboard.current == nil {
+		return nil, fmt.Errorf("leaderboard has not been computed yet, try again shortly")
+	}
+
+	board := *s.leaderboard.current
+	board.Age = time.Since(board.ComputedAt)
+	board.Refreshing = s.leaderboard.refreshing
+	board.Entries, board.Truncation = paginate(s, board.Entries, page)
+	return &board, nil
+}
+
+// Recompute the leaderboard on every interval, starting immediately
+func (s *Service) leaderboardLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		refreshCtx, cancel := context.WithTimeout(ctx, interval)
+		if err := s.refreshLeaderboard(refreshCtx, interval/2); err != nil && ctx.Err() == nil {
+			slog.Warn("Leaderboard refresh failed", "error", err)
+		}
+		cancel()
+		s.loopRan("leaderboard")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Fetch and score every registered provider, then swap in the new ranking.
+// Fetches are spread over the spread window in small batches, so a refresh
+// never bursts the whole network onto the shared concurrency limit.
+func (s *Service) refreshLeaderboard(ctx context.Context, spread time.Duration) error {
+	start := time.Now()
+	s.setLeaderboardRefreshing(true)
+	defer s.setLeaderboardRefreshing(false)
+
+	list, err := s.akashClient.ListProviders(ctx, akash.ListProvidersOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list providers: %w", err)
+	}
+
+	var addresses []string
+	for _, provider := range list.Providers {
+		if !s.blacklist.IsBanned(provider.Address) {
+			addresses = append(addresses, provider.Address)
+		}
+	}
+
+	batches := (len(addresses) + leaderboardBatchSize - 1) / leaderboardBatchSize
+	var pause time.Duration
+	if batches > 1 {
+		pause = spread / time.Duration(batches)
+	}
+
+	var providers []*akash.ProviderInfo
+	failed := 0
+	for i := 0; i < len(addresses); i += leaderboardBatchSize {
+		if i > 0 && pause > 0 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("refresh interrupted after %d of %d providers: %w", i, len(addresses), ctx.Err())
+			case <-time.After(pause):
+			}
+		}
+
+		batch := addresses[i:min(i+leaderboardBatchSize, len(addresses))]
+		intel, err := s.GetProviderIntelligence(ctx, batch)
+		if err != nil {
+			return fmt.Errorf("failed to get provider intelligence: %w", err)
+		}
+		providers = append(providers, intel.Providers...)
+		failed += len(intel.FailedProviders)
+	}
+	if len(providers) == 0 {
+		return fmt.Errorf("no provider data available (%d providers failed)", failed)
+	}
+
+	criteria := SelectionCriteria{Weights: s.leaderboardWeights()}
+	if criteria.Weights == (Weights{}) {
+		criteria.Weights = DefaultWeights()
+	}
+	criteria = s.applyGeoCoverage(providers, criteria)
+	scored := s.scoreProviders(ctx, providers, criteria)
+
+	size := s.config.LeaderboardSize
+	if size <= 0 {
+		size = defaultLeaderboardSize
+	}
+	entries := make([]LeaderboardEntry, 0, min(size, len(scored)))
+	for i, provider := range scored[:min(size, len(scored))] {
+		entries = append(entries, LeaderboardEntry{
+			Rank:        i + 1,
+			Address:     provider.Provider.Address,
+			Score:       provider.Score,
+			HealthScore: provider.Breakdown.HealthScore,
+			Region:      provider.Provider.Attributes["region"],
+			Tiers:       provider.Provider.Tiers,
+		})
+	}
+
+	board := &Leaderboard{
+		ComputedAt:      time.Now(),
+		RefreshDuration: time.Since(start),
+		Ranked:          len(scored),
+		Failed:          failed,
+		Weights:         criteria.Weights,
+		Entries:         entries,
+	}
+
+	s.leaderboard.mutex.Lock()
+	s.leaderboard.current = board
+	s.leaderboard.mutex.Unlock()
+
+	slog.InfoContext(ctx, "Leaderboard refreshed",
+		"ranked", board.Ranked, "unreachable", failed, "duration", board.RefreshDuration.Round(time.Second))
+	return nil
+}
+
+func (s *Service) setLeaderboardRefreshing(refreshing bool) {
+	s.leaderboard.mutex.Lock()
+	defer s.leaderboard.mutex.Unlock()
+	s.leaderboard.refreshing = refreshing
+}
@@ -0,0 +1,231 @@
+package intelligence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Returned for work requested after Close
+var ErrServiceClosed = errors.New("intelligence service is shutting down")
+
+// A periodic loop is reported stalled after this many intervals without a run
+const loopStallFactor = 3
+
+// Background loops and jobs, stopped by Close
+type lifecycle struct {
+	ctx    context.Context // cancelled by Close
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	closed bool
+	loops  map[string]*loopState
+	mutex  sync.Mutex
+}
+
+type loopState struct {
+	interval time.Duration
+	started  time.Time
+	lastRun  time.Time
+	stopped  bool
+}
+
+// A periodic background loop, as reported by readiness checks
+type BackgroundLoop struct {
+	Name     string     `json:"name"`
+	Interval string     `json:"interval"`
+	LastRun  *time.Time `json:"last_run,omitempty"`
+	Running  bool       `json:"running"`
+	Stalled  bool       `json:"stalled"`
+}
+
+// Run fn in the background until Close cancels its context. Reports false,
+// without running fn, once the service is closing.
+func (s *Service) goBackground(fn func(ctx context.Context)) bool {
+	s.lifecycle.mutex.Lock()
+	defer s.lifecycle.mutex.Unlock()
+	if s.lifecycle.closed {
+		return false
+	}
+
+	s.lifecycle.wg.Add(1)
+	go func() {
+		defer s.lifecycle.wg.Done()
+		fn(s.lifecycle.ctx)
+	}()
+	return true
+}
+
+// Run a periodic loop in the background. The loop calls loopRan after each
+// pass, so one that stops making progress shows up as stalled.
+func (s *Service) goLoop(name string, interval time.Duration, fn func(ctx context.Context)) {
+	s.lifecycle.mutex.Lock()
+	if s.lifecycle.loops == nil {
+		s.lifecycle.loops = make(map[string]*loopState)
+	}
+	state := &loopState{interval: interval, started: time.Now()}
+	s.lifecycle.loops[name] = state
+	s.lifecycle.mutex.Unlock()
+
+	s.goBackground(func(ctx context.Context) {
+		defer func() {
+			s.lifecycle.mutex.Lock()
+			state.stopped = true
+			s.lifecycle.mutex.Unlock()
+		}()
+		fn(ctx)
+	})
+}
+
+// Record a completed pass of a loop started with goLoop
+func (s *Service) loopRan(name string) {
+	s.lifecycle.mutex.Lock()
+	defer s.lifecycle.mutex.Unlock()
+	if state, ok := s.lifecycle.loops[name]; ok {
+		state.lastRun = time.Now()
+	}
+}
+
+// Get the state of every periodic background loop, by name
+func (s *Service) BackgroundLoops() []BackgroundLoop {
+	s.lifecycle.mutex.Lock()
+	defer s.lifecycle.mutex.Unlock()
+
+	now := time.Now()
+	loops := make([]BackgroundLoop, 0, len(s.lifecycle.loops))
+	for name, state := range s.lifecycle.loops {
+		loop := BackgroundLoop{Name: name, Interval: state.interval.String(), Running: !state.stopped}
+		since := state.started
+		if !state.lastRun.IsZero() {
+			lastRun := state.lastRun
+			loop.LastRun = &lastRun
+			since = lastRun
+		}
+		loop.Stalled = loop.Running && now.Sub(since) > loopStallFactor*state.interval
+		loops = append(loops, loop)
+	}
+	sort.Slice(loops, func(i, j int) bool { return loops[i].Name < loops[j].Name })
+	return loops
+}
+
+// Whether Close has been called
+func (s *Service) Closing() bool {
+	s.lifecycle.mutex.Lock()
+	defer s.lifecycle.mutex.Unlock()
+	return s.lifecycle.closed
+}
+
+// Stop the background loops and any cache rebuild, waiting for them until ctx
+// is done, close the Akash gRPC connection, then save the cache when
+// Config.CacheFile is set. The cache is saved even when the wait times out, so
+// a slow shutdown keeps what was fetched.
+func (s *Service) Close(ctx context.Context) error {
+	s.lifecycle.mutex.Lock()
+	if s.lifecycle.closed {
+		s.lifecycle.mutex.Unlock()
+		return nil
+	}
+	s.lifecycle.closed = true
+	s.lifecycle.mutex.Unlock()
+	s.lifecycle.cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.lifecycle.wg.Wait()
+		close(stopped)
+	}()
+
+	var err error
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		err = fmt.Errorf("background work still running: %w", ctx.Err())
+	}
+	if closeErr := s.akashClient.Close(); closeErr != nil {
+		slog.Warn("Failed to close Akash gRPC connection", "error", closeErr)
+	}
+
+	if s.config.CacheFile != "" {
+		saved, saveErr := s.saveCache(s.config.CacheFile)
+		if saveErr != nil {
+			return errors.Join(err, saveErr)
+		}
+		slog.Info("Provider cache saved", "path", s.config.CacheFile, "providers", saved)
+	}
+	return err
+}
+
+// On-disk form of the provider cache
+type cacheSnapshot struct {
+	SavedAt   time.Time                  `json:"saved_at"`
+	Providers map[string]*CachedProvider `json:"providers"`
+}
+
+// Write the unexpired cache entries to path, replacing it atomically
+func (s *Service) saveCache(path string) (int, error) {
+	snapshot := cacheSnapshot{SavedAt: time.Now(), Providers: make(map[string]*CachedProvider)}
+	s.cache.mutex.RLock()
+	for addr, cached := range s.cache.data {
+		if snapshot.SavedAt.Before(cached.ExpiresAt) {
+			snapshot.Providers[addr] = cached
+		}
+	}
+	data, err := json.Marshal(snapshot)
+	s.cache.mutex.RUnlock()
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode provider cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to save provider cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return 0, fmt.Errorf("failed to save provider cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, fmt.Errorf("failed to save provider cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, fmt.Errorf("failed to save provider cache: %w", err)
+	}
+	return len(snapshot.Providers), nil
+}
+
+// Warm the cache from a file written by saveCache, skipping entries that have
+// expired since. A missing file is not an error.
+func (s *Service) loadCache(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read provider cache: %w", err)
+	}
+
+	var snapshot cacheSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return 0, fmt.Errorf("failed to decode provider cache: %w", err)
+	}
+
+	now := time.Now()
+	loaded := 0
+	s.cache.mutex.Lock()
+	defer s.cache.mutex.Unlock()
+	for addr, cached := range snapshot.Providers {
+		if cached == nil || cached.Info == nil || cached.Info.Address != addr || !now.Before(cached.ExpiresAt) {
+			continue
+		}
+		s.cache.data[addr] = cached
+		loaded++
+	}
+	return loaded, nil
+}
@@ -0,0 +1,44 @@
+package intelligence
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Providers registered on chain, optionally narrowed to audited providers
+type ProviderListing struct {
+	Providers   []*akash.ListedProvider `json:"providers"`
+	Count       int                     `json:"count"`
+	AuditedOnly bool                    `json:"audited_only"`
+
+	// Set when only a page of the listing is returned; Count is the full total
+	Truncation *Truncation `json:"truncation,omitempty"`
+
+	Degraded                bool                    `json:"degraded"`
+	UnavailableCapabilities []UnavailableCapability `json:"unavailable_capabilities,omitempty"`
+}
+
+// List providers registered on chain, one page at a time for large networks
+func (s *Service) ListProviders(ctx context.Context, auditedOnly bool, page Page) (*ProviderListing, error) {
+	ctx, degraded := withDegradation(ctx)
+
+	list, err := s.akashClient.ListProviders(ctx, akash.ListProvidersOptions{AuditedOnly: auditedOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list providers: %w", err)
+	}
+
+	if len(list.Warnings) > 0 {
+		reportDegraded(ctx, "audit_filter", strings.Join(list.Warnings, "; "))
+	}
+
+	listing := &ProviderListing{
+		Count:       len(list.Providers),
+		AuditedOnly: auditedOnly && len(list.Warnings) == 0,
+	}
+	listing.Providers, listing.Truncation = paginate(s, list.Providers, page)
+	listing.Degraded, listing.UnavailableCapabilities = degraded.report()
+	return listing, nil
+}
@@ -0,0 +1,68 @@
+package intelligence
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// How providers with imminent maintenance are treated
+const (
+	MaintenancePolicyPenalize = "penalize" // subtract MaintenancePenalty from the score (default)
+	MaintenancePolicyExclude  = "exclude"  // remove from the candidate set
+)
+
+const (
+	defaultMaintenanceLookahead = 24 * time.Hour
+	defaultMaintenancePenalty   = 0.2
+)
+
+// First advertised maintenance window that is ongoing or starts within the look-ahead
+func (s *Service) imminentMaintenance(provider *akash.ProviderInfo, now time.Time) *akash.MaintenanceWindow {
+	lookahead := s.config.MaintenanceLookahead
+	if lookahead <= 0 {
+		lookahead = defaultMaintenanceLookahead
+	}
+
+	for _, window := range provider.Maintenance {
+		if window.Overlaps(now, now.Add(lookahead)) {
+			return &window
+		}
+	}
+	return nil
+}
+
+// Score penalty for imminent maintenance; providers that advertise none are neutral
+func (s *Service) maintenancePenalty(provider *akash.ProviderInfo, now time.Time) float64 {
+	if s.config.MaintenancePolicy == MaintenancePolicyExclude || s.imminentMaintenance(provider, now) == nil {
+		return 0
+	}
+	if s.config.MaintenancePenalty > 0 {
+		return s.config.MaintenancePenalty
+	}
+	return defaultMaintenancePenalty
+}
+
+// Drop providers with imminent maintenance when the exclude policy is configured
+func (s *Service) filterByMaintenance(providers []*akash.ProviderInfo, now time.Time) ([]*akash.ProviderInfo, []FilteredProvider) {
+	if s.config.MaintenancePolicy != MaintenancePolicyExclude {
+		return providers, nil
+	}
+
+	kept := make([]*akash.ProviderInfo, 0, len(providers))
+	var filtered []FilteredProvider
+	for _, provider := range providers {
+		if window := s.imminentMaintenance(provider, now); window != nil {
+			filtered = append(filtered, FilteredProvider{
+				Address: provider.Address,
+				Reason: fmt.Sprintf("maintenance scheduled %s to %s",
+					window.Start.Format(time.RFC3339), window.End.Format(time.RFC3339)),
+			})
+			continue
+		}
+		kept = append(kept, provider)
+	}
+
+	return kept, filtered
+}
@@ -0,0 +1,244 @@
+package intelligence
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+const (
+	// Fewest prices a percentile is computed from
+	minPricePoints = 3
+
+	// Median changes below this percentage count as stable
+	stablePricePercent = 2.0
+)
+
+// Advertised unit prices, in micro-denom per unit per 30-day month
+var pricedResources = []struct {
+	name string
+	unit string
+	rate func(*akash.PricingPolicy) float64
+}{
+	{"cpu", "core-month", func(p *akash.PricingPolicy) float64 { return p.CPUPerCoreMonth }},
+	{"memory", "GiB-month", func(p *akash.PricingPolicy) float64 { return p.MemoryPerGiBMonth }},
+	{"storage", "GiB-month", func(p *akash.PricingPolicy) float64 { return p.StoragePerGiBMonth }},
+	{"gpu", "GPU-month", func(p *akash.PricingPolicy) float64 { return p.GPUPerUnitMonth }},
+}
+
+// Spread of advertised prices, one point per provider
+type PricePercentiles struct {
+	P10        float64 `json:"p10"`
+	P50        float64 `json:"p50"`
+	P90        float64 `json:"p90"`
+	DataPoints int     `json:"data_points"`
+}
+
+// Price distribution of one resource in one denom over the timeframe
+type ResourcePriceTrend struct {
+	Denom       string            `json:"denom,omitempty"`
+	Unit        string            `json:"unit"`
+	Percentiles *PricePercentiles `json:"percentiles,omitempty"` // each provider's latest price in the timeframe
+
+	// Median of the second half of the timeframe against the first
+	Direction     string   `json:"direction,omitempty"` // rising, falling or stable
+	ChangePercent *float64 `json:"change_percent,omitempty"`
+	EarlierPoints int      `json:"earlier_points"`
+	LaterPoints   int      `json:"later_points"`
+
+	InsufficientData bool   `json:"insufficient_data,omitempty"`
+	Note             string `json:"note,omitempty"`
+}
+
+// Network pricing over a timeframe, from advertised prices in the historical store
+type MarketTrends struct {
+	Timeframe         time.Duration                   `json:"timeframe"`
+	Since             time.Time                       `json:"since"`
+	Until             time.Time                       `json:"until"`
+	ProvidersObserved int                             `json:"providers_observed"`
+	ProvidersPricing  int                             `json:"providers_pricing"` // advertised a usable price
+	Prices            map[string][]ResourcePriceTrend `json:"prices"`            // cpu, memory, storage, gpu; one entry per denom
+}
+
+// One provider's price for a resource
+type pricePoint struct {
+	denom string
+	rate  float64
+}
+
+// Compute price percentiles and trend per resource over the timeframe ending
+// now. Every provider counts once per percentile (its latest price), so a
+// provider observed often doesn't outweigh one observed rarely.
+func (s *Service) GetMarketTrends(timeframe time.Duration) (*MarketTrends, error) {
+	if s.history == nil {
+		return nil, fmt.Errorf("historical store is disabled")
+	}
+	if timeframe <= 0 {
+		return nil, fmt.Errorf("timeframe must be positive")
+	}
+
+	until := time.Now()
+	since := until.Add(-timeframe)
+	midpoint := since.Add(timeframe / 2)
+
+	trends := &MarketTrends{
+		Timeframe: timeframe,
+		Since:     since,
+		Until:     until,
+		Prices:    make(map[string][]ResourcePriceTrend),
+	}
+
+	// Latest price per provider over the whole timeframe and each half of it
+	latest := make(map[string][]pricePoint)
+	earlier := make(map[string][]pricePoint)
+	later := make(map[string][]pricePoint)
+	for _, address := range s.history.Addresses() {
+		samples := s.history.Samples(address, since, until)
+		if len(samples) == 0 {
+			continue
+		}
+		trends.ProvidersObserved++
+
+		pricing := false
+		for _, resource := range pricedResources {
+			var overall, first, second *pricePoint
+			for _, sample := range samples {
+				point, ok := priceOf(sample, resource.rate)
+				if !ok {
+					continue
+				}
+				overall = &point
+				if sample.ObservedAt.Before(midpoint) {
+					first = &point
+				} else {
+					second = &point
+				}
+			}
+			if overall == nil {
+				continue
+			}
+			pricing = true
+			latest[resource.name] = append(latest[resource.name], *overall)
+			if first != nil {
+				earlier[resource.name] = append(earlier[resource.name], *first)
+			}
+			if second != nil {
+				later[resource.name] = append(later[resource.name], *second)
+			}
+		}
+		if pricing {
+			trends.ProvidersPricing++
+		}
+	}
+
+	for _, resource := range pricedResources {
+		points := latest[resource.name]
+		if len(points) == 0 {
+			trends.Prices[resource.name] = []ResourcePriceTrend{{
+				Unit:             resource.unit,
+				InsufficientData: true,
+				Note:             fmt.Sprintf("no provider advertised %s pricing in the last %v", resource.name, timeframe),
+			}}
+			continue
+		}
+
+		// Prices in different denoms are not comparable
+		for _, denom := range priceDenoms(points) {
+			trends.Prices[resource.name] = append(trends.Prices[resource.name],
+				priceTrend(resource.unit, denom, ratesIn(points, denom), ratesIn(earlier[resource.name], denom), ratesIn(later[resource.name], denom)))
+		}
+	}
+
+	return trends, nil
+}
+
+func priceTrend(unit, denom string, rates, earlier, later []float64) ResourcePriceTrend {
+	trend := ResourcePriceTrend{
+		Denom:         denom,
+		Unit:          unit,
+		EarlierPoints: len(earlier),
+		LaterPoints:   len(later),
+	}
+
+	if len(rates) < minPricePoints {
+		trend.InsufficientData = true
+		trend.Note = fmt.Sprintf("%d providers priced in %s; at least %d are needed for percentiles", len(rates), denom, minPricePoints)
+		return trend
+	}
+	trend.Percentiles = &PricePercentiles{
+		P10:        percentile(rates, 0.1),
+		P50:        percentile(rates, 0.5),
+		P90:        percentile(rates, 0.9),
+		DataPoints: len(rates),
+	}
+
+	if len(earlier) < minPricePoints || len(later) < minPricePoints {
+		trend.InsufficientData = true
+		trend.Note = fmt.Sprintf("%d and %d prices in the earlier and later half of the timeframe; at least %d each are needed for a trend",
+			len(earlier), len(later), minPricePoints)
+		return trend
+	}
+
+	start, end := percentile(earlier, 0.5), percentile(later, 0.5)
+	trend.Direction = "stable"
+	if start > 0 {
+		change := math.Round((end-start)/start*10000) / 100
+		trend.ChangePercent = &change
+		switch {
+		case change >= stablePricePercent:
+			trend.Direction = "rising"
+		case change <= -stablePricePercent:
+			trend.Direction = "falling"
+		}
+	}
+	return trend
+}
+
+// A sample's advertised price for a resource, if it had one
+func priceOf(sample ProviderSnapshot, rate func(*akash.PricingPolicy) float64) (pricePoint, bool) {
+	if sample.Info == nil || sample.Info.Pricing == nil || sample.Info.Pricing.Denom == "" {
+		return pricePoint{}, false
+	}
+	value := rate(sample.Info.Pricing)
+	if value <= 0 {
+		return pricePoint{}, false
+	}
+	return pricePoint{denom: sample.Info.Pricing.Denom, rate: value}, true
+}
+
+func priceDenoms(points []pricePoint) []string {
+	seen := make(map[string]bool)
+	var denoms []string
+	for _, point := range points {
+		if !seen[point.denom] {
+			seen[point.denom] = true
+			denoms = append(denoms, point.denom)
+		}
+	}
+	sort.Strings(denoms)
+	return denoms
+}
+
+func ratesIn(points []pricePoint, denom string) []float64 {
+	var rates []float64
+	for _, point := range points {
+		if point.denom == denom {
+			rates = append(rates, point.rate)
+		}
+	}
+	return rates
+}
+
+// Percentile by linear interpolation between closest ranks
+func percentile(values []float64, q float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	rank := q * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
@@ -0,0 +1,190 @@
+package intelligence
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Attributes providers use to advertise network capacity, in order of preference
+var (
+	bandwidthAttributes   = []string{"network/bandwidth", "network-bandwidth", "bandwidth"}
+	networkTierAttributes = []string{"network/tier", "network-tier"}
+)
+
+// Scores of advertised network tiers, used when no bandwidth is advertised
+var networkTierScores = map[string]float64{
+	"premium":  1,
+	"high":     0.8,
+	"standard": 0.6,
+	"basic":    0.3,
+}
+
+const (
+	// Advertised bandwidth at or below min scores 0, at or above max scores 1,
+	// on a log scale in between
+	minScoredBandwidthMbps = 10
+	maxScoredBandwidthMbps = 10000
+
+	// Status latency only hints at network quality, so the proxy stays close to neutral
+	neutralNetworkScore = 0.5
+	latencyProxySpread  = 0.1
+	latencyProxyFast    = 300 * time.Millisecond
+	latencyProxySlow    = 2 * time.Second
+)
+
+// Sources of a network quality assessment
+const (
+	NetworkSourceAdvertised   = "advertised"
+	NetworkSourceLatencyProxy = "latency_proxy"
+	NetworkSourceNone         = "none"
+)
+
+// What is known about a provider's network
+type NetworkQuality struct {
+	BandwidthMbps *float64 `json:"bandwidth_mbps,omitempty"`
+	Tier          string   `json:"tier,omitempty"`
+	Source        string   `json:"source"`
+}
+
+// Read advertised network capacity from provider attributes
+func assessNetwork(provider *akash.ProviderInfo) *NetworkQuality {
+	quality := &NetworkQuality{Source: NetworkSourceNone}
+
+	for _, key := range bandwidthAttributes {
+		if value, ok := provider.Attributes[key]; ok {
+			if mbps, err := ParseBandwidth(value); err == nil {
+				quality.BandwidthMbps = &mbps
+				quality.Source = NetworkSourceAdvertised
+				break
+			}
+		}
+	}
+	for _, key := range networkTierAttributes {
+		if value, ok := provider.Attributes[key]; ok {
+			tier := strings.ToLower(strings.TrimSpace(value))
+			if _, known := networkTierScores[tier]; known {
+				quality.Tier = tier
+				quality.Source = NetworkSourceAdvertised
+				break
+			}
+		}
+	}
+
+	if quality.Source == NetworkSourceNone && provider.StatusQueryTime > 0 && provider.Error == "" {
+		quality.Source = NetworkSourceLatencyProxy
+	}
+	return quality
+}
+
+// Score network quality between 0 and 1: advertised bandwidth, else advertised
+// tier, else a weak status-latency proxy, else neutral
+func networkScore(provider *akash.ProviderInfo, quality *NetworkQuality) float64 {
+	if quality.BandwidthMbps != nil {
+		mbps := *quality.BandwidthMbps
+		switch {
+		case mbps <= minScoredBandwidthMbps:
+			return 0
+		case mbps >= maxScoredBandwidthMbps:
+			return 1
+		}
+		return math.Log10(mbps/minScoredBandwidthMbps) / math.Log10(maxScoredBandwidthMbps/minScoredBandwidthMbps)
+	}
+	if quality.Tier != "" {
+		return networkTierScores[quality.Tier]
+	}
+	if quality.Source == NetworkSourceLatencyProxy {
+		latency := provider.StatusQueryTime
+		switch {
+		case latency <= latencyProxyFast:
+			return neutralNetworkScore + latencyProxySpread
+		case latency >= latencyProxySlow:
+			return neutralNetworkScore - latencyProxySpread
+		}
+		position := float64(latency-latencyProxyFast) / float64(latencyProxySlow-latencyProxyFast)
+		return neutralNetworkScore + latencyProxySpread*(1-2*position)
+	}
+	return neutralNetworkScore
+}
+
+// ParseBandwidth parses a bandwidth such as "1Gbps", "500 Mbps" or a bare
+// number of Mbps, returning Mbps
+func ParseBandwidth(value string) (float64, error) {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(value), " ", ""))
+	normalized = strings.TrimSuffix(strings.TrimSuffix(normalized, "ps"), "/s")
+
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(normalized, "tb"):
+		multiplier, normalized = 1e6, strings.TrimSuffix(normalized, "tb")
+	case strings.HasSuffix(normalized, "gb"):
+		multiplier, normalized = 1e3, strings.TrimSuffix(normalized, "gb")
+	case strings.HasSuffix(normalized, "mb"):
+		normalized = strings.TrimSuffix(normalized, "mb")
+	case strings.HasSuffix(normalized, "kb"):
+		multiplier, normalized = 1e-3, strings.TrimSuffix(normalized, "kb")
+	}
+
+	amount, err := strconv.ParseFloat(normalized, 64)
+	if err != nil || amount < 0 {
+		return 0, fmt.Errorf("invalid bandwidth %q", value)
+	}
+	return amount * multiplier, nil
+}
+
+// Drop providers that don't advertise at least the required bandwidth.
+// Unadvertised bandwidth can't satisfy a hard requirement.
+func filterByBandwidth(providers []*akash.ProviderInfo, minMbps float64) ([]*akash.ProviderInfo, []FilteredProvider) {
+	if minMbps <= 0 {
+		return providers, nil
+	}
+
+	kept := make([]*akash.ProviderInfo, 0, len(providers))
+	var filtered []FilteredProvider
+	for _, provider := range providers {
+		quality := assessNetwork(provider)
+		switch {
+		case quality.BandwidthMbps == nil:
+			filtered = append(filtered, FilteredProvider{
+				Address: provider.Address,
+				Reason:  "bandwidth not advertised",
+			})
+		case *quality.BandwidthMbps < minMbps:
+			filtered = append(filtered, FilteredProvider{
+				Address: provider.Address,
+				Reason:  fmt.Sprintf("%s advertised, %s required", formatBandwidth(*quality.BandwidthMbps), formatBandwidth(minMbps)),
+			})
+		default:
+			kept = append(kept, provider)
+		}
+	}
+
+	return kept, filtered
+}
+
+// Format Mbps for reasoning and filter reasons
+func formatBandwidth(mbps float64) string {
+	if mbps >= 1000 {
+		return strconv.FormatFloat(mbps/1000, 'f', -1, 64) + "Gbps"
+	}
+	return strconv.FormatFloat(mbps, 'f', -1, 64) + "Mbps"
+}
+
+// Describe a network assessment for reasoning
+func describeNetwork(quality *NetworkQuality) string {
+	switch {
+	case quality.BandwidthMbps != nil && quality.Tier != "":
+		return fmt.Sprintf("%s advertised bandwidth (%s tier)", formatBandwidth(*quality.BandwidthMbps), quality.Tier)
+	case quality.BandwidthMbps != nil:
+		return fmt.Sprintf("%s advertised bandwidth", formatBandwidth(*quality.BandwidthMbps))
+	case quality.Tier != "":
+		return fmt.Sprintf("%s network tier advertised", quality.Tier)
+	case quality.Source == NetworkSourceLatencyProxy:
+		return "No network capacity advertised - scored from status latency as a weak proxy"
+	}
+	return "No network capacity advertised - network scored neutral"
+}
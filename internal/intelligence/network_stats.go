@@ -0,0 +1,175 @@
+package intelligence
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+const defaultNetworkSeriesPoints = 200
+
+// Network-wide aggregates at a point in time
+type NetworkStatsSample struct {
+	ObservedAt time.Time `json:"observed_at"`
+	akash.NetworkAggregate
+	Samples int `json:"samples"` // raw samples merged into this point
+}
+
+// Network stats over a time window, downsampled to at most MaxPoints
+type NetworkStatsSeries struct {
+	Since       time.Time            `json:"since"`
+	Until       time.Time            `json:"until"`
+	Resolution  time.Duration        `json:"resolution,omitempty"`
+	RawSamples  int                  `json:"raw_samples"`
+	Downsampled bool                 `json:"downsampled"`
+	Points      []NetworkStatsSample `json:"points"`
+}
+
+// Periodically sample network-wide aggregates into the historical store
+func (s *Service) networkStatsLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.config.NetworkStatsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		sampleCtx, cancel := context.WithTimeout(ctx, s.config.NetworkStatsInterval)
+		if err := s.snapshotNetworkStats(sampleCtx); err != nil && ctx.Err() == nil {
+			slog.Warn("Network stats snapshot failed", "error", err)
+		}
+		cancel()
+		s.loopRan("network_stats")
+	}
+}
+
+// Take a single network-wide stats sample
+func (s *Service) snapshotNetworkStats(ctx context.Context) error {
+	list, err := s.akashClient.ListProviders(ctx, akash.ListProvidersOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list providers: %w", err)
+	}
+
+	addresses := make([]string, 0, len(list.Providers))
+	for _, provider := range list.Providers {
+		addresses = append(addresses, provider.Address)
+	}
+
+	intel, err := s.GetProviderIntelligence(ctx, addresses)
+	if err != nil {
+		return fmt.Errorf("failed to get provider intelligence: %w", err)
+	}
+
+	sample := NetworkStatsSample{
+		ObservedAt:       time.Now(),
+		NetworkAggregate: s.akashClient.GetNetworkAggregate(intel.Providers),
+		Samples:          1,
+	}
+	s.history.RecordNetwork(sample)
+
+	slog.InfoContext(ctx, "Network stats sampled",
+		"providers", sample.TotalProviders, "unreachable", len(intel.FailedProviders), "gpus_available", sample.AvailableResources.GPU)
+	return nil
+}
+
+// Get network-wide stats for the window ending now, downsampled to at most maxPoints
+func (s *Service) GetNetworkStatsSeries(window time.Duration, maxPoints int) (*NetworkStatsSeries, error) {
+	if s.history == nil {
+		return nil, fmt.Errorf("historical store is disabled")
+	}
+	if s.config.NetworkStatsInterval <= 0 {
+		return nil, fmt.Errorf("network stats sampling is disabled")
+	}
+	if window <= 0 {
+		return nil, fmt.Errorf("window must be positive")
+	}
+	if maxPoints <= 0 {
+		maxPoints = defaultNetworkSeriesPoints
+	}
+
+	until := time.Now()
+	since := until.Add(-window)
+	samples := s.history.NetworkSamples(since, until)
+
+	series := &NetworkStatsSeries{
+		Since:      since,
+		Until:      until,
+		RawSamples: len(samples),
+		Points:     samples,
+	}
+	if len(samples) > maxPoints {
+		series.Resolution = window / time.Duration(maxPoints)
+		series.Points = downsampleNetworkStats(samples, since, series.Resolution)
+		series.Downsampled = true
+	}
+
+	return series, nil
+}
+
+// Average samples into fixed-width time buckets, dropping empty buckets
+func downsampleNetworkStats(samples []NetworkStatsSample, since time.Time, resolution time.Duration) []NetworkStatsSample {
+	var points []NetworkStatsSample
+
+	for start := 0; start < len(samples); {
+		bucket := samples[start].ObservedAt.Sub(since) / resolution
+		end := start + 1
+		for end < len(samples) && samples[end].ObservedAt.Sub(since)/resolution == bucket {
+			end++
+		}
+		points = append(points, averageNetworkStats(samples[start:end], since.Add(bucket*resolution)))
+		start = end
+	}
+
+	return points
+}
+
+// Average a group of samples into a single point
+func averageNetworkStats(samples []NetworkStatsSample, at time.Time) NetworkStatsSample {
+	var totalProviders, healthy, withGPU, leases float64
+	var health float64
+	var total, available [4]float64
+
+	for _, sample := range samples {
+		totalProviders += float64(sample.TotalProviders)
+		healthy += float64(sample.HealthyProviders)
+		withGPU += float64(sample.ProvidersWithGPU)
+		leases += float64(sample.TotalActiveLeases)
+		health += sample.AverageHealthScore
+		total = addResources(total, sample.TotalResources)
+		available = addResources(available, sample.AvailableResources)
+	}
+
+	n := float64(len(samples))
+	return NetworkStatsSample{
+		ObservedAt: at,
+		NetworkAggregate: akash.NetworkAggregate{
+			TotalProviders:     int(math.Round(totalProviders / n)),
+			HealthyProviders:   int(math.Round(healthy / n)),
+			ProvidersWithGPU:   int(math.Round(withGPU / n)),
+			TotalActiveLeases:  int(math.Round(leases / n)),
+			AverageHealthScore: health / n,
+			TotalResources:     averageResources(total, n),
+			AvailableResources: averageResources(available, n),
+		},
+		Samples: len(samples),
+	}
+}
+
+func addResources(sum [4]float64, r akash.ResourceSummary) [4]float64 {
+	return [4]float64{sum[0] + float64(r.CPU), sum[1] + float64(r.Memory), sum[2] + float64(r.Storage), sum[3] + float64(r.GPU)}
+}
+
+func averageResources(sum [4]float64, n float64) akash.ResourceSummary {
+	return akash.ResourceSummary{
+		CPU:     int64(math.Round(sum[0] / n)),
+		Memory:  int64(math.Round(sum[1] / n)),
+		Storage: int64(math.Round(sum[2] / n)),
+		GPU:     int(math.Round(sum[3] / n)),
+	}
+}
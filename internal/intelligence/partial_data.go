@@ -0,0 +1,54 @@
+package intelligence
+
+import (
+	"fmt"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// How dimensions are scored when the provider's status data is missing
+const (
+	PartialDataPenalize = "penalize" // score PartialDataScore (default 0)
+	PartialDataNeutral  = "neutral"  // score 0.5, neither rewarding nor punishing the gap
+)
+
+const neutralPartialDataScore = 0.5
+
+// Validate the partial-data policy and its penalty score
+func ValidatePartialDataPolicy(policy string, score float64) error {
+	switch policy {
+	case "", PartialDataPenalize, PartialDataNeutral:
+	default:
+		return fmt.Errorf("unknown partial data policy %q (valid: %s, %s)", policy, PartialDataPenalize, PartialDataNeutral)
+	}
+	if score < 0 || score > 1 {
+		return fmt.Errorf("partial data score must be between 0 and 1, got %v", score)
+	}
+	return nil
+}
+
+// Whether the provider's status endpoint returned cluster data
+func hasStatusData(provider *akash.ProviderInfo) bool {
+	return provider.ClusterInfo != nil
+}
+
+// Score given to a dimension whose inputs are missing
+func (s *Service) missingDataScore() float64 {
+	if s.config.PartialDataPolicy == PartialDataNeutral {
+		return neutralPartialDataScore
+	}
+	return s.config.PartialDataScore
+}
+
+// Dimensions scored without status data. Reliability falls back to the partial
+// health score; performance and heuristic pricing follow the partial-data policy.
+func missingDataDimensions(provider *akash.ProviderInfo, priceSource string) []string {
+	if hasStatusData(provider) {
+		return nil
+	}
+	missing := []string{"reliability", "performance"}
+	if priceSource == PriceSourceHeuristic {
+		missing = append(missing, "price")
+	}
+	return missing
+}
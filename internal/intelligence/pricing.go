@@ -0,0 +1,64 @@
+package intelligence
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Where a provider's price score came from
+const (
+	PriceSourceAdvertised = "advertised"
+	PriceSourceHeuristic  = "heuristic"
+)
+
+// Cheapest advertised reference cost among the candidates, per denom
+func advertisedPriceReferences(providers []*akash.ProviderInfo) map[string]float64 {
+	references := make(map[string]float64)
+	for _, provider := range providers {
+		cost, ok := provider.Pricing.ReferenceMonthlyCost()
+		if !ok {
+			continue
+		}
+		if cheapest, seen := references[provider.Pricing.Denom]; !seen || cost < cheapest {
+			references[provider.Pricing.Denom] = cost
+		}
+	}
+	return references
+}
+
+// Score advertised pricing relative to the cheapest candidate in the same denom,
+// so the cheapest scores 1 and a provider twice as expensive scores 0.5
+func advertisedPriceScore(provider *akash.ProviderInfo, criteria SelectionCriteria) (float64, bool) {
+	cost, ok := provider.Pricing.ReferenceMonthlyCost()
+	if !ok {
+		return 0, false
+	}
+	cheapest, ok := criteria.pricingReferences[provider.Pricing.Denom]
+	if !ok {
+		return 0, false
+	}
+	if cost == 0 {
+		return 1, true
+	}
+	return cheapest / cost, true
+}
+
+// Describe advertised pricing for the reasoning
+func describePricing(pricing *akash.PricingPolicy) string {
+	var parts []string
+	if pricing.CPUPerCoreMonth > 0 {
+		parts = append(parts, fmt.Sprintf("CPU %.0f %s/core", pricing.CPUPerCoreMonth, pricing.Denom))
+	}
+	if pricing.MemoryPerGiBMonth > 0 {
+		parts = append(parts, fmt.Sprintf("memory %.0f %s/GiB", pricing.MemoryPerGiBMonth, pricing.Denom))
+	}
+	if pricing.StoragePerGiBMonth > 0 {
+		parts = append(parts, fmt.Sprintf("storage %.0f %s/GiB", pricing.StoragePerGiBMonth, pricing.Denom))
+	}
+	if pricing.GPUPerUnitMonth > 0 {
+		parts = append(parts, fmt.Sprintf("GPU %.0f %s/unit", pricing.GPUPerUnitMonth, pricing.Denom))
+	}
+	return strings.Join(parts, ", ") + " per month"
+}
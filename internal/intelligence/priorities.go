@@ -0,0 +1,109 @@
+package intelligence
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Priorities compared by SelectAcrossPriorities
+var selectionPriorities = []string{"cost", "performance", "reliability"}
+
+// Winner under a single priority
+type PriorityPick struct {
+	Priority         string               `json:"priority"`
+	SelectedProvider string               `json:"selected_provider"`
+	Score            float64              `json:"score"`
+	Confidence       *SelectionConfidence `json:"confidence"`
+	Reasoning        string               `json:"reasoning"`
+	Error            string               `json:"error,omitempty"`
+}
+
+// Selections for every priority over the same candidate set
+type PrioritySelections struct {
+	Picks        []PriorityPick        `json:"picks"`
+	Differ       bool                  `json:"differ"` // the priorities do not agree on a winner
+	Summary      string                `json:"summary"`
+	AllProviders []*akash.ProviderInfo `json:"all_providers"`
+	// Set when all_providers was cut to the response limit
+	AllProvidersTruncation *Truncation             `json:"all_providers_truncation,omitempty"`
+	FailedProviders        []*akash.FailedProvider `json:"failed_providers"`
+	ExcludedProviders      []string                `json:"excluded_providers,omitempty"`
+	ScoringCap             *ScoringCap             `json:"scoring_cap,omitempty"`
+	Criteria               SelectionCriteria       `json:"criteria"`
+	QueryTime              time.Duration           `json:"query_time"`
+
+	Degraded                bool                    `json:"degraded"`
+	UnavailableCapabilities []UnavailableCapability `json:"unavailable_capabilities,omitempty"`
+	Freshness               *FreshnessGuarantee     `json:"freshness,omitempty"`
+}
+
+// Run selection under each priority against one fetch of the candidates' intelligence
+func (s *Service) SelectAcrossPriorities(ctx context.Context, addresses []string, criteria SelectionCriteria) (*PrioritySelections, error) {
+	start := time.Now()
+	ctx, degraded := withDegradation(ctx)
+
+	intel, excluded, capped, err := s.fetchCandidates(ctx, addresses, criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	allProviders, allTruncation := capProviders(s, intel.Providers, selectionTruncationHint)
+	result := &PrioritySelections{
+		AllProviders:           allProviders,
+		AllProvidersTruncation: allTruncation,
+		FailedProviders:        intel.FailedProviders,
+		ExcludedProviders:      excluded,
+		ScoringCap:             capped,
+		Criteria:               criteria,
+		Freshness:              intel.Freshness,
+	}
+
+	winners := make(map[string][]string)
+	var order []string
+	for _, priority := range selectionPriorities {
+		prioritized := criteria
+		prioritized.Priority = priority
+
+		pick := PriorityPick{Priority: priority}
+		selection, err := s.selectFrom(ctx, intel.Providers, intel.FailedProviders, prioritized)
+		if err != nil {
+			pick.Error = err.Error()
+			result.Picks = append(result.Picks, pick)
+			continue
+		}
+
+		pick.SelectedProvider = selection.SelectedProvider
+		pick.Score = selection.Score
+		pick.Confidence = selection.Confidence
+		pick.Reasoning = selection.Reasoning
+		result.Picks = append(result.Picks, pick)
+
+		if _, seen := winners[pick.SelectedProvider]; !seen {
+			order = append(order, pick.SelectedProvider)
+		}
+		winners[pick.SelectedProvider] = append(winners[pick.SelectedProvider], priority)
+	}
+
+	if len(winners) == 0 {
+		return nil, fmt.Errorf("no provider could be selected under any priority: %s", result.Picks[0].Error)
+	}
+
+	result.Differ = len(winners) > 1
+	if result.Differ {
+		var parts []string
+		for _, addr := range order {
+			parts = append(parts, fmt.Sprintf("%s for %s", addr, strings.Join(winners[addr], "/")))
+		}
+		result.Summary = "Priorities disagree: " + strings.Join(parts, "; ")
+	} else {
+		result.Summary = fmt.Sprintf("%s wins under every priority", order[0])
+	}
+
+	result.QueryTime = time.Since(start)
+	result.Degraded, result.UnavailableCapabilities = degraded.report()
+	return result, nil
+}
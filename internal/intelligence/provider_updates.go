@@ -0,0 +1,230 @@
+package intelligence
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+const (
+	// Health score movement that counts as a change when not configured
+	defaultSubscriptionHealthDelta = 0.05
+
+	// Updates buffered per subscriber; a subscriber that falls further behind misses updates
+	subscriptionBuffer = 64
+)
+
+// A change to a watched provider's health or availability, seen when its data
+// was refreshed
+type ProviderUpdate struct {
+	Address    string    `json:"address"`
+	ObservedAt time.Time `json:"observed_at"`
+	Changes    []string  `json:"changes"` // health_score, online, available_gpu, available_nodes
+
+	HealthScore         float64 `json:"health_score"`
+	PreviousHealthScore float64 `json:"previous_health_score"`
+	Online              bool    `json:"online"` // status endpoint answered
+	AvailableGPU        int     `json:"available_gpu"`
+	AvailableNodes      int     `json:"available_nodes"`
+}
+
+// The parts of a provider that updates are raised for
+type providerState struct {
+	healthScore    float64
+	online         bool
+	availableGPU   int
+	availableNodes int
+}
+
+func stateOf(info *akash.ProviderInfo) providerState {
+	state := providerState{healthScore: info.HealthScore}
+	if info.ClusterInfo != nil {
+		state.online = true
+		state.availableGPU = info.ClusterInfo.AvailableResources.GPU
+		state.availableNodes = info.ClusterInfo.AvailableNodes
+	}
+	return state
+}
+
+// Subscribers and the last seen state of every provider they may watch
+type updateFeed struct {
+	subscribers map[*Subscription]struct{}
+	last        map[string]providerState
+	mutex       sync.Mutex
+}
+
+// A subscriber's set of watched providers and its stream of updates
+type Subscription struct {
+	service   *Service
+	updates   chan ProviderUpdate
+	addresses map[string]bool
+	closed    bool
+}
+
+// Subscribe to updates for providers added with Watch. Close it when done.
+func (s *Service) Subscribe() *Subscription {
+	sub := &Subscription{
+		service:   s,
+		updates:   make(chan ProviderUpdate, subscriptionBuffer),
+		addresses: make(map[string]bool),
+	}
+
+	s.updates.mutex.Lock()
+	s.updates.subscribers[sub] = struct{}{}
+	s.updates.mutex.Unlock()
+	return sub
+}
+
+// Updates for the watched providers; closed by Close
+func (sub *Subscription) Updates() <-chan ProviderUpdate {
+	return sub.updates
+}
+
+// Start watching providers
+func (sub *Subscription) Watch(addresses []string) {
+	sub.service.updates.mutex.Lock()
+	defer sub.service.updates.mutex.Unlock()
+	for _, address := range addresses {
+		sub.addresses[address] = true
+	}
+}
+
+// Stop watching providers
+func (sub *Subscription) Unwatch(addresses []string) {
+	sub.service.updates.mutex.Lock()
+	defer sub.service.updates.mutex.Unlock()
+	for _, address := range addresses {
+		delete(sub.addresses, address)
+	}
+}
+
+// Watched providers
+func (sub *Subscription) Addresses() []string {
+	sub.service.updates.mutex.Lock()
+	defer sub.service.updates.mutex.Unlock()
+	addresses := make([]string, 0, len(sub.addresses))
+	for address := range sub.addresses {
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+// Stop the subscription and close its updates channel
+func (sub *Subscription) Close() {
+	sub.service.updates.mutex.Lock()
+	defer sub.service.updates.mutex.Unlock()
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	delete(sub.service.updates.subscribers, sub)
+	close(sub.updates)
+}
+
+// Compare freshly fetched providers with their last seen state and send the
+// changes to the subscribers watching them. A provider's first observation only
+// sets its baseline.
+func (s *Service) publishUpdates(fresh []*akash.ProviderInfo) {
+	healthDelta := s.config.SubscriptionHealthDelta
+	if healthDelta <= 0 {
+		healthDelta = defaultSubscriptionHealthDelta
+	}
+
+	s.updates.mutex.Lock()
+	defer s.updates.mutex.Unlock()
+
+	for _, info := range fresh {
+		current := stateOf(info)
+		previous, seen := s.updates.last[info.Address]
+		s.updates.last[info.Address] = current
+		if !seen {
+			continue
+		}
+
+		var changes []string
+		if math.Abs(current.healthScore-previous.healthScore) >= healthDelta {
+			changes = append(changes, "health_score")
+		}
+		if current.online != previous.online {
+			changes = append(changes, "online")
+		}
+		if current.availableGPU != previous.availableGPU {
+			changes = append(changes, "available_gpu")
+		}
+		if current.availableNodes != previous.availableNodes {
+			changes = append(changes, "available_nodes")
+		}
+		if len(changes) == 0 {
+			continue
+		}
+
+		update := ProviderUpdate{
+			Address:             info.Address,
+			ObservedAt:          info.LastSeen,
+			Changes:             changes,
+			HealthScore:         current.healthScore,
+			PreviousHealthScore: previous.healthScore,
+			Online:              current.online,
+			AvailableGPU:        current.availableGPU,
+			AvailableNodes:      current.availableNodes,
+		}
+		for sub := range s.updates.subscribers {
+			if !sub.addresses[info.Address] {
+				continue
+			}
+			select {
+			case sub.updates <- update:
+			default:
+			}
+		}
+	}
+}
+
+// Every watched provider, across subscribers
+func (s *Service) watchedProviders() []string {
+	s.updates.mutex.Lock()
+	defer s.updates.mutex.Unlock()
+
+	seen := make(map[string]bool)
+	var addresses []string
+	for sub := range s.updates.subscribers {
+		for address := range sub.addresses {
+			if !seen[address] {
+				seen[address] = true
+				addresses = append(addresses, address)
+			}
+		}
+	}
+	return addresses
+}
+
+// Keep watched providers fresh so their changes are seen without a client
+// asking. Cached data is reused until it expires, so this adds no queries for
+// providers other callers already keep fresh.
+func (s *Service) subscriptionRefreshLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		addresses := s.watchedProviders()
+		if len(addresses) == 0 {
+			s.loopRan("subscription_refresh")
+			continue
+		}
+		refreshCtx, cancel := context.WithTimeout(ctx, interval)
+		if _, err := s.GetProviderIntelligence(refreshCtx, addresses); err != nil && ctx.Err() == nil {
+			slog.Warn("Subscription refresh failed", "providers", len(addresses), "error", err)
+		}
+		cancel()
+		s.loopRan("subscription_refresh")
+	}
+}
@@ -0,0 +1,90 @@
+package intelligence
+
+import (
+	"sort"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+const (
+	// Fixed part of provisioning: bid acceptance, manifest upload, image pull
+	baseProvisionTime = 45 * time.Second
+
+	// Provisioning takes several round trips to the provider, so its status
+	// latency is multiplied into the estimate
+	provisionLatencyFactor = 20
+
+	// Extra time when the provider reports no free node and must squeeze the workload in
+	noFreeNodePenalty = 60 * time.Second
+
+	// Estimates at or below fast score 1, at or above slow score 0
+	fastProvisionTime = 1 * time.Minute
+	slowProvisionTime = 10 * time.Minute
+
+	// Recent observations used to smooth out a single slow status response
+	provisionLookback = time.Hour
+)
+
+// Neutral provisioning score used when a provider never answered its status endpoint
+const neutralProvisioningScore = 0.5
+
+// Estimated time until a deployment on the provider is running
+type ProvisioningEstimate struct {
+	Estimate time.Duration `json:"estimate"`
+	Basis    string        `json:"basis"` // how the estimate was derived
+	Samples  int           `json:"samples"`
+}
+
+// Estimate time-to-provision from provider responsiveness: a base time plus a
+// multiple of its median recent status latency. Returns nil without any latency data.
+func (s *Service) estimateProvisioning(provider *akash.ProviderInfo, criteria SelectionCriteria) *ProvisioningEstimate {
+	var latencies []time.Duration
+	if provider.StatusQueryTime > 0 && provider.Error == "" {
+		latencies = append(latencies, provider.StatusQueryTime)
+	}
+
+	// Pinned selections only use the supplied data
+	if s.history != nil && !criteria.Deterministic {
+		until := provider.LastSeen
+		if until.IsZero() {
+			until = time.Now()
+		}
+		for _, sample := range s.history.Samples(provider.Address, until.Add(-provisionLookback), until) {
+			if sample.Info.StatusQueryTime > 0 && sample.Info.Error == "" && !sample.Info.LastSeen.Equal(provider.LastSeen) {
+				latencies = append(latencies, sample.Info.StatusQueryTime)
+			}
+		}
+	}
+	if len(latencies) == 0 {
+		return nil
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	median := latencies[len(latencies)/2]
+
+	estimate := baseProvisionTime + median*provisionLatencyFactor
+	if provider.ClusterInfo != nil && provider.ClusterInfo.AvailableNodes == 0 {
+		estimate += noFreeNodePenalty
+	}
+
+	return &ProvisioningEstimate{
+		Estimate: estimate.Round(time.Second),
+		Basis:    "responsiveness",
+		Samples:  len(latencies),
+	}
+}
+
+// Score a provisioning estimate between 0 (slow) and 1 (fast); neutral without an estimate
+func provisioningScore(estimate *ProvisioningEstimate) float64 {
+	if estimate == nil {
+		return neutralProvisioningScore
+	}
+	switch {
+	case estimate.Estimate <= fastProvisionTime:
+		return 1
+	case estimate.Estimate >= slowProvisionTime:
+		return 0
+	}
+	return 1 - float64(estimate.Estimate-fastProvisionTime)/float64(slowProvisionTime-fastProvisionTime)
+}
@@ -0,0 +1,72 @@
+package intelligence
+
+import (
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Raw measurements behind a provider's sub-scores. Fields are omitted when the
+// measurement is unavailable, so a missing value is never mistaken for zero.
+type RawMetrics struct {
+	// Reliability (via the health score) and the lease-based price heuristic
+	HealthScore  float64 `json:"health_score"`
+	ActiveLeases *int    `json:"active_leases,omitempty"`
+
+	// Performance
+	StatusLatencyMs     *float64 `json:"status_latency_ms,omitempty"`
+	BlockchainLatencyMs *float64 `json:"blockchain_latency_ms,omitempty"`
+	AvailableNodes      *int     `json:"available_nodes,omitempty"`
+	AvailableCPU        *int64   `json:"available_cpu_millicores,omitempty"`
+	AvailableMemory     *int64   `json:"available_memory_bytes,omitempty"`
+	AvailableGPU        *int     `json:"available_gpu,omitempty"`
+
+	// Network
+	BandwidthMbps *float64 `json:"bandwidth_mbps,omitempty"`
+
+	// Geographic
+	Region     string `json:"region,omitempty"`
+	Datacenter string `json:"datacenter,omitempty"`
+
+	// Advertised price of the reference workload (1 core, 2 GiB, 10 GiB) per month
+	ReferenceMonthlyCost *float64 `json:"reference_monthly_cost,omitempty"`
+	PriceDenom           string   `json:"price_denom,omitempty"`
+}
+
+// Collect the raw inputs scoring used for a provider
+func collectRawMetrics(provider *akash.ProviderInfo) *RawMetrics {
+	metrics := &RawMetrics{
+		HealthScore: provider.HealthScore,
+		Region:      provider.Attributes["region"],
+		Datacenter:  provider.Attributes["datacenter"],
+	}
+
+	if provider.StatusQueryTime > 0 && hasStatusData(provider) {
+		latency := float64(provider.StatusQueryTime.Microseconds()) / 1000
+		metrics.StatusLatencyMs = &latency
+	}
+	if provider.BlockchainQueryTime > 0 {
+		latency := float64(provider.BlockchainQueryTime.Microseconds()) / 1000
+		metrics.BlockchainLatencyMs = &latency
+	}
+
+	if cluster := provider.ClusterInfo; cluster != nil {
+		leases := cluster.ActiveLeases
+		nodes := cluster.AvailableNodes
+		cpu := cluster.AvailableResources.CPU
+		memory := cluster.AvailableResources.Memory
+		gpu := cluster.AvailableResources.GPU
+		metrics.ActiveLeases = &leases
+		metrics.AvailableNodes = &nodes
+		metrics.AvailableCPU = &cpu
+		metrics.AvailableMemory = &memory
+		metrics.AvailableGPU = &gpu
+	}
+
+	metrics.BandwidthMbps = assessNetwork(provider).BandwidthMbps
+
+	if cost, ok := provider.Pricing.ReferenceMonthlyCost(); ok {
+		metrics.ReferenceMonthlyCost = &cost
+		metrics.PriceDenom = provider.Pricing.Denom
+	}
+
+	return metrics
+}
@@ -0,0 +1,79 @@
+package intelligence
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Longest a readiness check waits for the Akash gRPC endpoint
+const readinessPingTimeout = 2 * time.Second
+
+// Outcome of one readiness check
+type ReadinessCheck struct {
+	Name    string      `json:"name"`
+	Ready   bool        `json:"ready"`
+	Error   string      `json:"error,omitempty"`
+	Details interface{} `json:"details,omitempty"`
+}
+
+// Whether the service can serve traffic, and why not
+type Readiness struct {
+	Ready     bool             `json:"ready"`
+	CheckedAt time.Time        `json:"checked_at"`
+	Checks    []ReadinessCheck `json:"checks"`
+}
+
+// Check the Akash gRPC endpoint is reachable, the cache is serving and the
+// background loops are running. Not ready once Close has been called.
+func (s *Service) CheckReadiness(ctx context.Context) *Readiness {
+	readiness := &Readiness{CheckedAt: time.Now()}
+
+	// Without the chain no uncached provider can be fetched; one healthy
+	// endpoint is enough, or the RPC fallback while none is
+	pingCtx, cancel := context.WithTimeout(ctx, readinessPingTimeout)
+	start := time.Now()
+	err := s.akashClient.Ping(pingCtx)
+	cancel()
+	details := map[string]interface{}{
+		"endpoints":  s.config.AkashGRPCEndpoints,
+		"latency_ms": time.Since(start).Milliseconds(),
+	}
+	if err != nil && s.akashClient.HasRPCFallback() {
+		details["grpc_error"] = err.Error()
+		details["rpc_endpoint"] = s.config.AkashRPCEndpoint
+		pingCtx, cancel := context.WithTimeout(ctx, readinessPingTimeout)
+		if err = s.akashClient.PingRPC(pingCtx); err == nil {
+			details["serving_from"] = "rpc"
+		}
+		cancel()
+	}
+	chain := ReadinessCheck{Name: "akash_grpc", Ready: err == nil, Details: details}
+	if err != nil {
+		chain.Error = err.Error()
+	}
+
+	cache := ReadinessCheck{Name: "cache", Ready: !s.Closing(), Details: s.GetCacheStats()}
+	if !cache.Ready {
+		cache.Error = ErrServiceClosed.Error()
+	}
+
+	loops := s.BackgroundLoops()
+	background := ReadinessCheck{Name: "background_loops", Ready: true, Details: loops}
+	for _, loop := range loops {
+		if !loop.Running {
+			background.Ready = false
+			background.Error = fmt.Sprintf("%s has stopped", loop.Name)
+			break
+		}
+		if loop.Stalled {
+			background.Ready = false
+			background.Error = fmt.Sprintf("%s has not run for %d intervals", loop.Name, loopStallFactor)
+			break
+		}
+	}
+
+	readiness.Checks = []ReadinessCheck{chain, cache, background}
+	readiness.Ready = chain.Ready && cache.Ready && background.Ready
+	return readiness
+}
@@ -0,0 +1,76 @@
+package intelligence
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Reasoning styles
+const (
+	ReasoningStyleRich  = "rich"  // emoji headings and bullets (default)
+	ReasoningStylePlain = "plain" // ASCII only, safe for terminals and log pipelines
+)
+
+// Units used for memory and storage in reasoning
+const (
+	UnitsBinary  = "binary"  // GiB, 1024^3 bytes (default)
+	UnitsDecimal = "decimal" // GB, 1000^3 bytes
+)
+
+// Strips emoji and typographic bullets for plain reasoning
+var plainReasoning = strings.NewReplacer(
+	"🎯 ", "",
+	"📊 ", "",
+	"🔍 ", "",
+	"📈 ", "",
+	"⚠️  ", "",
+	"•", "-",
+	"×", "x",
+)
+
+// ValidateReasoningFormat checks a reasoning style and unit system, empty values meaning the default
+func ValidateReasoningFormat(style, units string) error {
+	switch style {
+	case "", ReasoningStyleRich, ReasoningStylePlain, ReasoningStyleMarkdown, ReasoningStyleHTML, ReasoningStyleCustom:
+	default:
+		return fmt.Errorf("unknown reasoning style %q (valid: %s, %s, %s, %s, %s)", style,
+			ReasoningStyleRich, ReasoningStylePlain, ReasoningStyleMarkdown, ReasoningStyleHTML, ReasoningStyleCustom)
+	}
+	switch units {
+	case "", UnitsBinary, UnitsDecimal:
+	default:
+		return fmt.Errorf("unknown units %q (valid: %s, %s)", units, UnitsBinary, UnitsDecimal)
+	}
+	return nil
+}
+
+// Get the reasoning style for a request, falling back to config
+func (s *Service) reasoningStyle(criteria SelectionCriteria) string {
+	if criteria.ReasoningStyle != "" {
+		return criteria.ReasoningStyle
+	}
+	if s.config.ReasoningStyle != "" {
+		return s.config.ReasoningStyle
+	}
+	return ReasoningStyleRich
+}
+
+// Format a byte count in the requested unit system
+func (s *Service) formatBytes(bytes int64, criteria SelectionCriteria) string {
+	units := criteria.Units
+	if units == "" {
+		units = s.config.ReasoningUnits
+	}
+	if units == UnitsDecimal {
+		return fmt.Sprintf("%.1fGB", float64(bytes)/1e9)
+	}
+	return fmt.Sprintf("%.1fGiB", float64(bytes)/(1<<30))
+}
+
+// Strip glyphs that reached plain reasoning through the structured data
+func (s *Service) applyReasoningStyle(reasoning string, style string) string {
+	if style == ReasoningStylePlain {
+		return plainReasoning.Replace(reasoning)
+	}
+	return reasoning
+}
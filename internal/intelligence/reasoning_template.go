@@ -0,0 +1,147 @@
+package intelligence
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"text/template"
+)
+
+// Built-in reasoning templates beyond rich and plain
+const (
+	ReasoningStyleMarkdown = "markdown"
+	ReasoningStyleHTML     = "html"
+	ReasoningStyleCustom   = "custom" // the operator's reasoning template
+)
+
+// Structured selection reasoning; templates decide how it is presented
+type ReasoningData struct {
+	Provider    string            `json:"provider"`
+	Score       float64           `json:"score"`
+	Breakdown   []ReasoningFactor `json:"breakdown"`
+	Details     []string          `json:"details"`
+	Rank        int               `json:"rank"`
+	Candidates  int               `json:"candidates"`
+	Competitive []string          `json:"competitive,omitempty"`
+	Note        string            `json:"note,omitempty"`
+}
+
+// One line of the score breakdown, e.g. Name "Price", Value "0.800 (weight: 40.0%, advertised)"
+type ReasoningFactor struct {
+	Name  string  `json:"name"`
+	Score float64 `json:"score"`
+	Value string  `json:"value"`
+}
+
+var builtinReasoningTemplates = map[string]string{
+	ReasoningStyleRich: `🎯 Selected provider {{.Provider}} with overall score {{printf "%.3f" .Score}}
+
+📊 Score Breakdown:
+{{range .Breakdown}}  • {{.Name}}: {{.Value}}
+{{end}}
+🔍 Provider Details:
+{{range .Details}}  • {{.}}
+{{end}}{{if .Competitive}}
+📈 Competitive Analysis:
+{{range .Competitive}}  • {{.}}
+{{end}}{{end}}{{if .Note}}
+⚠️  Note: {{.Note}}
+{{end}}`,
+
+	ReasoningStylePlain: `Selected provider {{.Provider}} with overall score {{printf "%.3f" .Score}}
+
+Score Breakdown:
+{{range .Breakdown}}  - {{.Name}}: {{.Value}}
+{{end}}
+Provider Details:
+{{range .Details}}  - {{.}}
+{{end}}{{if .Competitive}}
+Competitive Analysis:
+{{range .Competitive}}  - {{.}}
+{{end}}{{end}}{{if .Note}}
+Note: {{.Note}}
+{{end}}`,
+
+	ReasoningStyleMarkdown: `**Selected provider ` + "`{{.Provider}}`" + `** with overall score {{printf "%.3f" .Score}}
+
+### Score Breakdown
+{{range .Breakdown}}- **{{.Name}}**: {{.Value}}
+{{end}}
+### Provider Details
+{{range .Details}}- {{.}}
+{{end}}{{if .Competitive}}
+### Competitive Analysis
+{{range .Competitive}}- {{.}}
+{{end}}{{end}}{{if .Note}}
+> **Note:** {{.Note}}
+{{end}}`,
+
+	ReasoningStyleHTML: `<p><strong>Selected provider <code>{{html .Provider}}</code></strong> with overall score {{printf "%.3f" .Score}}</p>
+<h3>Score Breakdown</h3>
+<ul>
+{{range .Breakdown}}<li><strong>{{html .Name}}</strong>: {{html .Value}}</li>
+{{end}}</ul>
+<h3>Provider Details</h3>
+<ul>
+{{range .Details}}<li>{{html .}}</li>
+{{end}}</ul>
+{{if .Competitive}}<h3>Competitive Analysis</h3>
+<ul>
+{{range .Competitive}}<li>{{html .}}</li>
+{{end}}</ul>
+{{end}}{{if .Note}}<p><em>Note: {{html .Note}}</em></p>
+{{end}}`,
+}
+
+// Reasoning data used to check that a custom template executes, not just parses
+var sampleReasoningData = ReasoningData{
+	Provider:    "akash1example",
+	Score:       0.5,
+	Breakdown:   []ReasoningFactor{{Name: "Price", Score: 0.5, Value: "0.500 (weight: 40.0%, heuristic)"}},
+	Details:     []string{"10 active leases (reliability indicator)"},
+	Rank:        1,
+	Candidates:  2,
+	Competitive: []string{"Ranked #1 out of 2 providers"},
+	Note:        "example",
+}
+
+// Parse the built-in templates and, if given, the operator's custom template.
+// A custom template that fails to parse or execute is rejected at startup.
+func parseReasoningTemplates(custom string) (*template.Template, error) {
+	templates := template.New("reasoning")
+	for name, text := range builtinReasoningTemplates {
+		if _, err := templates.New(name).Parse(text); err != nil {
+			return nil, fmt.Errorf("built-in reasoning template %s: %w", name, err)
+		}
+	}
+
+	if custom != "" {
+		if _, err := templates.New(ReasoningStyleCustom).Parse(custom); err != nil {
+			return nil, fmt.Errorf("invalid reasoning template: %w", err)
+		}
+		if err := templates.ExecuteTemplate(&bytes.Buffer{}, ReasoningStyleCustom, sampleReasoningData); err != nil {
+			return nil, fmt.Errorf("invalid reasoning template: %w", err)
+		}
+	}
+
+	return templates, nil
+}
+
+// Render structured reasoning with the template for the request's style.
+// Custom falls back to rich when no custom template is configured.
+func (s *Service) renderReasoning(data *ReasoningData, criteria SelectionCriteria) string {
+	style := s.reasoningStyle(criteria)
+	if s.reasoningTemplates.Lookup(style) == nil {
+		style = ReasoningStyleRich
+	}
+
+	var out bytes.Buffer
+	if err := s.reasoningTemplates.ExecuteTemplate(&out, style, data); err != nil {
+		slog.Warn("Reasoning template failed, using the rich style", "style", style, "error", err)
+		out.Reset()
+		s.reasoningTemplates.ExecuteTemplate(&out, ReasoningStyleRich, data)
+		style = ReasoningStyleRich
+	}
+
+	return s.applyReasoningStyle(out.String(), style)
+}
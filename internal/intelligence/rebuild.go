@@ -0,0 +1,228 @@
+package intelligence
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+const (
+	// Providers fetched per step of a rebuild; progress is reported per step
+	rebuildBatchSize = 50
+
+	// Finished jobs kept for status queries
+	rebuildJobsKept = 20
+
+	// Per-provider errors kept on a job
+	rebuildErrorsKept = 100
+)
+
+const (
+	RebuildRunning   = "running"
+	RebuildCompleted = "completed"
+	RebuildFailed    = "failed"
+)
+
+var ErrRebuildRunning = errors.New("a cache rebuild is already running")
+
+// Progress of a full cache rebuild
+type RebuildJob struct {
+	ID         string         `json:"id"`
+	State      string         `json:"state"`
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt *time.Time     `json:"finished_at,omitempty"`
+	Elapsed    time.Duration  `json:"elapsed"`
+	ETA        *time.Duration `json:"eta,omitempty"` // remaining time at the current fetch rate
+	Total      int            `json:"total"`         // providers to fetch, known once listing completes
+	Fetched    int            `json:"fetched"`
+	Failed     int            `json:"failed"`
+	Errors     []RebuildError `json:"errors,omitempty"` // first per-provider failures
+	Error      string         `json:"error,omitempty"`  // why the job failed
+}
+
+type RebuildError struct {
+	Address string `json:"address"`
+	Error   string `json:"error"`
+}
+
+// Running and recent rebuild jobs
+type rebuildState struct {
+	running *RebuildJob
+	jobs    map[string]*RebuildJob
+	order   []string
+	mutex   sync.Mutex
+}
+
+// Start a background refetch of every known provider, replacing its cache
+// entry. Returns immediately with the new job; only one runs at a time, so
+// while one is running this returns that job with ErrRebuildRunning. Once the
+// service is closing the job fails at once with ErrServiceClosed.
+func (s *Service) StartCacheRebuild() (*RebuildJob, error) {
+	s.rebuild.mutex.Lock()
+	defer s.rebuild.mutex.Unlock()
+
+	if s.rebuild.running != nil {
+		job := s.rebuild.running.snapshot()
+		return &job, ErrRebuildRunning
+	}
+
+	job := &RebuildJob{ID: newRebuildID(), State: RebuildRunning, StartedAt: time.Now()}
+	if s.rebuild.jobs == nil {
+		s.rebuild.jobs = make(map[string]*RebuildJob)
+	}
+	s.rebuild.running = job
+	s.rebuild.jobs[job.ID] = job
+	s.rebuild.order = append(s.rebuild.order, job.ID)
+	if len(s.rebuild.order) > rebuildJobsKept {
+		delete(s.rebuild.jobs, s.rebuild.order[0])
+		s.rebuild.order = s.rebuild.order[1:]
+	}
+
+	if !s.goBackground(func(ctx context.Context) { s.runCacheRebuild(ctx, job) }) {
+		s.rebuild.running = nil
+		job.State = RebuildFailed
+		job.Error = ErrServiceClosed.Error()
+		snapshot := job.snapshot()
+		return &snapshot, ErrServiceClosed
+	}
+
+	snapshot := job.snapshot()
+	return &snapshot, nil
+}
+
+// Get a rebuild job's progress by ID
+func (s *Service) GetRebuildJob(id string) (*RebuildJob, bool) {
+	s.rebuild.mutex.Lock()
+	defer s.rebuild.mutex.Unlock()
+
+	job, ok := s.rebuild.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	snapshot := job.snapshot()
+	return &snapshot, true
+}
+
+// Runs detached from the triggering request, which has already returned, until
+// the service closes
+func (s *Service) runCacheRebuild(ctx context.Context, job *RebuildJob) {
+
+	slog.Info("Cache rebuild started", "job", job.ID)
+
+	addresses, err := s.knownProviders(ctx)
+	if err == nil {
+		s.updateRebuild(job, func() { job.Total = len(addresses) })
+		err = s.rebuildProviders(ctx, job, addresses)
+	}
+
+	s.updateRebuild(job, func() {
+		now := time.Now()
+		job.FinishedAt = &now
+		job.State = RebuildCompleted
+		if err != nil {
+			job.State = RebuildFailed
+			job.Error = err.Error()
+		}
+	})
+	s.rebuild.mutex.Lock()
+	s.rebuild.running = nil
+	s.rebuild.mutex.Unlock()
+
+	if err != nil {
+		slog.Error("Cache rebuild failed", "job", job.ID, "error", err)
+		return
+	}
+	slog.Info("Cache rebuild completed", "job", job.ID,
+		"refreshed", job.Fetched, "failed", job.Failed, "duration", time.Since(job.StartedAt).Round(time.Second))
+}
+
+// Every on-chain provider plus anything already cached, minus banned providers
+func (s *Service) knownProviders(ctx context.Context) ([]string, error) {
+	list, err := s.akashClient.ListProviders(ctx, akash.ListProvidersOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list providers: %w", err)
+	}
+
+	known := make(map[string]bool, len(list.Providers))
+	for _, provider := range list.Providers {
+		known[provider.Address] = true
+	}
+	s.cache.mutex.RLock()
+	for addr := range s.cache.data {
+		known[addr] = true
+	}
+	s.cache.mutex.RUnlock()
+
+	addresses := make([]string, 0, len(known))
+	for addr := range known {
+		if !s.blacklist.IsBanned(addr) {
+			addresses = append(addresses, addr)
+		}
+	}
+	sort.Strings(addresses)
+	return addresses, nil
+}
+
+// Refetch providers batch by batch. Fetches go through the usual path, so
+// they share the configured concurrency limit with live requests.
+func (s *Service) rebuildProviders(ctx context.Context, job *RebuildJob, addresses []string) error {
+	for i := 0; i < len(addresses); i += rebuildBatchSize {
+		batch := addresses[i:min(i+rebuildBatchSize, len(addresses))]
+		intel, err := s.GetProviderIntelligenceWithOptions(ctx, batch, FetchOptions{Refresh: true})
+		if err != nil {
+			return fmt.Errorf("rebuild stopped after %d of %d providers: %w", i, len(addresses), err)
+		}
+
+		s.updateRebuild(job, func() {
+			job.Fetched += len(intel.Providers)
+			job.Failed += len(intel.FailedProviders)
+			for _, failed := range intel.FailedProviders {
+				if len(job.Errors) < rebuildErrorsKept {
+					job.Errors = append(job.Errors, RebuildError{Address: failed.Address, Error: failed.Error})
+				}
+			}
+		})
+	}
+	return nil
+}
+
+func (s *Service) updateRebuild(job *RebuildJob, update func()) {
+	s.rebuild.mutex.Lock()
+	defer s.rebuild.mutex.Unlock()
+	update()
+}
+
+// Copy of the job with elapsed time and ETA filled in; callers hold the rebuild mutex
+func (job *RebuildJob) snapshot() RebuildJob {
+	snapshot := *job
+	snapshot.Errors = append([]RebuildError(nil), job.Errors...)
+
+	end := time.Now()
+	if job.FinishedAt != nil {
+		end = *job.FinishedAt
+	}
+	snapshot.Elapsed = end.Sub(job.StartedAt)
+
+	done := job.Fetched + job.Failed
+	if job.State == RebuildRunning && done > 0 && job.Total > done {
+		eta := snapshot.Elapsed / time.Duration(done) * time.Duration(job.Total-done)
+		snapshot.ETA = &eta
+	}
+	return snapshot
+}
+
+func newRebuildID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("rebuild-%d", time.Now().UnixNano())
+	}
+	return "rebuild-" + hex.EncodeToString(buf)
+}
@@ -0,0 +1,137 @@
+package intelligence
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Selection cached under a fingerprint of its addresses and resolved criteria
+type cachedRecommendation struct {
+	selection *ProviderSelection
+	cachedAt  time.Time
+	expiresAt time.Time
+
+	// Provider cache entry each candidate was scored from (zero when it failed) and
+	// whether it was blacklisted; any change means the selection could differ
+	sources map[string]time.Time
+	banned  map[string]bool
+}
+
+// Short-lived cache of selections for repeated identical queries
+type RecommendationCache struct {
+	ttl     time.Duration
+	entries map[string]*cachedRecommendation
+	mutex   sync.Mutex
+}
+
+func NewRecommendationCache(ttl time.Duration) *RecommendationCache {
+	return &RecommendationCache{
+		ttl:     ttl,
+		entries: make(map[string]*cachedRecommendation),
+	}
+}
+
+// Fingerprint of the candidate set and resolved criteria; address order does not matter
+func recommendationKey(addresses []string, criteria SelectionCriteria) (string, bool) {
+	sorted := append([]string(nil), addresses...)
+	sort.Strings(sorted)
+
+	encoded, err := json.Marshal(criteria)
+	if err != nil {
+		return "", false
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(sorted, ",") + "|" + string(encoded)))
+	return hex.EncodeToString(sum[:]), true
+}
+
+// Snapshot of the provider cache entries and blacklist state behind a selection
+func (s *Service) recommendationSources(addresses []string) (map[string]time.Time, map[string]bool) {
+	sources := make(map[string]time.Time, len(addresses))
+	banned := make(map[string]bool, len(addresses))
+
+	s.cache.mutex.RLock()
+	defer s.cache.mutex.RUnlock()
+
+	now := time.Now()
+	for _, addr := range addresses {
+		banned[addr] = s.blacklist.IsBanned(addr)
+		if cached, ok := s.cache.data[addr]; ok && now.Before(cached.ExpiresAt) {
+			sources[addr] = cached.CachedAt
+		} else {
+			sources[addr] = time.Time{}
+		}
+	}
+	return sources, banned
+}
+
+// Get a fresh cached selection whose provider data and blacklist state are unchanged
+func (s *Service) cachedRecommendation(key string, addresses []string) (*ProviderSelection, bool) {
+	s.recommendations.mutex.Lock()
+	entry, ok := s.recommendations.entries[key]
+	s.recommendations.mutex.Unlock()
+	if !ok || !time.Now().Before(entry.expiresAt) {
+		return nil, false
+	}
+
+	sources, banned := s.recommendationSources(addresses)
+	for _, addr := range addresses {
+		if !sources[addr].Equal(entry.sources[addr]) || banned[addr] != entry.banned[addr] {
+			s.recommendations.mutex.Lock()
+			delete(s.recommendations.entries, key)
+			s.recommendations.mutex.Unlock()
+			return nil, false
+		}
+	}
+
+	// Mark a copy so the stored selection stays untouched
+	selection := *entry.selection
+	selection.Cached = true
+	cachedAt := entry.cachedAt
+	selection.CachedAt = &cachedAt
+	return &selection, true
+}
+
+// Store a selection along with the provider data it was computed from
+func (s *Service) storeRecommendation(key string, addresses []string, selection *ProviderSelection) {
+	sources, banned := s.recommendationSources(addresses)
+	now := time.Now()
+
+	s.recommendations.mutex.Lock()
+	defer s.recommendations.mutex.Unlock()
+
+	s.recommendations.entries[key] = &cachedRecommendation{
+		selection: selection,
+		cachedAt:  now,
+		expiresAt: now.Add(s.recommendations.ttl),
+		sources:   sources,
+		banned:    banned,
+	}
+}
+
+// Drop expired recommendations
+func (r *RecommendationCache) PruneExpired() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := time.Now()
+	removed := 0
+	for key, entry := range r.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(r.entries, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+func (r *RecommendationCache) clear() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.entries = make(map[string]*cachedRecommendation)
+}
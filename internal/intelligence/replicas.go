@@ -0,0 +1,187 @@
+package intelligence
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Most replicas a single selection places
+const maxReplicas = 50
+
+// What replicas must not share, beyond the provider itself
+type AntiAffinity struct {
+	DistinctRegions  bool `json:"distinct_regions,omitempty"`
+	DistinctClusters bool `json:"distinct_clusters,omitempty"` // host or public hostname
+}
+
+// A provider chosen for one replica
+type ReplicaPick struct {
+	Replica       int                `json:"replica"`
+	Provider      string             `json:"provider"`
+	Score         float64            `json:"score"`
+	ScoreRank     int                `json:"score_rank"`
+	Region        string             `json:"region,omitempty"`
+	Cluster       string             `json:"cluster,omitempty"`
+	Reasoning     string             `json:"reasoning"`
+	ReasoningData *ReasoningData     `json:"reasoning_data,omitempty"`
+	Skipped       []FilteredProvider `json:"skipped,omitempty"` // higher-scoring providers passed over for anti-affinity
+}
+
+// Distinct providers for a set of replicas
+type ReplicaSelection struct {
+	Replicas          []ReplicaPick           `json:"replicas"`
+	TotalScore        float64                 `json:"total_score"`
+	AntiAffinity      AntiAffinity            `json:"anti_affinity"`
+	FailedProviders   []*akash.FailedProvider `json:"failed_providers"`
+	ExcludedProviders []string                `json:"excluded_providers,omitempty"`
+	FilteredProviders []FilteredProvider      `json:"filtered_providers,omitempty"`
+	PrunedProviders   []PrunedProvider        `json:"pruned_providers,omitempty"`
+	ScoringCap        *ScoringCap             `json:"scoring_cap,omitempty"`
+	Criteria          SelectionCriteria       `json:"criteria"`
+
+	Degraded                bool                    `json:"degraded"`
+	UnavailableCapabilities []UnavailableCapability `json:"unavailable_capabilities,omitempty"`
+}
+
+// Select a distinct provider for each of n replicas, maximizing the total score.
+// Candidates are taken best first, skipping any that share a region or cluster
+// with an earlier pick when that is required. With a single constraint this
+// greedy choice is optimal; with both it is a close approximation.
+func (s *Service) SelectReplicas(ctx context.Context, addresses []string, criteria SelectionCriteria, n int, affinity AntiAffinity) (*ReplicaSelection, error) {
+	if n < 1 || n > maxReplicas {
+		return nil, fmt.Errorf("replica count must be between 1 and %d, got %d", maxReplicas, n)
+	}
+
+	ctx, degraded := withDegradation(ctx)
+
+	intel, excluded, capped, err := s.fetchCandidates(ctx, addresses, criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	criteria = s.applyGeoCoverage(intel.Providers, criteria)
+	scored, filtered, pruned, err := s.filterAndScore(ctx, intel.Providers, criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	selection := &ReplicaSelection{
+		AntiAffinity:      affinity,
+		FailedProviders:   intel.FailedProviders,
+		ExcludedProviders: excluded,
+		FilteredProviders: filtered,
+		PrunedProviders:   pruned,
+		ScoringCap:        capped,
+		Criteria:          criteria,
+	}
+
+	usedRegions := make(map[string]string)  // region -> provider placed there
+	usedClusters := make(map[string]string) // cluster key -> provider placed there
+	var skipped []FilteredProvider
+	for rank, candidate := range scored {
+		if len(selection.Replicas) == n {
+			break
+		}
+
+		region := regionOf(candidate.Provider)
+		clusters := clusterKeys(candidate.Provider)
+		if reason := affinityConflict(affinity, region, clusters, usedRegions, usedClusters); reason != "" {
+			skipped = append(skipped, FilteredProvider{Address: candidate.Provider.Address, Reason: reason})
+			continue
+		}
+
+		usedRegions[region] = candidate.Provider.Address
+		for _, cluster := range clusters {
+			usedClusters[cluster] = candidate.Provider.Address
+		}
+
+		// Reason against the candidates still open to this replica
+		reasoning := s.buildReasoningData(candidate, scored[rank:], criteria)
+		pick := ReplicaPick{
+			Replica:       len(selection.Replicas) + 1,
+			Provider:      candidate.Provider.Address,
+			Score:         candidate.Score,
+			ScoreRank:     rank + 1,
+			Region:        region,
+			Reasoning:     s.renderReasoning(reasoning, criteria),
+			ReasoningData: reasoning,
+			Skipped:       skipped,
+		}
+		if identity := candidate.Provider.ClusterIdentity; identity != nil {
+			pick.Cluster = identity.Hostname
+		} else if len(clusters) > 0 {
+			pick.Cluster = clusters[0]
+		}
+		selection.Replicas = append(selection.Replicas, pick)
+		selection.TotalScore += candidate.Score
+		skipped = nil
+	}
+
+	if len(selection.Replicas) < n {
+		return nil, fmt.Errorf("only %d of %d replicas could be placed on distinct providers (%d candidates scored, %s)",
+			len(selection.Replicas), n, len(scored), describeAntiAffinity(affinity))
+	}
+
+	selection.Degraded, selection.UnavailableCapabilities = degraded.report()
+	return selection, nil
+}
+
+// Region attribute of a provider; providers without one share the "unknown" region
+func regionOf(provider *akash.ProviderInfo) string {
+	if region := strings.ToLower(strings.TrimSpace(provider.Attributes["region"])); region != "" {
+		return region
+	}
+	return "unknown"
+}
+
+// Identities of the cluster behind a provider: its fingerprint, host and reported public hostname
+func clusterKeys(provider *akash.ProviderInfo) []string {
+	var keys []string
+	if identity := provider.ClusterIdentity; identity != nil {
+		keys = append(keys, identity.Fingerprint)
+	}
+	if _, host, ok := normalizeHostURI(provider.HostURI); ok {
+		keys = append(keys, host)
+	}
+	if provider.ClusterInfo != nil {
+		if hostname := strings.ToLower(strings.TrimSpace(provider.ClusterInfo.PublicHostname)); hostname != "" && !slices.Contains(keys, hostname) {
+			keys = append(keys, hostname)
+		}
+	}
+	return keys
+}
+
+// Why a candidate cannot join the replicas placed so far; empty if it can
+func affinityConflict(affinity AntiAffinity, region string, clusters []string, usedRegions, usedClusters map[string]string) string {
+	if affinity.DistinctRegions {
+		if holder, ok := usedRegions[region]; ok {
+			return fmt.Sprintf("region %s already holds a replica on %s", region, holder)
+		}
+	}
+	if affinity.DistinctClusters {
+		for _, cluster := range clusters {
+			if holder, ok := usedClusters[cluster]; ok {
+				return fmt.Sprintf("cluster %s already holds a replica on %s", cluster, holder)
+			}
+		}
+	}
+	return ""
+}
+
+func describeAntiAffinity(affinity AntiAffinity) string {
+	var constraints []string
+	if affinity.DistinctRegions {
+		constraints = append(constraints, "distinct regions")
+	}
+	if affinity.DistinctClusters {
+		constraints = append(constraints, "distinct clusters")
+	}
+	if len(constraints) == 0 {
+		return "distinct providers only"
+	}
+	return "requiring " + strings.Join(constraints, " and ")
+}
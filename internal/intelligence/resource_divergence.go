@@ -0,0 +1,78 @@
+package intelligence
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Disagreement between what a provider advertises on chain and what its own
+// status inventory reports. Either side may be wrong, but a large divergence
+// points at a misconfigured or dishonest provider.
+type ResourceDivergence struct {
+	Resource   string  `json:"resource"`
+	Advertised string  `json:"advertised"`
+	Observed   string  `json:"observed"`
+	Severity   float64 `json:"severity"` // 0-1, scales the configured penalty
+}
+
+// Compare advertised resources against the status inventory. Providers
+// without status data can't diverge: there is nothing observed to compare.
+func detectResourceDivergence(provider *akash.ProviderInfo) []ResourceDivergence {
+	cluster := provider.ClusterInfo
+	if cluster == nil {
+		return nil
+	}
+
+	var divergences []ResourceDivergence
+
+	advertised := advertisedGPUs(provider.Attributes)
+	observed := cluster.TotalResources.GPU
+	switch {
+	case advertised != "" && observed == 0:
+		// The attributes attract GPU workloads the cluster cannot run
+		divergences = append(divergences, ResourceDivergence{
+			Resource:   "gpu",
+			Advertised: advertised,
+			Observed:   "no GPUs in status inventory",
+			Severity:   1,
+		})
+	case advertised == "" && observed > 0:
+		// Under-advertising only hides capacity, so it counts for less
+		divergences = append(divergences, ResourceDivergence{
+			Resource:   "gpu",
+			Advertised: "no GPU capabilities",
+			Observed:   fmt.Sprintf("%d GPUs in status inventory", observed),
+			Severity:   0.5,
+		})
+	}
+
+	return divergences
+}
+
+// Describe the GPUs a provider advertises, or "" if it advertises none
+func advertisedGPUs(attributes map[string]string) string {
+	if models := akash.ParseGPUModels(attributes); len(models) > 0 {
+		return "GPU models " + strings.Join(models, ", ")
+	}
+	for key := range attributes {
+		if strings.HasPrefix(key, "capabilities/gpu/") {
+			return "GPU capabilities"
+		}
+	}
+	return ""
+}
+
+// Score penalty for the worst divergence; zero unless a penalty is configured
+func (s *Service) divergencePenalty(divergences []ResourceDivergence) float64 {
+	worst := 0.0
+	for _, divergence := range divergences {
+		worst = max(worst, divergence.Severity)
+	}
+	return worst * s.config.DivergencePenalty
+}
+
+func describeDivergence(divergence ResourceDivergence) string {
+	return fmt.Sprintf("advertises %s, but %s", divergence.Advertised, divergence.Observed)
+}
@@ -0,0 +1,77 @@
+package intelligence
+
+import "fmt"
+
+// Providers returned in a single response when not configured
+const defaultMaxResultProviders = 1000
+
+// Window of a provider result requested by a client; a zero Limit means the configured maximum
+type Page struct {
+	Offset int
+	Limit  int
+}
+
+// Marks a response that holds only part of the result
+type Truncation struct {
+	Total      int    `json:"total"`
+	Returned   int    `json:"returned"`
+	Offset     int    `json:"offset"`
+	NextOffset *int   `json:"next_offset,omitempty"`
+	Hint       string `json:"hint"`
+}
+
+// Get the maximum number of providers returned in a single response
+func (s *Service) MaxResultProviders() int {
+	if s.config.MaxResultProviders > 0 {
+		return s.config.MaxResultProviders
+	}
+	return defaultMaxResultProviders
+}
+
+// Cut a result down to the requested page, capped at the configured maximum.
+// Truncation is nil when the whole result is returned.
+func paginate[T any](s *Service, items []T, page Page) ([]T, *Truncation) {
+	limit := s.MaxResultProviders()
+	if page.Limit > 0 && page.Limit < limit {
+		limit = page.Limit
+	}
+	offset := page.Offset
+	if offset < 0 {
+		offset = 0
+	}
+	if offset == 0 && len(items) <= limit {
+		return items, nil
+	}
+
+	start := min(offset, len(items))
+	end := min(start+limit, len(items))
+
+	truncation := &Truncation{
+		Total:    len(items),
+		Returned: end - start,
+		Offset:   start,
+		Hint:     "result is complete from this offset",
+	}
+	if end < len(items) {
+		next := end
+		truncation.NextOffset = &next
+		truncation.Hint = fmt.Sprintf("%d more providers; repeat the call with offset %d for the next page", len(items)-end, next)
+	}
+	return items[start:end], truncation
+}
+
+// Pointer given when a selection's candidate list is cut
+const selectionTruncationHint = "all_providers lists only the first candidates; use get_provider_intelligence with offset and limit for the full set"
+
+// Cap a provider list embedded in a response that can't be paged itself
+func capProviders[T any](s *Service, items []T, hint string) ([]T, *Truncation) {
+	limit := s.MaxResultProviders()
+	if len(items) <= limit {
+		return items, nil
+	}
+	return items[:limit], &Truncation{
+		Total:    len(items),
+		Returned: limit,
+		Hint:     hint,
+	}
+}
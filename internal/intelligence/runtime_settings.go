@@ -0,0 +1,48 @@
+package intelligence
+
+import (
+	"fmt"
+	"time"
+)
+
+// Settings that can change while the service runs, without losing the cache
+type RuntimeSettings struct {
+	CacheTTL           time.Duration
+	LeaderboardWeights Weights
+}
+
+// Apply new runtime settings. Entries already cached keep the expiry they were
+// stored with; the new TTL applies from their next refresh.
+func (s *Service) Reconfigure(settings RuntimeSettings) error {
+	if settings.CacheTTL <= 0 {
+		return fmt.Errorf("cache TTL must be positive, got %v", settings.CacheTTL)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.config.CacheTTL = settings.CacheTTL
+	s.config.LeaderboardWeights = settings.LeaderboardWeights
+	return nil
+}
+
+func (s *Service) cacheTTL() time.Duration {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.config.CacheTTL
+}
+
+func (s *Service) leaderboardWeights() Weights {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.config.LeaderboardWeights
+}
+
+// Get the runtime settings in effect
+func (s *Service) CurrentSettings() RuntimeSettings {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return RuntimeSettings{
+		CacheTTL:           s.config.CacheTTL,
+		LeaderboardWeights: s.config.LeaderboardWeights,
+	}
+}
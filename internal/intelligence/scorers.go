@@ -0,0 +1,108 @@
+package intelligence
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// ProviderScorer is the contract for custom scoring plugins.
+//
+// Score returns a value between 0 and 1 where higher is better; values outside
+// that range are clamped. Scorers are invoked concurrently across requests and
+// must be safe for concurrent use. A returned error drops the scorer's
+// contribution for that provider (it scores 0) without failing the selection.
+type ProviderScorer interface {
+	Score(ctx context.Context, provider *akash.ProviderInfo) (float64, error)
+}
+
+// ScorerFunc adapts an ordinary function to the ProviderScorer interface
+type ScorerFunc func(ctx context.Context, provider *akash.ProviderInfo) (float64, error)
+
+func (f ScorerFunc) Score(ctx context.Context, provider *akash.ProviderInfo) (float64, error) {
+	return f(ctx, provider)
+}
+
+// ScorerWeight references a registered scorer in the scoring pipeline
+type ScorerWeight struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+}
+
+var (
+	scorerRegistry = make(map[string]ProviderScorer)
+	scorerMutex    sync.RWMutex
+)
+
+// RegisterScorer makes a named scorer available to the scoring pipeline.
+// It must be called at startup, before NewService validates the configured scorers:
+//
+//	intelligence.RegisterScorer("reputation", intelligence.ScorerFunc(
+//		func(ctx context.Context, p *akash.ProviderInfo) (float64, error) {
+//			return reputationAPI.Lookup(ctx, p.Address)
+//		}))
+func RegisterScorer(name string, scorer ProviderScorer) error {
+	if name == "" {
+		return fmt.Errorf("scorer name is required")
+	}
+	if scorer == nil {
+		return fmt.Errorf("scorer %q is nil", name)
+	}
+
+	scorerMutex.Lock()
+	defer scorerMutex.Unlock()
+
+	if _, exists := scorerRegistry[name]; exists {
+		return fmt.Errorf("scorer %q is already registered", name)
+	}
+	scorerRegistry[name] = scorer
+	return nil
+}
+
+// Look up a registered scorer by name
+func lookupScorer(name string) (ProviderScorer, bool) {
+	scorerMutex.RLock()
+	defer scorerMutex.RUnlock()
+
+	scorer, ok := scorerRegistry[name]
+	return scorer, ok
+}
+
+// Run the configured custom scorers for a provider, returning each raw score and the weighted total
+func (s *Service) calculateCustomScores(ctx context.Context, provider *akash.ProviderInfo) (map[string]float64, float64) {
+	if len(s.config.CustomScorers) == 0 {
+		return nil, 0
+	}
+
+	scores := make(map[string]float64, len(s.config.CustomScorers))
+	total := 0.0
+
+	for _, configured := range s.config.CustomScorers {
+		scorer, ok := lookupScorer(configured.Name)
+		if !ok {
+			continue
+		}
+
+		score, err := scorer.Score(ctx, provider)
+		if err != nil {
+			slog.WarnContext(ctx, "Scoring plugin failed", "scorer", configured.Name, "provider", provider.Address, "error", err)
+			reportDegraded(ctx, "scorer:"+configured.Name,
+				fmt.Sprintf("scoring plugin failed (%v); its dimension scored 0", err))
+			score = 0
+		}
+		if score < 0 {
+			score = 0
+		}
+		if score > 1 {
+			score = 1
+		}
+
+		scores[configured.Name] = score
+		total += score * configured.Weight
+	}
+
+	return scores, total
+}
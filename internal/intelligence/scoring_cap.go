@@ -0,0 +1,60 @@
+package intelligence
+
+import (
+	"fmt"
+)
+
+// Built-in cap on providers fully scored per selection request
+const defaultMaxScoredProviders = 5000
+
+// What happens when a selection names more providers than the cap
+const (
+	ScoringCapReject = "reject" // fail the request
+	ScoringCapPrior  = "prior"  // score only the top candidates by fetch prior
+)
+
+func ValidateScoringCapPolicy(policy string) error {
+	switch policy {
+	case "", ScoringCapReject, ScoringCapPrior:
+		return nil
+	}
+	return fmt.Errorf("unknown scoring cap policy %q (valid: %s, %s)", policy, ScoringCapReject, ScoringCapPrior)
+}
+
+// Set when a selection request was cut down to the scoring cap
+type ScoringCap struct {
+	Limit     int    `json:"limit"`
+	Requested int    `json:"requested"`
+	Skipped   int    `json:"skipped"`
+	Policy    string `json:"policy"`
+}
+
+// Most providers fully scored per selection request
+func (s *Service) MaxScoredProviders() int {
+	if s.config.MaxScoredProviders > 0 {
+		return s.config.MaxScoredProviders
+	}
+	return defaultMaxScoredProviders
+}
+
+// Enforce the scoring cap on a selection's candidates before anything is fetched.
+// Under the prior policy the likeliest winners are kept, by hint or last known
+// health score; otherwise an oversized request is rejected.
+func (s *Service) capCandidates(candidates []string, criteria SelectionCriteria) ([]string, *ScoringCap, error) {
+	limit := s.MaxScoredProviders()
+	if len(candidates) <= limit {
+		return candidates, nil, nil
+	}
+
+	if s.config.ScoringCapPolicy != ScoringCapPrior {
+		return nil, nil, fmt.Errorf("selection names %d providers, more than the %d that can be scored per request; narrow the candidate set",
+			len(candidates), limit)
+	}
+
+	return s.orderByPrior(candidates, criteria.FetchHints)[:limit], &ScoringCap{
+		Limit:     limit,
+		Requested: len(candidates),
+		Skipped:   len(candidates) - limit,
+		Policy:    ScoringCapPrior,
+	}, nil
+}
@@ -0,0 +1,280 @@
+package intelligence
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Score of a provider reconstructed from a historical observation
+type HistoricalScore struct {
+	Address    string              `json:"address"`
+	Score      float64             `json:"score"`
+	Breakdown  ScoreBreakdown      `json:"breakdown"`
+	ObservedAt time.Time           `json:"observed_at"`
+	Info       *akash.ProviderInfo `json:"-"`
+}
+
+// Explanation of why the selected provider differs between two points in time
+type SelectionChange struct {
+	From            time.Time         `json:"from"`
+	To              time.Time         `json:"to"`
+	PreviousWinner  string            `json:"previous_winner"`
+	CurrentWinner   string            `json:"current_winner"`
+	WinnerChanged   bool              `json:"winner_changed"`
+	DecisiveFactor  string            `json:"decisive_factor,omitempty"`
+	Explanation     string            `json:"explanation"`
+	PreviousRanking []HistoricalScore `json:"previous_ranking"`
+	CurrentRanking  []HistoricalScore `json:"current_ranking"`
+	MissingHistory  []string          `json:"missing_history,omitempty"`
+
+	Degraded                bool                    `json:"degraded"`
+	UnavailableCapabilities []UnavailableCapability `json:"unavailable_capabilities,omitempty"`
+}
+
+// Weighted contribution of a single scoring dimension
+type dimensionContribution struct {
+	Name         string
+	Value        float64
+	Contribution float64
+}
+
+// Break a provider's total score into the weighted contribution of each dimension
+func (s *Service) dimensionContributions(breakdown ScoreBreakdown, criteria SelectionCriteria) []dimensionContribution {
+	contributions := []dimensionContribution{
+		{"reliability", breakdown.HealthScore, breakdown.HealthScore * criteria.Weights.Reliability},
+		{"performance", breakdown.PerformanceScore, breakdown.PerformanceScore * criteria.Weights.Performance},
+		{"geographic", breakdown.GeographicScore, breakdown.GeographicScore * criteria.Weights.Geographic},
+		{"price", breakdown.PriceScore, breakdown.PriceScore * criteria.Weights.Price},
+		{"priority", breakdown.PriorityBonus, breakdown.PriorityBonus},
+		{"stability", breakdown.StabilityScore, breakdown.StabilityScore * criteria.Weights.Stability * stabilityDurationFactor(criteria.DeploymentDuration)},
+		{"provisioning", breakdown.ProvisioningScore, breakdown.ProvisioningScore * criteria.Weights.Provisioning},
+		{"network", breakdown.NetworkScore, breakdown.NetworkScore * criteria.Weights.Network},
+		{"maintenance", breakdown.MaintenancePenalty, -breakdown.MaintenancePenalty},
+		{"resource_divergence", breakdown.DivergencePenalty, -breakdown.DivergencePenalty},
+	}
+
+	for _, configured := range s.config.CustomScorers {
+		value := breakdown.CustomScores[configured.Name]
+		contributions = append(contributions, dimensionContribution{
+			Name:         "plugin:" + configured.Name,
+			Value:        value,
+			Contribution: value * configured.Weight,
+		})
+	}
+
+	return contributions
+}
+
+// Explain which factor changed the selected provider between two points in time
+func (s *Service) ExplainSelectionChange(ctx context.Context, addresses []string, criteria SelectionCriteria, from, to time.Time) (change *SelectionChange, err error) {
+	ctx, degraded := withDegradation(ctx)
+	defer func() {
+		if change != nil {
+			change.Degraded, change.UnavailableCapabilities = degraded.report()
+		}
+	}()
+
+	if s.history == nil {
+		return nil, fmt.Errorf("historical store is disabled")
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("from (%s) must be before to (%s)", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	previous, missingFrom := s.scoreAt(ctx, addresses, criteria, from)
+	current, missingTo := s.scoreAt(ctx, addresses, criteria, to)
+
+	if len(previous) == 0 {
+		return nil, fmt.Errorf("no historical data for any candidate at %s", from.Format(time.RFC3339))
+	}
+	if len(current) == 0 {
+		return nil, fmt.Errorf("no historical data for any candidate at %s", to.Format(time.RFC3339))
+	}
+
+	change = &SelectionChange{
+		From:            from,
+		To:              to,
+		PreviousWinner:  previous[0].Address,
+		CurrentWinner:   current[0].Address,
+		PreviousRanking: previous,
+		CurrentRanking:  current,
+	}
+	for _, addr := range missingFrom {
+		change.MissingHistory = append(change.MissingHistory, fmt.Sprintf("%s (at %s)", addr, from.Format(time.RFC3339)))
+	}
+	for _, addr := range missingTo {
+		change.MissingHistory = append(change.MissingHistory, fmt.Sprintf("%s (at %s)", addr, to.Format(time.RFC3339)))
+	}
+
+	if change.PreviousWinner == change.CurrentWinner {
+		change.Explanation = fmt.Sprintf("%s was selected at both points in time (score %.3f → %.3f)",
+			change.CurrentWinner, previous[0].Score, current[0].Score)
+		return change, nil
+	}
+	change.WinnerChanged = true
+
+	prevThen, prevNow := &previous[0], findHistoricalScore(current, change.PreviousWinner)
+	currNow, currThen := &current[0], findHistoricalScore(previous, change.CurrentWinner)
+
+	// A winner that was not observed at the other time point changed the candidate set itself
+	if prevNow == nil {
+		change.DecisiveFactor = "availability"
+		change.Explanation = fmt.Sprintf("%s has no observations at %s, so %s was selected instead",
+			change.PreviousWinner, to.Format(time.RFC3339), change.CurrentWinner)
+		return change, nil
+	}
+	if currThen == nil {
+		change.DecisiveFactor = "availability"
+		change.Explanation = fmt.Sprintf("%s had no observations at %s and was not a candidate then; it now outscores %s (%.3f vs %.3f)",
+			change.CurrentWinner, from.Format(time.RFC3339), change.PreviousWinner, currNow.Score, prevNow.Score)
+		return change, nil
+	}
+
+	// Find the dimension whose change moved the gap between the two winners the most
+	prevThenDims := s.dimensionContributions(prevThen.Breakdown, criteria)
+	prevNowDims := s.dimensionContributions(prevNow.Breakdown, criteria)
+	currThenDims := s.dimensionContributions(currThen.Breakdown, criteria)
+	currNowDims := s.dimensionContributions(currNow.Breakdown, criteria)
+
+	decisive := -1
+	var decisiveShift float64
+	var others []string
+	for i := range prevThenDims {
+		gapThen := currThenDims[i].Contribution - prevThenDims[i].Contribution
+		gapNow := currNowDims[i].Contribution - prevNowDims[i].Contribution
+		shift := gapNow - gapThen
+
+		if shift > decisiveShift {
+			if decisive >= 0 && decisiveShift > 0.001 {
+				others = append(others, fmt.Sprintf("%s (+%.3f)", prevThenDims[decisive].Name, decisiveShift))
+			}
+			decisive, decisiveShift = i, shift
+		} else if shift > 0.001 {
+			others = append(others, fmt.Sprintf("%s (+%.3f)", prevThenDims[i].Name, shift))
+		}
+	}
+
+	if decisive < 0 {
+		change.Explanation = fmt.Sprintf("%s overtook %s (%.3f vs %.3f) without any single dimension moving in its favour",
+			change.CurrentWinner, change.PreviousWinner, currNow.Score, prevNow.Score)
+		return change, nil
+	}
+
+	dimension := prevThenDims[decisive].Name
+	change.DecisiveFactor = dimension
+
+	// Attribute the shift to whichever provider moved more on that dimension
+	prevDelta := prevNowDims[decisive].Contribution - prevThenDims[decisive].Contribution
+	currDelta := currNowDims[decisive].Contribution - currThenDims[decisive].Contribution
+	if -prevDelta >= currDelta {
+		change.Explanation = fmt.Sprintf("%s's %s degraded (%s), dropping it below %s (%.3f vs %.3f)",
+			change.PreviousWinner, dimension, describeDimensionChange(dimension, prevThen, prevNow),
+			change.CurrentWinner, prevNow.Score, currNow.Score)
+	} else {
+		change.Explanation = fmt.Sprintf("%s's %s improved (%s), lifting it above %s (%.3f vs %.3f)",
+			change.CurrentWinner, dimension, describeDimensionChange(dimension, currThen, currNow),
+			change.PreviousWinner, currNow.Score, prevNow.Score)
+	}
+
+	if len(others) > 0 {
+		change.Explanation += fmt.Sprintf("; also contributing: %s", strings.Join(others, ", "))
+	}
+
+	return change, nil
+}
+
+// Score every candidate from its latest observation at or before the given time
+func (s *Service) scoreAt(ctx context.Context, addresses []string, criteria SelectionCriteria, at time.Time) ([]HistoricalScore, []string) {
+	var scores []HistoricalScore
+	var missing []string
+
+	var snapshots []*ProviderSnapshot
+	var infos []*akash.ProviderInfo
+	for _, addr := range addresses {
+		snapshot, ok := s.history.SnapshotAt(addr, at)
+		if !ok {
+			missing = append(missing, addr)
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+		infos = append(infos, snapshot.Info)
+	}
+
+	// Advertised prices are compared across the candidates observed at that time
+	criteria.pricingReferences = advertisedPriceReferences(infos)
+
+	for _, snapshot := range snapshots {
+		addr := snapshot.Info.Address
+		score, breakdown := s.scoreProviderWithBreakdown(ctx, snapshot.Info, criteria)
+		scores = append(scores, HistoricalScore{
+			Address:    addr,
+			Score:      score,
+			Breakdown:  breakdown,
+			ObservedAt: snapshot.ObservedAt,
+			Info:       snapshot.Info,
+		})
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Score > scores[j].Score
+	})
+
+	return scores, missing
+}
+
+// Find a provider in a historical ranking
+func findHistoricalScore(scores []HistoricalScore, address string) *HistoricalScore {
+	for i := range scores {
+		if scores[i].Address == address {
+			return &scores[i]
+		}
+	}
+	return nil
+}
+
+// Describe how the raw inputs behind a dimension changed between two observations
+func describeDimensionChange(dimension string, then, now *HistoricalScore) string {
+	switch dimension {
+	case "reliability":
+		return fmt.Sprintf("health score %.2f → %.2f", then.Breakdown.HealthScore, now.Breakdown.HealthScore)
+	case "performance":
+		if then.Info.StatusQueryTime > 0 && now.Info.StatusQueryTime > 0 {
+			return fmt.Sprintf("status latency %v → %v",
+				then.Info.StatusQueryTime.Round(time.Millisecond), now.Info.StatusQueryTime.Round(time.Millisecond))
+		}
+		if then.Info.StatusQueryTime > 0 && now.Info.StatusQueryTime == 0 {
+			return "status endpoint became unreachable"
+		}
+		return fmt.Sprintf("performance score %.2f → %.2f", then.Breakdown.PerformanceScore, now.Breakdown.PerformanceScore)
+	case "geographic":
+		return fmt.Sprintf("region %q → %q", then.Info.Attributes["region"], now.Info.Attributes["region"])
+	case "price":
+		return fmt.Sprintf("price score %.2f → %.2f", then.Breakdown.PriceScore, now.Breakdown.PriceScore)
+	case "priority":
+		return fmt.Sprintf("priority bonus %.2f → %.2f", then.Breakdown.PriorityBonus, now.Breakdown.PriorityBonus)
+	case "stability":
+		return fmt.Sprintf("lease stability %.2f → %.2f", then.Breakdown.StabilityScore, now.Breakdown.StabilityScore)
+	case "provisioning":
+		if then.Breakdown.Provisioning != nil && now.Breakdown.Provisioning != nil {
+			return fmt.Sprintf("estimated time-to-provision %v → %v", then.Breakdown.Provisioning.Estimate, now.Breakdown.Provisioning.Estimate)
+		}
+		return fmt.Sprintf("provisioning score %.2f → %.2f", then.Breakdown.ProvisioningScore, now.Breakdown.ProvisioningScore)
+	case "network":
+		if then.Breakdown.Network != nil && now.Breakdown.Network != nil {
+			return fmt.Sprintf("%s → %s", describeNetwork(then.Breakdown.Network), describeNetwork(now.Breakdown.Network))
+		}
+		return fmt.Sprintf("network score %.2f → %.2f", then.Breakdown.NetworkScore, now.Breakdown.NetworkScore)
+	case "maintenance":
+		return fmt.Sprintf("maintenance penalty %.2f → %.2f", then.Breakdown.MaintenancePenalty, now.Breakdown.MaintenancePenalty)
+	case "resource_divergence":
+		return fmt.Sprintf("resource divergence penalty %.2f → %.2f", then.Breakdown.DivergencePenalty, now.Breakdown.DivergencePenalty)
+	}
+
+	name := strings.TrimPrefix(dimension, "plugin:")
+	return fmt.Sprintf("%s score %.2f → %.2f", name, then.Breakdown.CustomScores[name], now.Breakdown.CustomScores[name])
+}
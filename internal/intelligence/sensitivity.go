@@ -0,0 +1,131 @@
+package intelligence
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Default weight perturbation used by the sensitivity analysis
+const defaultSensitivityStep = 0.05
+
+// How the winner responds to changing a single weight
+type WeightSensitivity struct {
+	Weight string  `json:"weight"`
+	Value  float64 `json:"value"`
+
+	// Winner after moving the weight up and down by the step; the margin is the
+	// winner's lead over the runner-up, so a different winner means it flipped
+	WinnerOnIncrease string  `json:"winner_on_increase"`
+	MarginOnIncrease float64 `json:"margin_on_increase"`
+	WinnerOnDecrease string  `json:"winner_on_decrease"`
+	MarginOnDecrease float64 `json:"margin_on_decrease"`
+
+	// Smallest signed change to this weight that changes the winner, and to whom;
+	// absent when no change keeps the weight non-negative and flips the winner
+	FlipThreshold *float64 `json:"flip_threshold,omitempty"`
+	FlipsTo       string   `json:"flips_to,omitempty"`
+}
+
+// Sensitivity of the selected provider to each scoring weight
+type SensitivityAnalysis struct {
+	Winner string  `json:"winner"`
+	Margin float64 `json:"margin"`
+	Step   float64 `json:"step"`
+
+	// Most decisive weight (smallest flip threshold) first
+	Weights []WeightSensitivity `json:"weights"`
+}
+
+// Perturb each built-in weight by ±step, rescore the candidates and work out
+// how far each weight would have to move before another provider wins.
+// Scores are linear in each weight, so the threshold follows from the slopes
+// observed in the perturbed scoring.
+func (s *Service) analyzeSensitivity(ctx context.Context, scored []ScoredProvider, criteria SelectionCriteria, step float64) *SensitivityAnalysis {
+	if len(scored) == 0 {
+		return nil
+	}
+	if step <= 0 {
+		step = defaultSensitivityStep
+	}
+
+	providers := make([]*akash.ProviderInfo, len(scored))
+	base := make(map[string]float64, len(scored))
+	for i, candidate := range scored {
+		providers[i] = candidate.Provider
+		base[candidate.Provider.Address] = candidate.Score
+	}
+
+	winner := scored[0].Provider.Address
+	analysis := &SensitivityAnalysis{
+		Winner: winner,
+		Step:   step,
+	}
+	if len(scored) > 1 {
+		analysis.Margin = scored[0].Score - scored[1].Score
+	}
+
+	for _, name := range weightNames {
+		value := *criteria.Weights.field(name)
+		sensitivity := WeightSensitivity{Weight: name, Value: value}
+
+		increased := s.scoreProviders(ctx, providers, withWeight(criteria, name, value+step))
+		sensitivity.WinnerOnIncrease, sensitivity.MarginOnIncrease = winnerAndMargin(increased)
+
+		decreased := s.scoreProviders(ctx, providers, withWeight(criteria, name, math.Max(value-step, 0)))
+		sensitivity.WinnerOnDecrease, sensitivity.MarginOnDecrease = winnerAndMargin(decreased)
+
+		// Each provider's score changes by slope × weight change
+		slopes := make(map[string]float64, len(increased))
+		for _, candidate := range increased {
+			slopes[candidate.Provider.Address] = (candidate.Score - base[candidate.Provider.Address]) / step
+		}
+
+		for _, candidate := range scored[1:] {
+			addr := candidate.Provider.Address
+			gap := base[winner] - base[addr]
+			relative := slopes[addr] - slopes[winner]
+			if relative == 0 {
+				continue
+			}
+
+			threshold := gap / relative
+			if value+threshold < 0 {
+				continue // would need a negative weight
+			}
+			if sensitivity.FlipThreshold == nil || math.Abs(threshold) < math.Abs(*sensitivity.FlipThreshold) {
+				t := threshold
+				sensitivity.FlipThreshold = &t
+				sensitivity.FlipsTo = addr
+			}
+		}
+
+		analysis.Weights = append(analysis.Weights, sensitivity)
+	}
+
+	sort.SliceStable(analysis.Weights, func(i, j int) bool {
+		a, b := analysis.Weights[i].FlipThreshold, analysis.Weights[j].FlipThreshold
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return math.Abs(*a) < math.Abs(*b)
+	})
+
+	return analysis
+}
+
+// Copy criteria with a single weight replaced
+func withWeight(criteria SelectionCriteria, name string, value float64) SelectionCriteria {
+	*criteria.Weights.field(name) = value
+	return criteria
+}
+
+// Top provider of a ranking and its lead over the runner-up
+func winnerAndMargin(scored []ScoredProvider) (string, float64) {
+	if len(scored) < 2 {
+		return scored[0].Provider.Address, 0
+	}
+	return scored[0].Provider.Address, scored[0].Score - scored[1].Score
+}
@@ -3,27 +3,181 @@ package intelligence
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"sort"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 
 	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+	"github.com/chainzero/akash-provider-intelligence/internal/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/chainzero/akash-provider-intelligence/internal/intelligence")
+
 type Config struct {
-	AkashGRPCEndpoint   string
-	CacheTTL            time.Duration
+	AkashGRPCEndpoints []string
+	// Queried while no gRPC endpoint is reachable; empty disables the fallback
+	AkashRPCEndpoint  string
+	AkashGRPCSecurity akash.GRPCSecurity
+	CacheTTL          time.Duration
+	// Provider cache saved here by Close and reloaded on start; empty keeps it in memory only
+	CacheFile           string
 	StatusTimeout       time.Duration
 	MaxConcurrent       int
 	HealthCheckInterval time.Duration
+
+	// Per provider blockchain query, whole provider fetch and batch timeouts
+	ChainTimeout    time.Duration
+	ProviderTimeout time.Duration
+	BatchTimeout    time.Duration
+
+	StatusRetry    akash.RetryPolicy
+	ChainRetry     akash.RetryPolicy
+	CircuitBreaker akash.CircuitBreakerConfig
+	CustomScorers  []ScorerWeight
+
+	// Additional provider endpoints fetched concurrently with /status
+	ExtraStatusEndpoints []string
+
+	// Sanity bounds on provider-reported inventory
+	ResourceLimits akash.ResourceLimits
+
+	// Status response field mappings for provider versions beyond the built-in ones
+	StatusSchemas []akash.StatusSchema
+
+	// Active lease contribution to the health score
+	LeaseScoring akash.LeaseScoring
+
+	// Provider list pages fetched concurrently when enumerating the network
+	ListConcurrency int
+
+	// Most providers returned in a single response (default 1000); larger
+	// results are truncated with pagination metadata
+	MaxResultProviders int
+
+	// Most providers a selection request fully scores (default 5000), and what
+	// happens beyond it: reject (default) or prior (keep the likeliest winners)
+	MaxScoredProviders int
+	ScoringCapPolicy   string
+
+	// Default fetch queue order: input (default), prior or stalest
+	FetchOrder string
+
+	// GPU availability fast path: reading TTL (default 30s) and per-provider
+	// timeout (default 2s)
+	GPUCacheTTL time.Duration
+	GPUTimeout  time.Duration
+
+	// Keep providers without status data when a minimum node count is required
+	IncludeUnknownNodeCount bool
+
+	// Providers without an advertised CPU architecture when one is required:
+	// assume_x86 (default) or exclude
+	UnknownArchPolicy string
+
+	// Default reasoning style (rich, plain, markdown, html or custom) and
+	// units (binary or decimal)
+	ReasoningStyle string
+	ReasoningUnits string
+
+	// Go text/template over ReasoningData used by the custom style
+	ReasoningTemplate string
+
+	// Weight of the newest observation in the health score moving average;
+	// zero disables smoothing
+	HealthSmoothingAlpha float64
+
+	// Treatment of providers whose advertised maintenance is ongoing or starts
+	// within the look-ahead (default 24h): penalize (default) or exclude
+	MaintenanceLookahead time.Duration
+	MaintenancePolicy    string
+	MaintenancePenalty   float64
+
+	// Subtracted, scaled by severity, from providers whose advertised and
+	// observed resources disagree; zero only reports the divergence
+	DivergencePenalty float64
+
+	// Historical store of provider observations; zero retention disables it
+	HistoryRetention  time.Duration
+	HistoryMaxSamples int
+
+	// Interval between network-wide stats samples; zero disables sampling
+	NetworkStatsInterval time.Duration
+
+	// Scoring of dimensions that lack status data: penalize (PartialDataScore,
+	// default 0) or neutral (0.5)
+	PartialDataPolicy string
+	PartialDataScore  float64
+
+	// Share of the performance score earned from blockchain query time (0-1).
+	// That time reflects our gRPC endpoint rather than the provider, so it
+	// defaults to 0; 0.2 restores the original weighting.
+	BlockchainQueryWeight float64
+
+	// How long identical selections are served from the recommendation cache;
+	// zero disables it
+	RecommendationCacheTTL time.Duration
+
+	// Attribute keys (glob patterns, e.g. "capabilities/gpu/*") ignored by scoring
+	AttributeDenylist []string
+
+	// How often the host URI to address index is rebuilt (default 10m)
+	HostIndexRefresh time.Duration
+
+	// Background leaderboard: refresh interval (zero disables it), entries
+	// kept (default 100) and scoring weights (defaults when unset)
+	LeaderboardInterval time.Duration
+	LeaderboardSize     int
+	LeaderboardWeights  Weights
+
+	// Subscriptions: how often watched providers are refreshed (zero leaves
+	// them to other callers) and the health score movement that counts as a
+	// change (default 0.05)
+	SubscriptionRefresh     time.Duration
+	SubscriptionHealthDelta float64
+
+	// Providers permanently excluded from selection
+	Blacklist []string
+
+	// Capability tier classification rules
+	TierRules []TierRule
+
+	// Zero the geographic weight when fewer than this share of candidates report
+	// a region (0 disables), redistributing it proportionally (default) or not at all
+	GeoMinCoverage    float64
+	GeoRedistribution string
+
+	// Providers whose best possible score is below this floor are skipped
+	// before full scoring; zero disables early exclusion
+	ScoreFloor float64
 }
 
 type Service struct {
 	config      *Config
 	akashClient *akash.Client
 	cache       *ProviderCache
-	mutex       sync.RWMutex
+	history     *HistoryStore
+	blacklist   *Blacklist
+	smoother    *HealthSmoother
+	hostIndex   *HostIndex
+	gpuCache    *GPUCache
+	leaderboard leaderboardState
+	rebuild     rebuildState
+	updates     updateFeed
+	// Synthetic failures, only ever installed by debug builds
+	injector *FailureInjector
+	// Built-in and custom reasoning templates, by style
+	reasoningTemplates *template.Template
+	// Recent selections, nil when the recommendation cache is disabled
+	recommendations *RecommendationCache
+	lifecycle       lifecycle
+	mutex           sync.RWMutex
 }
 
 type ProviderCache struct {
@@ -38,27 +192,96 @@ type CachedProvider struct {
 	ExpiresAt time.Time           `json:"expires_at"`
 }
 
+// Provider intelligence split into reachable providers and providers that could not be fetched
+type IntelligenceResult struct {
+	Providers               []*akash.ProviderInfo   `json:"providers"`
+	FailedProviders         []*akash.FailedProvider `json:"failed_providers"`
+	Degraded                bool                    `json:"degraded"`
+	UnavailableCapabilities []UnavailableCapability `json:"unavailable_capabilities,omitempty"`
+
+	// Set when only a page of the requested providers was fetched
+	Truncation *Truncation `json:"truncation,omitempty"`
+
+	// Set when a max_data_age was requested
+	Freshness *FreshnessGuarantee `json:"freshness,omitempty"`
+
+	// ID of the request this was fetched for, see telemetry.RequestID
+	RequestID string `json:"request_id,omitempty"`
+}
+
 type ProviderSelection struct {
-	SelectedProvider string                 `json:"selected_provider"`
-	Score            float64                `json:"score"`
-	Reasoning        string                 `json:"reasoning"`
-	AllProviders     []*akash.ProviderInfo  `json:"all_providers"`
-	Criteria         SelectionCriteria      `json:"criteria"`
-	Stats            map[string]interface{} `json:"stats"`
-	QueryTime        time.Duration          `json:"query_time"`
+	SelectedProvider string                `json:"selected_provider"`
+	Score            float64               `json:"score"`
+	Confidence       *SelectionConfidence  `json:"confidence"`
+	Reasoning        string                `json:"reasoning"`
+	ReasoningData    *ReasoningData        `json:"reasoning_data,omitempty"`
+	AllProviders     []*akash.ProviderInfo `json:"all_providers"`
+	// Set when all_providers was cut to the response limit
+	AllProvidersTruncation *Truncation             `json:"all_providers_truncation,omitempty"`
+	FailedProviders        []*akash.FailedProvider `json:"failed_providers"`
+	ExcludedProviders      []string                `json:"excluded_providers,omitempty"`
+	FilteredProviders      []FilteredProvider      `json:"filtered_providers,omitempty"`
+	PrunedProviders        []PrunedProvider        `json:"pruned_providers,omitempty"`
+	Criteria               SelectionCriteria       `json:"criteria"`
+	Stats                  map[string]interface{}  `json:"stats"`
+	QueryTime              time.Duration           `json:"query_time"`
+
+	Degraded                bool                    `json:"degraded"`
+	UnavailableCapabilities []UnavailableCapability `json:"unavailable_capabilities,omitempty"`
+
+	Freshness *FreshnessGuarantee `json:"freshness,omitempty"`
+
+	// Set when only part of the candidates were scored, see Config.MaxScoredProviders
+	ScoringCap *ScoringCap `json:"scoring_cap,omitempty"`
+
+	// Top providers in the order most likely to reach a running deployment soonest
+	TryOrder []TryOrderEntry `json:"try_order,omitempty"`
+
+	// Served from the recommendation cache, computed at CachedAt
+	Cached   bool       `json:"cached,omitempty"`
+	CachedAt *time.Time `json:"cached_at,omitempty"`
+
+	// ID of the request that asked for this selection, to find its logs and trace
+	RequestID string `json:"request_id,omitempty"`
 }
 
 type SelectionCriteria struct {
-	Priority string  `json:"priority"`
-	Budget   float64 `json:"budget"`
-	Weights  Weights `json:"weights"`
+	Priority           string        `json:"priority"`
+	Budget             float64       `json:"budget"`
+	DeploymentDuration time.Duration `json:"deployment_duration,omitempty"`
+	MaxDataAge         time.Duration `json:"max_data_age,omitempty"`
+	FetchOrder         string        `json:"fetch_order,omitempty"`
+	MinAvailableNodes  int           `json:"min_available_nodes,omitempty"`
+	MinBandwidthMbps   float64       `json:"min_bandwidth_mbps,omitempty"`
+	CPUArch            string        `json:"cpu_arch,omitempty"`         // normalized, e.g. amd64 or arm64
+	PreferConfident    bool          `json:"prefer_confident,omitempty"` // break near ties by score certainty
+	TryOrder           int           `json:"try_order,omitempty"`        // top providers to order for failover
+	ReasoningStyle     string        `json:"reasoning_style,omitempty"`
+	Units              string        `json:"units,omitempty"`
+	Weights            Weights       `json:"weights"`
+
+	// Caller-supplied fetch priors by address, see FetchOptions
+	FetchHints map[string]float64 `json:"fetch_hints,omitempty"`
+
+	// Where each weight was resolved from, see ResolveWeights
+	WeightProvenance map[string]WeightProvenance `json:"weight_provenance,omitempty"`
+	// Set when the geographic weight was disabled for low region coverage
+	GeoAdjustment *GeoAdjustment `json:"geo_adjustment,omitempty"`
+	// Scoring is a pure function of the pinned snapshots, see SelectFromSnapshots
+	Deterministic bool       `json:"deterministic,omitempty"`
+	EvaluatedAt   *time.Time `json:"evaluated_at,omitempty"`
+	// Cheapest advertised reference cost per denom among the scored candidates
+	pricingReferences map[string]float64
 }
 
 type Weights struct {
-	Price       float64 `json:"price"`
-	Reliability float64 `json:"reliability"`
-	Performance float64 `json:"performance"`
-	Geographic  float64 `json:"geographic"`
+	Price        float64 `json:"price"`
+	Reliability  float64 `json:"reliability"`
+	Performance  float64 `json:"performance"`
+	Geographic   float64 `json:"geographic"`
+	Stability    float64 `json:"stability"`    // Only applied when a deployment duration is requested
+	Provisioning float64 `json:"provisioning"` // Estimated time-to-provision; off unless weighted
+	Network      float64 `json:"network"`      // Advertised bandwidth or tier; off unless weighted
 }
 
 type ScoredProvider struct {
@@ -68,100 +291,520 @@ type ScoredProvider struct {
 }
 
 type ScoreBreakdown struct {
-	HealthScore      float64 `json:"health_score"`
-	PerformanceScore float64 `json:"performance_score"`
-	GeographicScore  float64 `json:"geographic_score"`
-	PriceScore       float64 `json:"price_score"`
-	PriorityBonus    float64 `json:"priority_bonus"`
+	HealthScore        float64               `json:"health_score"`
+	InstantHealthScore float64               `json:"instant_health_score"`
+	PerformanceScore   float64               `json:"performance_score"`
+	GeographicScore    float64               `json:"geographic_score"`
+	PriceScore         float64               `json:"price_score"`
+	PriceSource        string                `json:"price_source"`
+	PriorityBonus      float64               `json:"priority_bonus"`
+	CustomScores       map[string]float64    `json:"custom_scores,omitempty"`
+	StabilityScore     float64               `json:"stability_score,omitempty"`
+	LeaseStability     *LeaseStability       `json:"lease_stability,omitempty"`
+	ProvisioningScore  float64               `json:"provisioning_score"`
+	Provisioning       *ProvisioningEstimate `json:"provisioning,omitempty"`
+	NetworkScore       float64               `json:"network_score"`
+	Network            *NetworkQuality       `json:"network,omitempty"`
+	MaintenancePenalty float64               `json:"maintenance_penalty,omitempty"`
+	ResourceDivergence []ResourceDivergence  `json:"resource_divergence,omitempty"`
+	DivergencePenalty  float64               `json:"divergence_penalty,omitempty"`
+	IgnoredAttributes  []string              `json:"ignored_attributes,omitempty"`
+	MissingData        []string              `json:"missing_data,omitempty"` // dimensions scored without status data
+	RawMetrics         *RawMetrics           `json:"raw_metrics,omitempty"`
+	Normalized         map[string]float64    `json:"normalized,omitempty"` // raw metrics relative to the candidate set
+	Uncertainty        *ScoreUncertainty     `json:"uncertainty,omitempty"`
 }
 
 func NewService(config *Config) (*Service, error) {
-	akashClient := akash.NewClient(config.AkashGRPCEndpoint)
+	if err := ValidateReasoningFormat(config.ReasoningStyle, config.ReasoningUnits); err != nil {
+		return nil, err
+	}
+	if config.ReasoningStyle == ReasoningStyleCustom && config.ReasoningTemplate == "" {
+		return nil, fmt.Errorf("reasoning style %q requires a reasoning template", ReasoningStyleCustom)
+	}
+	reasoningTemplates, err := parseReasoningTemplates(config.ReasoningTemplate)
+	if err != nil {
+		return nil, err
+	}
+	switch config.MaintenancePolicy {
+	case "", MaintenancePolicyPenalize, MaintenancePolicyExclude:
+	default:
+		return nil, fmt.Errorf("unknown maintenance policy %q (valid: %s, %s)",
+			config.MaintenancePolicy, MaintenancePolicyPenalize, MaintenancePolicyExclude)
+	}
+	if err := config.LeaseScoring.Validate(); err != nil {
+		return nil, err
+	}
+	if err := akash.ValidateStatusSchemas(config.StatusSchemas); err != nil {
+		return nil, err
+	}
+	if err := ValidatePartialDataPolicy(config.PartialDataPolicy, config.PartialDataScore); err != nil {
+		return nil, err
+	}
+	if err := ValidateAttributeDenylist(config.AttributeDenylist); err != nil {
+		return nil, err
+	}
+	if err := ValidateFetchOrder(config.FetchOrder); err != nil {
+		return nil, err
+	}
+	if err := ValidateUnknownArchPolicy(config.UnknownArchPolicy); err != nil {
+		return nil, err
+	}
+	if err := ValidateScoringCapPolicy(config.ScoringCapPolicy); err != nil {
+		return nil, err
+	}
+	if err := ValidateGeoRedistribution(config.GeoRedistribution); err != nil {
+		return nil, err
+	}
+	if config.GeoMinCoverage < 0 || config.GeoMinCoverage > 1 {
+		return nil, fmt.Errorf("geographic minimum coverage must be between 0 and 1, got %v", config.GeoMinCoverage)
+	}
+	if config.BlockchainQueryWeight < 0 || config.BlockchainQueryWeight > 1 {
+		return nil, fmt.Errorf("blockchain query weight must be between 0 and 1, got %v", config.BlockchainQueryWeight)
+	}
+	if config.HealthSmoothingAlpha < 0 || config.HealthSmoothingAlpha > 1 {
+		return nil, fmt.Errorf("health smoothing alpha must be between 0 and 1, got %v", config.HealthSmoothingAlpha)
+	}
+
+	// Every scorer referenced by the scoring pipeline must have been registered
+	for _, configured := range config.CustomScorers {
+		if _, ok := lookupScorer(configured.Name); !ok {
+			return nil, fmt.Errorf("scoring plugin %q is not registered", configured.Name)
+		}
+	}
+
+	if len(config.TierRules) == 0 {
+		config.TierRules = DefaultTierRules()
+	}
+
+	akashClient, err := akash.NewClient(&akash.Config{
+		GRPCEndpoints:   config.AkashGRPCEndpoints,
+		RPCEndpoint:     config.AkashRPCEndpoint,
+		GRPCSecurity:    config.AkashGRPCSecurity,
+		StatusRetry:     config.StatusRetry,
+		ChainRetry:      config.ChainRetry,
+		CircuitBreaker:  config.CircuitBreaker,
+		MaxConcurrent:   config.MaxConcurrent,
+		StatusTimeout:   config.StatusTimeout,
+		ChainTimeout:    config.ChainTimeout,
+		ProviderTimeout: config.ProviderTimeout,
+		BatchTimeout:    config.BatchTimeout,
+		ExtraEndpoints:  config.ExtraStatusEndpoints,
+		ResourceLimits:  config.ResourceLimits,
+		LeaseScoring:    config.LeaseScoring,
+		ListConcurrency: config.ListConcurrency,
+		StatusSchemas:   config.StatusSchemas,
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	service := &Service{
 		config:      config,
 		akashClient: akashClient,
+		blacklist:   NewBlacklist(config.Blacklist),
+		hostIndex:   NewHostIndex(),
+		gpuCache:    NewGPUCache(config.GPUCacheTTL),
+		updates: updateFeed{
+			subscribers: make(map[*Subscription]struct{}),
+			last:        make(map[string]providerState),
+		},
+
+		reasoningTemplates: reasoningTemplates,
 		cache: &ProviderCache{
 			data:       make(map[string]*CachedProvider),
 			lastUpdate: time.Time{},
 		},
 	}
 
-	// Start background cache cleanup
-	go service.cacheCleanupLoop()
+	if config.HistoryRetention > 0 {
+		service.history = NewHistoryStore(config.HistoryRetention, config.HistoryMaxSamples)
+	}
+	if config.HealthSmoothingAlpha > 0 {
+		service.smoother = NewHealthSmoother(config.HealthSmoothingAlpha)
+	}
+	if config.RecommendationCacheTTL > 0 {
+		service.recommendations = NewRecommendationCache(config.RecommendationCacheTTL)
+	}
+
+	// Pick up where the last run left off
+	if config.CacheFile != "" {
+		loaded, err := service.loadCache(config.CacheFile)
+		if err != nil {
+			slog.Warn("Starting with an empty provider cache", "path", config.CacheFile, "error", err)
+		} else if loaded > 0 {
+			slog.Info("Provider cache restored", "path", config.CacheFile, "providers", loaded)
+		}
+	}
+
+	// Background loops run until Close
+	service.lifecycle.ctx, service.lifecycle.cancel = context.WithCancel(context.Background())
+	service.goLoop("cache_cleanup", config.HealthCheckInterval, service.cacheCleanupLoop)
+
+	if service.history != nil && config.NetworkStatsInterval > 0 {
+		service.goLoop("network_stats", config.NetworkStatsInterval, service.networkStatsLoop)
+	}
+
+	hostIndexRefresh := config.HostIndexRefresh
+	if hostIndexRefresh <= 0 {
+		hostIndexRefresh = defaultHostIndexRefresh
+	}
+	service.goLoop("host_index", hostIndexRefresh, func(ctx context.Context) { service.hostIndexLoop(ctx, hostIndexRefresh) })
+
+	if config.LeaderboardInterval > 0 {
+		service.goLoop("leaderboard", config.LeaderboardInterval, func(ctx context.Context) { service.leaderboardLoop(ctx, config.LeaderboardInterval) })
+	}
+
+	if config.SubscriptionRefresh > 0 {
+		service.goLoop("subscription_refresh", config.SubscriptionRefresh, func(ctx context.Context) { service.subscriptionRefreshLoop(ctx, config.SubscriptionRefresh) })
+	}
 
 	return service, nil
 }
 
 // Get provider intelligence with caching and concurrent queries
-func (s *Service) GetProviderIntelligence(ctx context.Context, addresses []string) ([]*akash.ProviderInfo, error) {
+func (s *Service) GetProviderIntelligence(ctx context.Context, addresses []string) (*IntelligenceResult, error) {
+	return s.GetProviderIntelligenceWithOptions(ctx, addresses, FetchOptions{})
+}
+
+// Name the slowest fetched provider on the span, so a slow query can be
+// traced to the endpoint responsible without opening every provider span
+func recordSlowestProvider(span trace.Span, fetched []*akash.ProviderInfo) {
+	var slowest *akash.ProviderInfo
+	for _, info := range fetched {
+		if slowest == nil || info.BlockchainQueryTime+info.StatusQueryTime > slowest.BlockchainQueryTime+slowest.StatusQueryTime {
+			slowest = info
+		}
+	}
+	if slowest == nil {
+		return
+	}
+	span.SetAttributes(
+		attribute.String("provider.slowest.address", slowest.Address),
+		attribute.String("provider.slowest.host_uri", slowest.HostURI),
+		attribute.Int64("provider.slowest.query_ms", (slowest.BlockchainQueryTime+slowest.StatusQueryTime).Milliseconds()),
+	)
+}
+
+// Get provider intelligence, refetching cached data that is too old for the options given
+func (s *Service) GetProviderIntelligenceWithOptions(ctx context.Context, addresses []string, opts FetchOptions) (*IntelligenceResult, error) {
+	ctx, span := tracer.Start(ctx, "intelligence.GetProviderIntelligence",
+		trace.WithAttributes(attribute.Int("provider.count", len(addresses))))
+	defer span.End()
+
+	ctx, degraded := withDegradation(ctx)
+
+	result := &IntelligenceResult{
+		Providers:       []*akash.ProviderInfo{},
+		FailedProviders: []*akash.FailedProvider{},
+		RequestID:       telemetry.RequestID(ctx),
+	}
+	if opts.Page != nil {
+		addresses, result.Truncation = paginate(s, addresses, *opts.Page)
+	}
 	if len(addresses) == 0 {
-		return []*akash.ProviderInfo{}, nil
+		return result, nil
 	}
+	defer func() {
+		result.Degraded, result.UnavailableCapabilities = degraded.report()
+	}()
+
+	// Debug builds may force failures on some providers
+	addresses, injectedFailures := s.injectFetchFailures(ctx, addresses)
 
 	start := time.Now()
-	var results []*akash.ProviderInfo
 	var toFetch []string
+	var corrupt []string
 
 	// Check cache first
 	s.cache.mutex.RLock()
 	for _, addr := range addresses {
-		if cached, exists := s.cache.data[addr]; exists && time.Now().Before(cached.ExpiresAt) {
-			results = append(results, cached.Info)
+		cached, exists := s.cache.data[addr]
+		if !exists {
+			toFetch = append(toFetch, addr)
+			continue
+		}
+		if cached.Info == nil || cached.Info.Address != addr {
+			// Corrupt entries are evicted and refetched rather than returned
+			corrupt = append(corrupt, addr)
+			toFetch = append(toFetch, addr)
+			continue
+		}
+		tooOld := opts.MaxDataAge > 0 && time.Since(cached.Info.LastSeen) > opts.MaxDataAge
+		if time.Now().Before(cached.ExpiresAt) && !tooOld && !opts.Refresh {
+			span.AddEvent("cache hit", trace.WithAttributes(attribute.String("provider.address", addr)))
+			result.Providers = append(result.Providers, cached.Info)
 		} else {
 			toFetch = append(toFetch, addr)
 		}
 	}
 	s.cache.mutex.RUnlock()
 
-	// Fetch missing providers concurrently
+	if len(corrupt) > 0 {
+		s.cache.mutex.Lock()
+		for _, addr := range corrupt {
+			delete(s.cache.data, addr)
+		}
+		s.cache.mutex.Unlock()
+		slog.WarnContext(ctx, "Evicted corrupt cache entries", "count", len(corrupt))
+	}
+
+	// Fetch missing providers concurrently, likely winners first when ordering by prior
 	if len(toFetch) > 0 {
-		freshData, err := s.akashClient.GetMultipleProviderInfo(ctx, toFetch)
+		toFetch = s.orderFetchQueue(toFetch, opts)
+		freshData, failed, err := s.akashClient.GetMultipleProviderInfo(ctx, toFetch)
 		if err != nil {
-			return results, fmt.Errorf("failed to fetch provider data: %w", err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "provider fetch failed")
+			return result, fmt.Errorf("failed to fetch provider data: %w", err)
+		}
+
+		// Queries cut short by a cancelled caller would cache and record
+		// providers as unreachable
+		if err := ctx.Err(); err != nil {
+			return result, fmt.Errorf("provider fetch abandoned: %w", err)
+		}
+
+		for _, info := range freshData {
+			s.classifyProvider(info)
+			s.identifyCluster(info)
+			if s.smoother != nil {
+				smoothed := s.smoother.Observe(info)
+				info.SmoothedHealthScore = &smoothed
+			}
 		}
 
-		// Update cache
+		// Update cache - failed providers are not cached so they are retried on the next request
 		s.cache.mutex.Lock()
 		for _, info := range freshData {
 			s.cache.data[info.Address] = &CachedProvider{
 				Info:      info,
 				CachedAt:  time.Now(),
-				ExpiresAt: time.Now().Add(s.config.CacheTTL),
+				ExpiresAt: time.Now().Add(s.cacheTTL()),
 			}
 		}
 		s.cache.lastUpdate = time.Now()
 		s.cache.mutex.Unlock()
 
-		results = append(results, freshData...)
+		// Record fresh observations in the historical store
+		if s.history != nil {
+			for _, info := range freshData {
+				s.history.Record(info, info.LastSeen)
+			}
+		}
+		s.publishUpdates(freshData)
+
+		result.Providers = append(result.Providers, freshData...)
+		result.FailedProviders = append(result.FailedProviders, failed...)
+		recordSlowestProvider(span, freshData)
 	}
 
+	result.FailedProviders = append(result.FailedProviders, injectedFailures()...)
+	result.Providers = s.injectDataFaults(result.Providers)
+
+	span.SetAttributes(
+		attribute.Int("cache.hits", len(addresses)-len(toFetch)),
+		attribute.Int("cache.misses", len(toFetch)),
+		attribute.Int("provider.failed", len(result.FailedProviders)),
+	)
+
 	// Log performance
 	queryTime := time.Since(start)
-	fmt.Printf("🔍 Provider intelligence query completed: %d providers in %v (%d from cache, %d fresh)\n",
-		len(results), queryTime, len(addresses)-len(toFetch), len(toFetch))
+	slog.InfoContext(ctx, "Provider intelligence query completed",
+		"providers", len(result.Providers), "duration", queryTime, "cached", len(addresses)-len(toFetch),
+		"fresh", len(toFetch)-len(result.FailedProviders), "failed", len(result.FailedProviders))
 
-	return results, nil
+	if opts.MaxDataAge > 0 {
+		freshness, err := checkFreshness(result.Providers, result.FailedProviders, opts.MaxDataAge, time.Now())
+		result.Freshness = freshness
+		if err != nil {
+			span.SetStatus(codes.Error, "freshness guarantee not met")
+			return result, err
+		}
+	}
+
+	return result, nil
 }
 
 // Select optimal provider based on criteria with detailed scoring
 func (s *Service) SelectOptimalProvider(ctx context.Context, addresses []string, criteria SelectionCriteria) (*ProviderSelection, error) {
+	ctx, span := tracer.Start(ctx, "intelligence.SelectOptimalProvider",
+		trace.WithAttributes(
+			attribute.Int("provider.count", len(addresses)),
+			attribute.String("selection.priority", criteria.Priority),
+		))
+	defer span.End()
+
+	// Identical queries over unchanged provider data reuse the previous selection.
+	// A freshness requirement always goes to the provider cache instead.
+	var recommendationKeyValue string
+	cacheable := s.recommendations != nil && criteria.MaxDataAge == 0
+	if cacheable {
+		recommendationKeyValue, cacheable = recommendationKey(addresses, criteria)
+	}
+	if cacheable {
+		if selection, ok := s.cachedRecommendation(recommendationKeyValue, addresses); ok {
+			span.AddEvent("recommendation cache hit")
+			selection.RequestID = telemetry.RequestID(ctx)
+			return selection, nil
+		}
+	}
+
 	start := time.Now()
+	ctx, degraded := withDegradation(ctx)
+
+	intel, excluded, capped, err := s.fetchCandidates(ctx, addresses, criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	// A caller that gave up gets no selection built from whatever completed
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("selection abandoned: %w", err)
+	}
+
+	selection, err := s.selectFrom(ctx, intel.Providers, intel.FailedProviders, criteria)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(
+		attribute.String("selection.provider", selection.SelectedProvider),
+		attribute.Float64("selection.score", selection.Score),
+	)
+
+	selection.ExcludedProviders = excluded
+	selection.ScoringCap = capped
+	selection.QueryTime = time.Since(start)
+	selection.Degraded, selection.UnavailableCapabilities = degraded.report()
+	selection.Freshness = intel.Freshness
+	selection.RequestID = telemetry.RequestID(ctx)
+
+	if cacheable && !selection.Degraded {
+		s.storeRecommendation(recommendationKeyValue, addresses, selection)
+	}
+	return selection, nil
+}
+
+// Get intelligence for the candidates that are not blacklisted, returning the
+// excluded ones and, when the candidates exceed the scoring cap, what was cut
+func (s *Service) fetchCandidates(ctx context.Context, addresses []string, criteria SelectionCriteria) (*IntelligenceResult, []string, *ScoringCap, error) {
+	// Blacklisted and temporarily banned providers are never considered
+	var candidates, excluded []string
+	for _, addr := range addresses {
+		if s.blacklist.IsBanned(addr) {
+			excluded = append(excluded, addr)
+		} else {
+			candidates = append(candidates, addr)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil, nil, fmt.Errorf("all %d providers are blacklisted", len(addresses))
+	}
+
+	candidates, capped, err := s.capCandidates(candidates, criteria)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	intel, err := s.GetProviderIntelligenceWithOptions(ctx, candidates, FetchOptions{
+		MaxDataAge: criteria.MaxDataAge,
+		Order:      criteria.FetchOrder,
+		Hints:      criteria.FetchHints,
+	})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get provider intelligence: %w", err)
+	}
+	if len(intel.Providers) == 0 {
+		return nil, nil, nil, fmt.Errorf("no provider data available (%d providers failed)", len(intel.FailedProviders))
+	}
+
+	return intel, excluded, capped, nil
+}
 
-	// Get provider intelligence
-	providers, err := s.GetProviderIntelligence(ctx, addresses)
+// Apply hard requirements, score the remaining providers and build the selection
+func (s *Service) selectFrom(ctx context.Context, providers []*akash.ProviderInfo, failed []*akash.FailedProvider, criteria SelectionCriteria) (*ProviderSelection, error) {
+	criteria = s.applyGeoCoverage(providers, criteria)
+	scoredProviders, filtered, pruned, err := s.filterAndScore(ctx, providers, criteria)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get provider intelligence: %w", err)
+		return nil, err
+	}
+
+	best := scoredProviders[0]
+	reasoning := s.buildReasoningData(best, scoredProviders, criteria)
+	allProviders, allTruncation := capProviders(s, providers, selectionTruncationHint)
+
+	var tryOrder []TryOrderEntry
+	if criteria.TryOrder > 0 {
+		tryOrder = s.buildTryOrder(scoredProviders, criteria.TryOrder, criteria)
+	}
+
+	return &ProviderSelection{
+		SelectedProvider:       best.Provider.Address,
+		Score:                  best.Score,
+		Confidence:             calculateConfidence(scoredProviders, failed),
+		Reasoning:              s.renderReasoning(reasoning, criteria),
+		ReasoningData:          reasoning,
+		AllProviders:           allProviders,
+		AllProvidersTruncation: allTruncation,
+		FailedProviders:        failed,
+		FilteredProviders:      filtered,
+		PrunedProviders:        pruned,
+		TryOrder:               tryOrder,
+		Criteria:               criteria,
+		Stats:                  s.akashClient.GetProviderStats(providers),
+	}, nil
+}
+
+// Drop providers that fail hard requirements or cannot reach the score floor,
+// then score the rest with detailed breakdowns, highest first
+func (s *Service) filterAndScore(ctx context.Context, providers []*akash.ProviderInfo, criteria SelectionCriteria) ([]ScoredProvider, []FilteredProvider, []PrunedProvider, error) {
+	eligible, filtered := s.filterByAvailableNodes(providers, criteria.MinAvailableNodes)
+	if len(eligible) == 0 {
+		return nil, nil, nil, fmt.Errorf("no provider has at least %d available nodes (%d filtered out)", criteria.MinAvailableNodes, len(filtered))
+	}
+
+	eligible, filteredForBandwidth := filterByBandwidth(eligible, criteria.MinBandwidthMbps)
+	filtered = append(filtered, filteredForBandwidth...)
+	if len(eligible) == 0 {
+		return nil, nil, nil, fmt.Errorf("no provider advertises at least %s of bandwidth (%d filtered out)", formatBandwidth(criteria.MinBandwidthMbps), len(filtered))
+	}
+
+	eligible, filteredForArch := s.filterByArchitecture(eligible, criteria.CPUArch)
+	filtered = append(filtered, filteredForArch...)
+	if len(eligible) == 0 {
+		return nil, nil, nil, fmt.Errorf("no provider runs %s CPUs (%d filtered out)", criteria.CPUArch, len(filtered))
+	}
+
+	eligible, filteredForMaintenance := s.filterByMaintenance(eligible, criteria.now())
+	filtered = append(filtered, filteredForMaintenance...)
+	if len(eligible) == 0 {
+		return nil, nil, nil, fmt.Errorf("every remaining provider has maintenance scheduled (%d filtered out)", len(filtered))
+	}
+
+	// Skip providers that cannot reach the score floor under these weights
+	viable, pruned := s.pruneBelowFloor(eligible, criteria)
+	if len(viable) == 0 {
+		return nil, nil, nil, fmt.Errorf("no provider can reach the minimum score of %.2f (%d pruned)", s.config.ScoreFloor, len(pruned))
 	}
 
-	if len(providers) == 0 {
-		return nil, fmt.Errorf("no provider data available")
+	return s.scoreProviders(ctx, viable, criteria), filtered, pruned, nil
+}
+
+// Reference time for time-dependent scoring: the pinned time in deterministic mode, otherwise now
+func (c SelectionCriteria) now() time.Time {
+	if c.Deterministic && c.EvaluatedAt != nil {
+		return *c.EvaluatedAt
 	}
+	return time.Now()
+}
+
+// Score providers with detailed breakdowns, sorted by score (highest first)
+func (s *Service) scoreProviders(ctx context.Context, providers []*akash.ProviderInfo, criteria SelectionCriteria) []ScoredProvider {
+	criteria.pricingReferences = advertisedPriceReferences(providers)
 
-	// Score each provider with detailed breakdown
 	scoredProviders := make([]ScoredProvider, 0, len(providers))
 	for _, provider := range providers {
-		score, breakdown := s.scoreProviderWithBreakdown(provider, criteria)
+		score, breakdown := s.scoreProviderWithBreakdown(ctx, provider, criteria)
 		scoredProviders = append(scoredProviders, ScoredProvider{
 			Provider:  provider,
 			Score:     score,
@@ -169,33 +812,34 @@ func (s *Service) SelectOptimalProvider(ctx context.Context, addresses []string,
 		})
 	}
 
-	// Sort by score (highest first)
-	sort.Slice(scoredProviders, func(i, j int) bool {
-		return scoredProviders[i].Score > scoredProviders[j].Score
+	// Ties are broken by address so equal scores always rank the same way
+	sort.SliceStable(scoredProviders, func(i, j int) bool {
+		if scoredProviders[i].Score != scoredProviders[j].Score {
+			return scoredProviders[i].Score > scoredProviders[j].Score
+		}
+		return scoredProviders[i].Provider.Address < scoredProviders[j].Provider.Address
 	})
 
-	// Build selection result
-	best := scoredProviders[0]
-	reasoning := s.buildDetailedReasoning(best, scoredProviders, criteria)
-	stats := s.akashClient.GetProviderStats(providers)
+	if criteria.PreferConfident {
+		preferConfidentScores(scoredProviders)
+	}
 
-	return &ProviderSelection{
-		SelectedProvider: best.Provider.Address,
-		Score:            best.Score,
-		Reasoning:        reasoning,
-		AllProviders:     providers,
-		Criteria:         criteria,
-		Stats:            stats,
-		QueryTime:        time.Since(start),
-	}, nil
+	normalizeAcrossSet(scoredProviders)
+
+	return scoredProviders
 }
 
 // Score a provider with detailed breakdown
-func (s *Service) scoreProviderWithBreakdown(provider *akash.ProviderInfo, criteria SelectionCriteria) (float64, ScoreBreakdown) {
+func (s *Service) scoreProviderWithBreakdown(ctx context.Context, provider *akash.ProviderInfo, criteria SelectionCriteria) (float64, ScoreBreakdown) {
 	breakdown := ScoreBreakdown{}
 
-	// Health score component (base reliability)
-	breakdown.HealthScore = provider.HealthScore
+	// Denylisted attributes never reach scoring
+	provider, breakdown.IgnoredAttributes = s.scoringView(provider)
+	breakdown.RawMetrics = collectRawMetrics(provider)
+
+	// Health score component (base reliability), smoothed across observations when enabled
+	breakdown.HealthScore = selectionHealthScore(provider)
+	breakdown.InstantHealthScore = provider.HealthScore
 	score := breakdown.HealthScore * criteria.Weights.Reliability
 
 	// Performance score (response time and resources)
@@ -206,14 +850,62 @@ func (s *Service) scoreProviderWithBreakdown(provider *akash.ProviderInfo, crite
 	breakdown.GeographicScore = s.calculateGeographicScore(provider)
 	score += breakdown.GeographicScore * criteria.Weights.Geographic
 
-	// Price component (placeholder - would need bid data)
-	breakdown.PriceScore = s.calculatePriceScore(provider)
+	// Price component: advertised pricing when comparable, otherwise the heuristic
+	if priceScore, ok := advertisedPriceScore(provider, criteria); ok {
+		breakdown.PriceScore = priceScore
+		breakdown.PriceSource = PriceSourceAdvertised
+	} else {
+		breakdown.PriceScore = s.calculatePriceScore(provider)
+		breakdown.PriceSource = PriceSourceHeuristic
+	}
 	score += breakdown.PriceScore * criteria.Weights.Price
 
+	breakdown.MissingData = missingDataDimensions(provider, breakdown.PriceSource)
+
 	// Priority adjustments
 	breakdown.PriorityBonus = s.calculatePriorityBonus(provider, criteria.Priority)
 	score += breakdown.PriorityBonus
 
+	// Lease stability, weighted by how long the deployment is meant to run
+	if criteria.DeploymentDuration > 0 && criteria.Weights.Stability > 0 {
+		breakdown.StabilityScore = neutralStabilityScore
+		// History keeps changing, so pinned selections score stability neutral
+		if !criteria.Deterministic {
+			if stability := s.calculateLeaseStability(ctx, provider); stability != nil {
+				breakdown.LeaseStability = stability
+				breakdown.StabilityScore = stability.Score
+			}
+		}
+		score += breakdown.StabilityScore * criteria.Weights.Stability * stabilityDurationFactor(criteria.DeploymentDuration)
+	}
+
+	// Estimated time-to-provision, always reported but only scored when weighted
+	breakdown.Provisioning = s.estimateProvisioning(provider, criteria)
+	breakdown.ProvisioningScore = provisioningScore(breakdown.Provisioning)
+	score += breakdown.ProvisioningScore * criteria.Weights.Provisioning
+
+	// Network quality, always reported but only scored when weighted
+	breakdown.Network = assessNetwork(provider)
+	breakdown.NetworkScore = networkScore(provider, breakdown.Network)
+	score += breakdown.NetworkScore * criteria.Weights.Network
+
+	// Imminent maintenance
+	breakdown.MaintenancePenalty = s.maintenancePenalty(provider, criteria.now())
+	score -= breakdown.MaintenancePenalty
+
+	// Advertised resources the status inventory contradicts
+	breakdown.ResourceDivergence = detectResourceDivergence(provider)
+	breakdown.DivergencePenalty = s.divergencePenalty(breakdown.ResourceDivergence)
+	score -= breakdown.DivergencePenalty
+
+	// Custom scoring plugins, combined with their configured weights
+	customScores, customTotal := s.calculateCustomScores(ctx, provider)
+	breakdown.CustomScores = customScores
+	score += customTotal
+
+	// How much history backs the score
+	breakdown.Uncertainty = s.scoreUncertainty(provider, score, criteria)
+
 	return score, breakdown
 }
 
@@ -221,7 +913,7 @@ func (s *Service) scoreProviderWithBreakdown(provider *akash.ProviderInfo, crite
 func (s *Service) calculatePerformanceScore(provider *akash.ProviderInfo) float64 {
 	score := 0.0
 
-	// Response time scoring (50% of performance score)
+	// Response time scoring (50% of the provider-side performance score)
 	if provider.StatusQueryTime > 0 {
 		if provider.StatusQueryTime < 300*time.Millisecond {
 			score += 0.5
@@ -238,7 +930,7 @@ func (s *Service) calculatePerformanceScore(provider *akash.ProviderInfo) float6
 		}
 	}
 
-	// Resource availability scoring (30% of performance score)
+	// Resource availability scoring (30% of the provider-side performance score)
 	if provider.ClusterInfo != nil {
 		if provider.ClusterInfo.AvailableNodes > 0 {
 			score += 0.15
@@ -254,14 +946,23 @@ func (s *Service) calculatePerformanceScore(provider *akash.ProviderInfo) float6
 		}
 	}
 
-	// Blockchain query performance (20% of performance score)
+	// Blockchain query time mostly measures our own gRPC endpoint, so it only
+	// counts toward the provider's performance when configured (BlockchainQueryWeight)
+	blockchainScore := 0.0
 	if provider.BlockchainQueryTime > 0 && provider.BlockchainQueryTime < 2*time.Second {
-		score += 0.2
+		blockchainScore = 1
 	} else if provider.BlockchainQueryTime > 0 && provider.BlockchainQueryTime < 5*time.Second {
-		score += 0.1
+		blockchainScore = 0.5
 	}
 
-	return score
+	// Without status data, the time of a failed request says nothing about responsiveness
+	providerScore := score / 0.8
+	if !hasStatusData(provider) {
+		providerScore = s.missingDataScore()
+	}
+
+	weight := s.config.BlockchainQueryWeight
+	return providerScore*(1-weight) + blockchainScore*weight
 }
 
 // Calculate geographic score based on provider attributes
@@ -302,6 +1003,11 @@ func (s *Service) calculatePriceScore(provider *akash.ProviderInfo) float64 {
 
 	score := 0.5 // Default neutral score
 
+	// The heuristic is driven by lease counts, which are unknown without status data
+	if !hasStatusData(provider) {
+		score = s.missingDataScore()
+	}
+
 	// Providers with more active leases might be slightly more expensive but more reliable
 	if provider.ClusterInfo != nil {
 		leases := provider.ClusterInfo.ActiveLeases
@@ -343,9 +1049,9 @@ func (s *Service) calculatePriorityBonus(provider *akash.ProviderInfo, priority
 
 	switch priority {
 	case "reliability":
-		if provider.HealthScore > 0.8 {
+		if health := selectionHealthScore(provider); health > 0.8 {
 			bonus = 0.2
-		} else if provider.HealthScore > 0.6 {
+		} else if health > 0.6 {
 			bonus = 0.1
 		}
 	case "performance":
@@ -366,59 +1072,162 @@ func (s *Service) calculatePriorityBonus(provider *akash.ProviderInfo, priority
 	return bonus
 }
 
-// Build detailed human-readable reasoning for the selection
-func (s *Service) buildDetailedReasoning(best ScoredProvider, all []ScoredProvider, criteria SelectionCriteria) string {
-	reasoning := fmt.Sprintf("🎯 Selected provider %s with overall score %.3f\n\n",
-		best.Provider.Address, best.Score)
+// Build the structured reasoning for the selection
+func (s *Service) buildReasoningData(best ScoredProvider, all []ScoredProvider, criteria SelectionCriteria) *ReasoningData {
+	data := &ReasoningData{
+		Provider:   best.Provider.Address,
+		Score:      best.Score,
+		Rank:       1,
+		Candidates: len(all),
+	}
+	factor := func(name string, score float64, format string, args ...interface{}) {
+		data.Breakdown = append(data.Breakdown, ReasoningFactor{Name: name, Score: score, Value: fmt.Sprintf(format, args...)})
+	}
+	detail := func(format string, args ...interface{}) {
+		data.Details = append(data.Details, fmt.Sprintf(format, args...))
+	}
 
-	reasoning += "📊 Score Breakdown:\n"
-	reasoning += fmt.Sprintf("  • Health/Reliability: %.3f (weight: %.1f%%)\n",
-		best.Breakdown.HealthScore, criteria.Weights.Reliability*100)
-	reasoning += fmt.Sprintf("  • Performance: %.3f (weight: %.1f%%)\n",
+	if best.Provider.SmoothedHealthScore != nil {
+		factor("Health/Reliability", best.Breakdown.HealthScore, "%.3f smoothed, %.3f latest (weight: %.1f%%)",
+			best.Breakdown.HealthScore, best.Breakdown.InstantHealthScore, criteria.Weights.Reliability*100)
+	} else {
+		factor("Health/Reliability", best.Breakdown.HealthScore, "%.3f (weight: %.1f%%)",
+			best.Breakdown.HealthScore, criteria.Weights.Reliability*100)
+	}
+	factor("Performance", best.Breakdown.PerformanceScore, "%.3f (weight: %.1f%%)",
 		best.Breakdown.PerformanceScore, criteria.Weights.Performance*100)
-	reasoning += fmt.Sprintf("  • Geographic: %.3f (weight: %.1f%%)\n",
+	factor("Geographic", best.Breakdown.GeographicScore, "%.3f (weight: %.1f%%)",
 		best.Breakdown.GeographicScore, criteria.Weights.Geographic*100)
-	reasoning += fmt.Sprintf("  • Price: %.3f (weight: %.1f%%)\n",
-		best.Breakdown.PriceScore, criteria.Weights.Price*100)
+	factor("Price", best.Breakdown.PriceScore, "%.3f (weight: %.1f%%, %s)",
+		best.Breakdown.PriceScore, criteria.Weights.Price*100, best.Breakdown.PriceSource)
 
 	if best.Breakdown.PriorityBonus > 0 {
-		reasoning += fmt.Sprintf("  • Priority Bonus (%s): +%.3f\n",
-			criteria.Priority, best.Breakdown.PriorityBonus)
+		factor(fmt.Sprintf("Priority Bonus (%s)", criteria.Priority), best.Breakdown.PriorityBonus, "+%.3f",
+			best.Breakdown.PriorityBonus)
+	}
+
+	if criteria.DeploymentDuration > 0 && criteria.Weights.Stability > 0 {
+		factor("Lease Stability", best.Breakdown.StabilityScore, "%.3f (weight: %.1f%% × %.0f%% for a %v deployment)",
+			best.Breakdown.StabilityScore, criteria.Weights.Stability*100,
+			stabilityDurationFactor(criteria.DeploymentDuration)*100, criteria.DeploymentDuration)
+	}
+
+	if criteria.Weights.Provisioning > 0 {
+		factor("Provisioning Speed", best.Breakdown.ProvisioningScore, "%.3f (weight: %.1f%%)",
+			best.Breakdown.ProvisioningScore, criteria.Weights.Provisioning*100)
+	}
+
+	if criteria.Weights.Network > 0 {
+		factor("Network", best.Breakdown.NetworkScore, "%.3f (weight: %.1f%%)",
+			best.Breakdown.NetworkScore, criteria.Weights.Network*100)
 	}
 
-	reasoning += "\n🔍 Provider Details:\n"
+	for _, configured := range s.config.CustomScorers {
+		if score, ok := best.Breakdown.CustomScores[configured.Name]; ok {
+			factor(configured.Name+" (plugin)", score, "%.3f (weight: %.1f%%)",
+				score, configured.Weight*100)
+		}
+	}
 
 	// Health/reliability info
-	if best.Provider.ClusterInfo != nil {
-		reasoning += fmt.Sprintf("  • %d active leases (reliability indicator)\n",
-			best.Provider.ClusterInfo.ActiveLeases)
+	if best.Provider.Unproven {
+		if s.config.LeaseScoring.ZeroLeasePolicy == akash.ZeroLeaseNeutral {
+			detail("🆕 New/unproven: no active leases yet (scored neutrally, reliability not yet demonstrated)")
+		} else {
+			detail("🆕 New/unproven: no active leases yet (no reliability credit from leases)")
+		}
+	} else if best.Provider.ClusterInfo != nil {
+		detail("%d active leases (reliability indicator)", best.Provider.ClusterInfo.ActiveLeases)
+	}
+
+	// Dimension switched off for this candidate set
+	if criteria.GeoAdjustment != nil {
+		detail("%s", describeGeoAdjustment(criteria.GeoAdjustment))
+	}
+
+	// Score certainty
+	if uncertainty := best.Breakdown.Uncertainty; uncertainty != nil {
+		detail("%s", describeUncertainty(uncertainty, best.Score))
+	}
+
+	// Lease stability info
+	if stability := best.Breakdown.LeaseStability; stability != nil {
+		detail("Lease stability %.2f over %d observations (avg lease drop %.1f%%, reachable %.0f%% of the time)",
+			stability.Score, stability.Samples, stability.AverageLeaseDrop*100, stability.ReachableFraction*100)
+	} else if criteria.DeploymentDuration > 0 && criteria.Weights.Stability > 0 {
+		detail("No lease history yet - stability scored neutral")
+	}
+
+	// Provisioning estimate
+	if estimate := best.Breakdown.Provisioning; estimate != nil {
+		detail("Estimated time-to-provision ~%v (from %s, %d observations)",
+			estimate.Estimate, estimate.Basis, estimate.Samples)
+	} else if criteria.Weights.Provisioning > 0 {
+		detail("No responsiveness data - provisioning speed scored neutral")
+	}
+
+	// Network quality
+	if network := best.Breakdown.Network; network != nil && (network.Source == NetworkSourceAdvertised || criteria.Weights.Network > 0) {
+		detail("%s", describeNetwork(network))
+	}
+
+	// CPU architecture, when required or advertised
+	if criteria.CPUArch != "" || len(best.Provider.CPUArchitectures) > 0 {
+		detail("%s", s.describeArchitecture(best.Provider))
 	}
 
 	// Performance info
 	if best.Provider.StatusQueryTime > 0 {
-		reasoning += fmt.Sprintf("  • %v status endpoint response time\n",
-			best.Provider.StatusQueryTime)
+		detail("%v status endpoint response time", best.Provider.StatusQueryTime)
 	}
 
 	if best.Provider.BlockchainQueryTime > 0 {
-		reasoning += fmt.Sprintf("  • %v blockchain query time\n",
-			best.Provider.BlockchainQueryTime)
+		detail("%v blockchain query time", best.Provider.BlockchainQueryTime)
+	}
+
+	if criteria.MinAvailableNodes > 0 {
+		if best.Provider.ClusterInfo != nil {
+			detail("%d available nodes (minimum %d required)",
+				best.Provider.ClusterInfo.AvailableNodes, criteria.MinAvailableNodes)
+		} else {
+			detail("Available node count unknown (minimum %d required, unknown counts allowed)",
+				criteria.MinAvailableNodes)
+		}
+	} else if best.Provider.ClusterInfo != nil && best.Provider.ClusterInfo.AvailableNodes > 0 {
+		detail("%d available nodes", best.Provider.ClusterInfo.AvailableNodes)
 	}
 
-	if best.Provider.ClusterInfo != nil && best.Provider.ClusterInfo.AvailableNodes > 0 {
-		reasoning += fmt.Sprintf("  • %d available nodes\n",
-			best.Provider.ClusterInfo.AvailableNodes)
+	// Upcoming maintenance
+	if window := s.imminentMaintenance(best.Provider, criteria.now()); window != nil {
+		detail("⚠️  Maintenance scheduled %s to %s (score -%.3f)",
+			window.Start.Format(time.RFC3339), window.End.Format(time.RFC3339), best.Breakdown.MaintenancePenalty)
+	}
+
+	// Trust: advertised resources the provider's own inventory contradicts
+	for _, divergence := range best.Breakdown.ResourceDivergence {
+		if best.Breakdown.DivergencePenalty > 0 {
+			detail("⚠️  Resource mismatch: %s (score -%.3f)", describeDivergence(divergence), best.Breakdown.DivergencePenalty)
+		} else {
+			detail("⚠️  Resource mismatch: %s", describeDivergence(divergence))
+		}
+	}
+
+	// Advertised pricing
+	if best.Provider.Pricing != nil {
+		if _, ok := best.Provider.Pricing.ReferenceMonthlyCost(); ok {
+			detail("Advertised pricing: %s", describePricing(best.Provider.Pricing))
+		}
 	}
 
 	// Regional info
 	if region, ok := best.Provider.Attributes["region"]; ok {
-		reasoning += fmt.Sprintf("  • Located in %s region\n", region)
+		detail("Located in %s region", region)
 	}
 
 	// GPU capabilities
 	for key := range best.Provider.Attributes {
 		if key == "capabilities/gpu/vendor/nvidia" {
-			reasoning += "  • NVIDIA GPU capabilities available\n"
+			detail("NVIDIA GPU capabilities available")
 			break
 		}
 	}
@@ -427,40 +1236,34 @@ func (s *Service) buildDetailedReasoning(best ScoredProvider, all []ScoredProvid
 	if best.Provider.ClusterInfo != nil {
 		available := best.Provider.ClusterInfo.AvailableResources
 		if available.CPU > 0 || available.Memory > 0 || available.GPU > 0 {
-			reasoning += "  • Available resources: "
 			parts := []string{}
 			if available.CPU > 0 {
 				parts = append(parts, fmt.Sprintf("CPU: %d", available.CPU))
 			}
 			if available.Memory > 0 {
-				parts = append(parts, fmt.Sprintf("Memory: %.1fGB", float64(available.Memory)/(1024*1024*1024)))
+				parts = append(parts, fmt.Sprintf("Memory: %s", s.formatBytes(available.Memory, criteria)))
 			}
 			if available.GPU > 0 {
 				parts = append(parts, fmt.Sprintf("GPU: %d", available.GPU))
 			}
-			reasoning += fmt.Sprintf("%s\n", strings.Join(parts, ", "))
+			detail("Available resources: %s", strings.Join(parts, ", "))
 		}
 	}
 
 	// Comparison with alternatives
 	if len(all) > 1 {
-		reasoning += fmt.Sprintf("\n📈 Competitive Analysis:\n")
-		reasoning += fmt.Sprintf("  • Ranked #1 out of %d providers\n", len(all))
+		data.Competitive = append(data.Competitive, fmt.Sprintf("Ranked #1 out of %d providers", len(all)))
 
-		if len(all) > 1 {
-			secondBest := all[1]
-			scoreDiff := best.Score - secondBest.Score
-			reasoning += fmt.Sprintf("  • Score advantage over #2: +%.3f (%.1f%% better)\n",
-				scoreDiff, (scoreDiff/secondBest.Score)*100)
-		}
+		secondBest := all[1]
+		scoreDiff := best.Score - secondBest.Score
+		data.Competitive = append(data.Competitive, fmt.Sprintf("Score advantage over #2: +%.3f (%.1f%% better)",
+			scoreDiff, (scoreDiff/secondBest.Score)*100))
 	}
 
 	// Error information if any
-	if best.Provider.Error != "" {
-		reasoning += fmt.Sprintf("\n⚠️  Note: %s\n", best.Provider.Error)
-	}
+	data.Note = best.Provider.Error
 
-	return reasoning
+	return data
 }
 
 // Get cache statistics
@@ -470,7 +1273,7 @@ func (s *Service) GetCacheStats() map[string]interface{} {
 
 	stats := map[string]interface{}{
 		"entries":     len(s.cache.data),
-		"ttl":         s.config.CacheTTL.String(),
+		"ttl":         s.cacheTTL().String(),
 		"last_update": s.cache.lastUpdate,
 	}
 
@@ -491,13 +1294,74 @@ func (s *Service) GetCacheStats() map[string]interface{} {
 	return stats
 }
 
+// Temporarily ban a provider from selection
+func (s *Service) BanProvider(address string, ttl time.Duration, reason string) (*BanEntry, error) {
+	entry, err := s.blacklist.Ban(address, ttl, reason)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("Provider banned", "provider", address, "until", entry.ExpiresAt.Format(time.RFC3339), "reason", reason)
+	return entry, nil
+}
+
+// Lift a temporary ban
+func (s *Service) UnbanProvider(address string) error {
+	return s.blacklist.Unban(address)
+}
+
+// Get permanent blacklist entries and active temporary bans
+func (s *Service) GetBlacklistStatus() map[string]interface{} {
+	permanent, temporary := s.blacklist.Active()
+	return map[string]interface{}{
+		"permanent": permanent,
+		"temporary": temporary,
+	}
+}
+
+// Get the provider hosts whose status queries are skipped after repeated failures
+func (s *Service) OpenCircuits() []akash.OpenCircuit {
+	return s.akashClient.OpenCircuits()
+}
+
+// Get current upstream saturation, see akash.Client.Saturation
+func (s *Service) Saturation() float64 {
+	return s.akashClient.Saturation()
+}
+
+// Get historical store statistics
+func (s *Service) GetHistoryStats() map[string]interface{} {
+	if s.history == nil {
+		return map[string]interface{}{"enabled": false}
+	}
+
+	stats := s.history.Stats()
+	stats["enabled"] = true
+	return stats
+}
+
 // Background cache cleanup loop
-func (s *Service) cacheCleanupLoop() {
+func (s *Service) cacheCleanupLoop(ctx context.Context) {
 	ticker := time.NewTicker(s.config.HealthCheckInterval)
 	defer ticker.Stop()
 
-	for range ticker.C {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
 		s.cleanupExpiredCache()
+		if s.history != nil {
+			s.history.Prune(time.Now())
+		}
+		if s.recommendations != nil {
+			s.recommendations.PruneExpired()
+		}
+		s.gpuCache.PruneExpired(time.Now())
+		if expired := s.blacklist.PruneExpired(); expired > 0 {
+			slog.Info("Temporary bans expired", "count", expired)
+		}
+		s.loopRan("cache_cleanup")
 	}
 }
 
@@ -517,7 +1381,6 @@ func (s *Service) cleanupExpiredCache() {
 
 	cleanedCount := initialCount - len(s.cache.data)
 	if cleanedCount > 0 {
-		fmt.Printf("🧹 Cache cleanup: removed %d expired entries, %d remaining\n",
-			cleanedCount, len(s.cache.data))
+		slog.Debug("Cache cleanup", "removed", cleanedCount, "remaining", len(s.cache.data))
 	}
 }
@@ -0,0 +1,128 @@
+package intelligence
+
+// A raw measurement compared across the candidate set
+type setMetric struct {
+	name          string
+	lowerIsBetter bool
+	value         func(ScoredProvider) (float64, bool)
+}
+
+var setMetrics = []setMetric{
+	{name: "health_score", value: func(p ScoredProvider) (float64, bool) {
+		return p.Breakdown.RawMetrics.HealthScore, true
+	}},
+	{name: "status_latency_ms", lowerIsBetter: true, value: func(p ScoredProvider) (float64, bool) {
+		return derefFloat(p.Breakdown.RawMetrics.StatusLatencyMs)
+	}},
+	{name: "available_nodes", value: func(p ScoredProvider) (float64, bool) {
+		return derefInt(p.Breakdown.RawMetrics.AvailableNodes)
+	}},
+	{name: "available_cpu", value: func(p ScoredProvider) (float64, bool) {
+		if cpu := p.Breakdown.RawMetrics.AvailableCPU; cpu != nil {
+			return float64(*cpu), true
+		}
+		return 0, false
+	}},
+	{name: "available_memory", value: func(p ScoredProvider) (float64, bool) {
+		if memory := p.Breakdown.RawMetrics.AvailableMemory; memory != nil {
+			return float64(*memory), true
+		}
+		return 0, false
+	}},
+	{name: "available_gpu", value: func(p ScoredProvider) (float64, bool) {
+		return derefInt(p.Breakdown.RawMetrics.AvailableGPU)
+	}},
+	{name: "bandwidth_mbps", value: func(p ScoredProvider) (float64, bool) {
+		return derefFloat(p.Breakdown.RawMetrics.BandwidthMbps)
+	}},
+	{name: "geographic_score", value: func(p ScoredProvider) (float64, bool) {
+		return p.Breakdown.GeographicScore, true
+	}},
+}
+
+// Min-max normalize each raw measurement across the scored set, best 1 and
+// worst 0 within this set. Purely a presentation aid: it never feeds the score.
+// Providers missing a measurement get no value for it; when every provider
+// measures the same, all get 1.
+func normalizeAcrossSet(scored []ScoredProvider) {
+	for i := range scored {
+		if scored[i].Breakdown.RawMetrics != nil {
+			scored[i].Breakdown.Normalized = make(map[string]float64)
+		}
+	}
+
+	for _, metric := range setMetrics {
+		normalizeGroup(scored, metric.name, metric.lowerIsBetter, func(p ScoredProvider) (float64, bool) {
+			if p.Breakdown.RawMetrics == nil {
+				return 0, false
+			}
+			return metric.value(p)
+		})
+	}
+
+	// Advertised prices only compare within a denom
+	denoms := make(map[string]bool)
+	for _, p := range scored {
+		if raw := p.Breakdown.RawMetrics; raw != nil && raw.ReferenceMonthlyCost != nil {
+			denoms[raw.PriceDenom] = true
+		}
+	}
+	for denom := range denoms {
+		normalizeGroup(scored, "reference_monthly_cost", true, func(p ScoredProvider) (float64, bool) {
+			if raw := p.Breakdown.RawMetrics; raw != nil && raw.PriceDenom == denom {
+				return derefFloat(raw.ReferenceMonthlyCost)
+			}
+			return 0, false
+		})
+	}
+}
+
+func normalizeGroup(scored []ScoredProvider, name string, lowerIsBetter bool, value func(ScoredProvider) (float64, bool)) {
+	first := true
+	var lo, hi float64
+	for _, p := range scored {
+		v, ok := value(p)
+		if !ok {
+			continue
+		}
+		if first || v < lo {
+			lo = v
+		}
+		if first || v > hi {
+			hi = v
+		}
+		first = false
+	}
+	if first {
+		return
+	}
+
+	for i := range scored {
+		v, ok := value(scored[i])
+		if !ok {
+			continue
+		}
+		normalized := 1.0
+		if hi > lo {
+			normalized = (v - lo) / (hi - lo)
+			if lowerIsBetter {
+				normalized = 1 - normalized
+			}
+		}
+		scored[i].Breakdown.Normalized[name] = normalized
+	}
+}
+
+func derefFloat(v *float64) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+	return *v, true
+}
+
+func derefInt(v *int) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+	return float64(*v), true
+}
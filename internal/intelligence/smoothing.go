@@ -0,0 +1,45 @@
+package intelligence
+
+import (
+	"sync"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Exponential moving average of each provider's health score across observations
+type HealthSmoother struct {
+	alpha  float64
+	scores map[string]float64
+	mutex  sync.Mutex
+}
+
+// Alpha is the weight of the newest observation: 1 disables smoothing, smaller values smooth more
+func NewHealthSmoother(alpha float64) *HealthSmoother {
+	return &HealthSmoother{
+		alpha:  alpha,
+		scores: make(map[string]float64),
+	}
+}
+
+// Fold a fresh observation into the provider's average, seeding it with the first one
+func (h *HealthSmoother) Observe(info *akash.ProviderInfo) float64 {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	smoothed, seen := h.scores[info.Address]
+	if !seen {
+		smoothed = info.HealthScore
+	} else {
+		smoothed = h.alpha*info.HealthScore + (1-h.alpha)*smoothed
+	}
+	h.scores[info.Address] = smoothed
+	return smoothed
+}
+
+// Health score used for selection: the smoothed value when smoothing is enabled
+func selectionHealthScore(provider *akash.ProviderInfo) float64 {
+	if provider.SmoothedHealthScore != nil {
+		return *provider.SmoothedHealthScore
+	}
+	return provider.HealthScore
+}
@@ -0,0 +1,98 @@
+package intelligence
+
+import (
+	"context"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+const (
+	// How far back lease history is considered when judging stability
+	stabilityLookback = 7 * 24 * time.Hour
+
+	// Minimum observations before stability is scored at all
+	minStabilitySamples = 3
+
+	// Deployments of this length or longer get the full stability weight
+	longDeploymentDuration = 30 * 24 * time.Hour
+)
+
+// Neutral stability score used when no lease history exists
+const neutralStabilityScore = 0.5
+
+// Observed lease stability of a provider, from the historical store
+type LeaseStability struct {
+	Score             float64 `json:"score"`
+	Samples           int     `json:"samples"`
+	AverageLeaseDrop  float64 `json:"average_lease_drop"`
+	ReachableFraction float64 `json:"reachable_fraction"`
+}
+
+// Calculate lease stability from the provider's history up to its observation time.
+// Returns nil when there is not enough history to judge.
+func (s *Service) calculateLeaseStability(ctx context.Context, provider *akash.ProviderInfo) *LeaseStability {
+	if s.history == nil {
+		reportDegraded(ctx, "historical_store", "historical store disabled; lease stability scored neutral")
+		return nil
+	}
+
+	until := provider.LastSeen
+	if until.IsZero() {
+		until = time.Now()
+	}
+
+	samples := s.history.Samples(provider.Address, until.Add(-stabilityLookback), until)
+	if len(samples) < minStabilitySamples {
+		return nil
+	}
+
+	// Relative lease drops between consecutive observations indicate churn
+	var totalDrop float64
+	var drops int
+	reachable := 0
+	previousLeases := -1
+	for _, sample := range samples {
+		if sample.Info.ClusterInfo == nil {
+			continue
+		}
+		reachable++
+
+		leases := sample.Info.ClusterInfo.ActiveLeases
+		if previousLeases >= 0 {
+			if previousLeases > leases {
+				totalDrop += float64(previousLeases-leases) / float64(previousLeases)
+			}
+			drops++
+		}
+		previousLeases = leases
+	}
+
+	stability := &LeaseStability{
+		Samples:           len(samples),
+		ReachableFraction: float64(reachable) / float64(len(samples)),
+	}
+	if drops > 0 {
+		stability.AverageLeaseDrop = totalDrop / float64(drops)
+	}
+
+	// Losing a quarter of leases per observation on average counts as fully unstable
+	churnScore := 1 - stability.AverageLeaseDrop*4
+	if churnScore < 0 {
+		churnScore = 0
+	}
+	stability.Score = 0.5*churnScore + 0.5*stability.ReachableFraction
+
+	return stability
+}
+
+// Fraction of the stability weight that applies to a deployment of the given duration
+func stabilityDurationFactor(duration time.Duration) float64 {
+	if duration <= 0 {
+		return 0
+	}
+	if duration >= longDeploymentDuration {
+		return 1
+	}
+	return float64(duration) / float64(longDeploymentDuration)
+}
@@ -0,0 +1,119 @@
+package intelligence
+
+import (
+	"context"
+	"sort"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+// Rule assigning a tier label to providers that satisfy all of its conditions.
+// Zero-valued conditions are ignored.
+type TierRule struct {
+	Name               string            `json:"name"`
+	RequireGPU         bool              `json:"require_gpu,omitempty"`
+	MinAvailableCPU    int64             `json:"min_available_cpu,omitempty"`    // millicores
+	MinAvailableMemory int64             `json:"min_available_memory,omitempty"` // bytes
+	MaxActiveLeases    *int              `json:"max_active_leases,omitempty"`
+	Attributes         map[string]string `json:"attributes,omitempty"`
+}
+
+// Label for providers that match no tier rule
+const untieredLabel = "untiered"
+
+// Tier rules used when none are configured
+func DefaultTierRules() []TierRule {
+	budgetLeases := 20
+	return []TierRule{
+		{Name: "gpu", RequireGPU: true},
+		{Name: "high-capacity", MinAvailableCPU: 32000, MinAvailableMemory: 64 * 1024 * 1024 * 1024},
+		// Fewer active leases tends to mean lower prices, mirroring the cost priority heuristic
+		{Name: "budget", MaxActiveLeases: &budgetLeases},
+		{Name: "enterprise", Attributes: map[string]string{"tier": "enterprise"}},
+	}
+}
+
+// Providers grouped by tier label; a provider appears under every tier it belongs to
+type TieredProviders struct {
+	Tiers           map[string][]*akash.ProviderInfo `json:"tiers"`
+	FailedProviders []*akash.FailedProvider          `json:"failed_providers"`
+}
+
+// Check whether a provider has GPUs, either advertised or reported in its inventory
+func hasGPU(provider *akash.ProviderInfo) bool {
+	if _, ok := provider.Attributes["capabilities/gpu/vendor/nvidia"]; ok {
+		return true
+	}
+	return provider.ClusterInfo != nil && provider.ClusterInfo.TotalResources.GPU > 0
+}
+
+// Check whether a provider satisfies a tier rule
+func (rule TierRule) matches(provider *akash.ProviderInfo) bool {
+	if rule.RequireGPU && !hasGPU(provider) {
+		return false
+	}
+
+	if rule.MinAvailableCPU > 0 || rule.MinAvailableMemory > 0 || rule.MaxActiveLeases != nil {
+		if provider.ClusterInfo == nil {
+			return false
+		}
+		available := provider.ClusterInfo.AvailableResources
+		if available.CPU < rule.MinAvailableCPU || available.Memory < rule.MinAvailableMemory {
+			return false
+		}
+		if rule.MaxActiveLeases != nil && provider.ClusterInfo.ActiveLeases > *rule.MaxActiveLeases {
+			return false
+		}
+	}
+
+	for key, value := range rule.Attributes {
+		if provider.Attributes[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Assign tier labels to a freshly fetched provider
+func (s *Service) classifyProvider(provider *akash.ProviderInfo) {
+	var tiers []string
+	for _, rule := range s.config.TierRules {
+		if rule.matches(provider) {
+			tiers = append(tiers, rule.Name)
+		}
+	}
+	provider.Tiers = tiers
+}
+
+// Get provider intelligence grouped by capability tier
+func (s *Service) GetProvidersByTier(ctx context.Context, addresses []string) (*TieredProviders, error) {
+	intel, err := s.GetProviderIntelligence(ctx, addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	grouped := &TieredProviders{
+		Tiers:           make(map[string][]*akash.ProviderInfo),
+		FailedProviders: intel.FailedProviders,
+	}
+
+	for _, provider := range intel.Providers {
+		if len(provider.Tiers) == 0 {
+			grouped.Tiers[untieredLabel] = append(grouped.Tiers[untieredLabel], provider)
+			continue
+		}
+		for _, tier := range provider.Tiers {
+			grouped.Tiers[tier] = append(grouped.Tiers[tier], provider)
+		}
+	}
+
+	// Healthiest providers first within each tier
+	for _, providers := range grouped.Tiers {
+		sort.SliceStable(providers, func(i, j int) bool {
+			return providers[i].HealthScore > providers[j].HealthScore
+		})
+	}
+
+	return grouped, nil
+}
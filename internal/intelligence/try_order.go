@@ -0,0 +1,138 @@
+package intelligence
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+const (
+	// Most providers a try order can cover
+	maxTryOrder = 20
+
+	// Observations considered when estimating how often a provider answers
+	reachabilityLookback = 24 * time.Hour
+
+	// Share of free CPU at which capacity no longer limits the chance of success
+	ampleCapacityFraction = 0.25
+
+	// Capacity factor of a provider reporting no free node
+	noFreeNodeCapacityFactor = 0.2
+
+	// Capacity factor of a provider whose capacity is unknown
+	unknownCapacityFactor = 0.5
+)
+
+// A position in the order clients should try providers in
+type TryOrderEntry struct {
+	Position           int           `json:"position"`
+	Address            string        `json:"address"`
+	Score              float64       `json:"score"`
+	ScoreRank          int           `json:"score_rank"`
+	SuccessProbability float64       `json:"success_probability"`
+	Reachability       float64       `json:"reachability"`
+	CapacityFactor     float64       `json:"capacity_factor"`
+	ExpectedAttempt    time.Duration `json:"expected_attempt"` // time an attempt takes, see ProvisioningEstimate
+	Rationale          string        `json:"rationale"`
+}
+
+// Order the top n scored providers so that a client trying them one at a time
+// reaches a running deployment soonest. Each attempt succeeds with probability
+// p and costs time t; trying in descending p/t minimizes the expected total
+// time. The score ranking only decides which providers are candidates.
+func (s *Service) buildTryOrder(scored []ScoredProvider, n int, criteria SelectionCriteria) []TryOrderEntry {
+	n = min(n, maxTryOrder, len(scored))
+
+	entries := make([]TryOrderEntry, 0, n)
+	for i, candidate := range scored[:n] {
+		reachability := s.observedReachability(candidate.Provider, criteria)
+		capacity := capacityFactor(candidate.Provider)
+
+		attempt := baseProvisionTime
+		if estimate := candidate.Breakdown.Provisioning; estimate != nil {
+			attempt = estimate.Estimate
+		}
+
+		entries = append(entries, TryOrderEntry{
+			Address:            candidate.Provider.Address,
+			Score:              candidate.Score,
+			ScoreRank:          i + 1,
+			SuccessProbability: reachability * capacity,
+			Reachability:       reachability,
+			CapacityFactor:     capacity,
+			ExpectedAttempt:    attempt,
+		})
+	}
+
+	rate := func(e TryOrderEntry) float64 {
+		return e.SuccessProbability / e.ExpectedAttempt.Seconds()
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return rate(entries[i]) > rate(entries[j])
+	})
+
+	for i := range entries {
+		entries[i].Position = i + 1
+		entries[i].Rationale = describeTryPosition(entries[i])
+	}
+	return entries
+}
+
+// Share of recent observations in which the provider answered its status
+// endpoint, Laplace-smoothed so a single observation is not taken as certainty.
+// Stands in for bid acceptance, which is not tracked.
+func (s *Service) observedReachability(provider *akash.ProviderInfo, criteria SelectionCriteria) float64 {
+	reachable, observations := 0, 1
+	if provider.ClusterInfo != nil {
+		reachable++
+	}
+
+	// Pinned selections only use the supplied data
+	if s.history != nil && !criteria.Deterministic {
+		until := provider.LastSeen
+		if until.IsZero() {
+			until = time.Now()
+		}
+		for _, sample := range s.history.Samples(provider.Address, until.Add(-reachabilityLookback), until) {
+			if sample.Info.LastSeen.Equal(provider.LastSeen) {
+				continue
+			}
+			observations++
+			if sample.Info.ClusterInfo != nil {
+				reachable++
+			}
+		}
+	}
+
+	return float64(reachable+1) / float64(observations+2)
+}
+
+// Chance that free capacity lets the provider take the lease
+func capacityFactor(provider *akash.ProviderInfo) float64 {
+	cluster := provider.ClusterInfo
+	if cluster == nil || cluster.TotalResources.CPU <= 0 {
+		return unknownCapacityFactor
+	}
+	if cluster.AvailableNodes == 0 {
+		return noFreeNodeCapacityFactor
+	}
+
+	free := float64(cluster.AvailableResources.CPU) / float64(cluster.TotalResources.CPU)
+	return noFreeNodeCapacityFactor + (1-noFreeNodeCapacityFactor)*min(free/ampleCapacityFraction, 1)
+}
+
+// Explain why a provider holds its place in the try order
+func describeTryPosition(entry TryOrderEntry) string {
+	rationale := fmt.Sprintf("%.0f%% estimated success (%.0f%% reachable, capacity factor %.2f), ~%v per attempt",
+		entry.SuccessProbability*100, entry.Reachability*100, entry.CapacityFactor, entry.ExpectedAttempt)
+
+	switch {
+	case entry.Position < entry.ScoreRank:
+		rationale += fmt.Sprintf("; moved up from score rank %d", entry.ScoreRank)
+	case entry.Position > entry.ScoreRank:
+		rationale += fmt.Sprintf("; moved down from score rank %d", entry.ScoreRank)
+	}
+	return rationale
+}
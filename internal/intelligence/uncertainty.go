@@ -0,0 +1,148 @@
+package intelligence
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/chainzero/akash-provider-intelligence/internal/akash"
+)
+
+const (
+	// History considered when judging how well a score is backed
+	uncertaintyLookback = 7 * 24 * time.Hour
+
+	// Observations and observed span at which history counts as sufficient
+	sufficientSamples = 30
+	sufficientSpan    = 24 * time.Hour
+
+	// Assumed spread of a sub-score before there is enough history to measure it
+	priorScoreStdDev = 0.2
+
+	// z value of a 95% interval
+	intervalZ = 1.96
+)
+
+// Data sufficiency levels
+const (
+	SufficiencyHigh         = "sufficient"
+	SufficiencyLimited      = "limited"
+	SufficiencyInsufficient = "insufficient"
+)
+
+// How much history backs a provider's score, and the resulting 95% interval
+// around it. Only the reliability and performance dimensions vary between
+// observations, so only they widen the interval.
+type ScoreUncertainty struct {
+	Samples     int           `json:"samples"`
+	Span        time.Duration `json:"span"`
+	Sufficiency float64       `json:"sufficiency"` // 0..1
+	Level       string        `json:"level"`
+	Lower       float64       `json:"lower"`
+	Upper       float64       `json:"upper"`
+}
+
+// Estimate the uncertainty of a provider's total score from its observation history
+func (s *Service) scoreUncertainty(provider *akash.ProviderInfo, score float64, criteria SelectionCriteria) *ScoreUncertainty {
+	// Pinned selections don't consult history
+	if s.history == nil || criteria.Deterministic {
+		return nil
+	}
+
+	until := provider.LastSeen
+	if until.IsZero() {
+		until = time.Now()
+	}
+	samples := s.history.Samples(provider.Address, until.Add(-uncertaintyLookback), until)
+
+	uncertainty := &ScoreUncertainty{Samples: len(samples)}
+	if len(samples) > 1 {
+		uncertainty.Span = samples[len(samples)-1].ObservedAt.Sub(samples[0].ObservedAt)
+	}
+	uncertainty.Sufficiency = math.Min(float64(len(samples))/sufficientSamples, 1) *
+		math.Min(float64(uncertainty.Span)/float64(sufficientSpan), 1)
+
+	switch {
+	case uncertainty.Sufficiency >= 0.8:
+		uncertainty.Level = SufficiencyHigh
+	case uncertainty.Sufficiency >= 0.2:
+		uncertainty.Level = SufficiencyLimited
+	default:
+		uncertainty.Level = SufficiencyInsufficient
+	}
+
+	// Standard error of each varying sub-score, falling back to the prior spread
+	health := make([]float64, 0, len(samples))
+	performance := make([]float64, 0, len(samples))
+	for _, sample := range samples {
+		health = append(health, sample.Info.HealthScore)
+		performance = append(performance, s.calculatePerformanceScore(sample.Info))
+	}
+	healthError := standardError(health)
+	performanceError := standardError(performance)
+
+	halfWidth := intervalZ * math.Hypot(healthError*criteria.Weights.Reliability, performanceError*criteria.Weights.Performance)
+	uncertainty.Lower = score - halfWidth
+	uncertainty.Upper = score + halfWidth
+	return uncertainty
+}
+
+// Standard error of the mean; the prior spread stands in for the deviation of short series
+func standardError(values []float64) float64 {
+	n := len(values)
+	if n < 2 {
+		return priorScoreStdDev
+	}
+
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(n)
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	stdDev := math.Sqrt(variance / float64(n-1))
+
+	// A handful of identical observations doesn't prove a provider never varies
+	if n < sufficientSamples {
+		weight := float64(n) / sufficientSamples
+		stdDev = weight*stdDev + (1-weight)*priorScoreStdDev
+	}
+	return stdDev / math.Sqrt(float64(n))
+}
+
+// Among providers nearly tied with the leader, rank the one whose score is
+// best supported (highest lower bound) first
+func preferConfidentScores(scored []ScoredProvider) {
+	if len(scored) < 2 {
+		return
+	}
+
+	tied := 1
+	for tied < len(scored) && scored[0].Score-scored[tied].Score <= nearTieMargin {
+		tied++
+	}
+	if tied == 1 {
+		return
+	}
+
+	lowerBound := func(p ScoredProvider) float64 {
+		if p.Breakdown.Uncertainty == nil {
+			return math.Inf(-1)
+		}
+		return p.Breakdown.Uncertainty.Lower
+	}
+	sort.SliceStable(scored[:tied], func(i, j int) bool {
+		return lowerBound(scored[i]) > lowerBound(scored[j])
+	})
+}
+
+// Describe score uncertainty for reasoning
+func describeUncertainty(u *ScoreUncertainty, score float64) string {
+	return fmt.Sprintf("Score %.3f ± %.3f (95%% interval, %d observations over %v - %s history)",
+		score, (u.Upper-u.Lower)/2, u.Samples, u.Span.Round(time.Minute), u.Level)
+}
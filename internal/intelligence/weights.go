@@ -0,0 +1,97 @@
+package intelligence
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Sources a scoring weight can be resolved from, lowest precedence first
+const (
+	WeightSourceDefault  = "default"
+	WeightSourceConfig   = "config"
+	WeightSourceOverride = "request-override"
+)
+
+// A resolved scoring weight and where its value came from
+type WeightProvenance struct {
+	Value  float64 `json:"value"`
+	Source string  `json:"source"`
+}
+
+// Built-in weights used for dimensions that are not configured
+func DefaultWeights() Weights {
+	return Weights{
+		Price:       0.4,
+		Reliability: 0.3,
+		Performance: 0.2,
+		Geographic:  0.1,
+		Stability:   0.15,
+	}
+}
+
+// Names of the weighted dimensions, as used in config and request overrides
+var weightNames = []string{"price", "reliability", "performance", "geographic", "stability", "provisioning", "network"}
+
+// Get a pointer to the named weight
+func (w *Weights) field(name string) *float64 {
+	switch name {
+	case "price":
+		return &w.Price
+	case "reliability":
+		return &w.Reliability
+	case "performance":
+		return &w.Performance
+	case "geographic":
+		return &w.Geographic
+	case "stability":
+		return &w.Stability
+	case "provisioning":
+		return &w.Provisioning
+	case "network":
+		return &w.Network
+	}
+	return nil
+}
+
+// Resolve scoring weights by layering configured weights over the defaults and
+// request overrides over both, recording the source of every weight
+func ResolveWeights(configured, overrides map[string]float64) (Weights, map[string]WeightProvenance, error) {
+	weights := DefaultWeights()
+	provenance := make(map[string]WeightProvenance, len(weightNames))
+	for _, name := range weightNames {
+		provenance[name] = WeightProvenance{Value: *weights.field(name), Source: WeightSourceDefault}
+	}
+
+	layers := []struct {
+		source string
+		values map[string]float64
+	}{
+		{WeightSourceConfig, configured},
+		{WeightSourceOverride, overrides},
+	}
+
+	for _, layer := range layers {
+		// Apply in name order so errors are deterministic
+		names := make([]string, 0, len(layer.values))
+		for name := range layer.values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			value := layer.values[name]
+			field := weights.field(name)
+			if field == nil {
+				return Weights{}, nil, fmt.Errorf("unknown %s weight %q (valid: %s)", layer.source, name, strings.Join(weightNames, ", "))
+			}
+			if value < 0 {
+				return Weights{}, nil, fmt.Errorf("%s weight %q must not be negative", layer.source, name)
+			}
+			*field = value
+			provenance[name] = WeightProvenance{Value: value, Source: layer.source}
+		}
+	}
+
+	return weights, provenance, nil
+}
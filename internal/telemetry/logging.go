@@ -0,0 +1,115 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Log output formats
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+)
+
+type LogConfig struct {
+	Level  string // debug, info, warn or error
+	Format string // json or text
+}
+
+// Shared by every handler so the level can change at runtime
+var logLevel = new(slog.LevelVar)
+
+// Install a slog default logger writing to w at the configured level. Records
+// logged with a context carry its request attributes and trace IDs, and the
+// standard log package is routed through the same handler.
+func SetupLogging(w io.Writer, config LogConfig) error {
+	if err := SetLogLevel(config.Level); err != nil {
+		return err
+	}
+
+	options := &slog.HandlerOptions{Level: logLevel}
+	var handler slog.Handler
+	switch config.Format {
+	case LogFormatJSON, "":
+		handler = slog.NewJSONHandler(w, options)
+	case LogFormatText:
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return fmt.Errorf("unknown log format %q (valid: %s, %s)", config.Format, LogFormatJSON, LogFormatText)
+	}
+
+	slog.SetDefault(slog.New(contextHandler{handler}))
+	return nil
+}
+
+// Parse a configured log level; empty means info
+func ParseLogLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q (valid: debug, info, warn, error)", level)
+}
+
+// Change the level of the installed logger
+func SetLogLevel(level string) error {
+	parsed, err := ParseLogLevel(level)
+	if err != nil {
+		return err
+	}
+	logLevel.Set(parsed)
+	return nil
+}
+
+// Current level of the installed logger
+func LogLevel() slog.Level {
+	return logLevel.Level()
+}
+
+type logAttrsKey struct{}
+
+// Attach attributes to every record logged with the returned context, e.g.
+// the route of a request or the tool being called
+func WithLogAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	inherited, _ := ctx.Value(logAttrsKey{}).([]slog.Attr)
+	combined := make([]slog.Attr, 0, len(inherited)+len(attrs))
+	combined = append(append(combined, inherited...), attrs...)
+	return context.WithValue(ctx, logAttrsKey{}, combined)
+}
+
+// Adds context attributes and the active trace and span IDs to each record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs, ok := ctx.Value(logAttrsKey{}).([]slog.Attr); ok {
+		record.AddAttrs(attrs...)
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", spanContext.TraceID().String()),
+			slog.String("span_id", spanContext.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
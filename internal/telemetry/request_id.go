@@ -0,0 +1,47 @@
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+)
+
+// Longest client supplied request ID that is honoured
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// Generate a random request ID
+func NewRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// Whether a client supplied request ID is safe to echo into headers and logs:
+// short, and printable ASCII without spaces
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// Carry a request ID, which is also attached to every record logged with the
+// returned context
+func WithRequestID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	return WithLogAttrs(ctx, slog.String("request_id", id))
+}
+
+// Get the request ID carried by ctx, or "" outside a request
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,70 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const defaultServiceName = "akash-provider-intelligence"
+
+type Config struct {
+	Enabled      bool
+	OTLPEndpoint string  // collector host:port for OTLP over gRPC
+	Insecure     bool    // disable TLS to the collector
+	SampleRatio  float64 // fraction of new traces sampled; zero samples everything
+	ServiceName  string
+}
+
+// Install a global tracer provider that exports spans to an OTLP collector.
+// When tracing is disabled the default no-op provider is left in place, so
+// instrumented code costs next to nothing. The returned function flushes and
+// stops the exporter.
+func SetupTracing(ctx context.Context, config Config) (func(context.Context) error, error) {
+	// W3C trace context is honoured either way, so a caller's trace ID still
+	// reaches logs and X-Trace-Id when this server exports nothing
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !config.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(config.OTLPEndpoint)}
+	if config.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	serviceName := config.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+
+	ratio := config.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+		// Honour the caller's sampling decision, sample new traces by ratio
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}